@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -11,16 +11,29 @@ import (
 	"sync/atomic"
 	"time"
 
+	"myproject/backend/transport"
+
 	serial "go.bug.st/serial.v1"
 )
 
 type AuthService struct {
+	ctx         context.Context
 	currentPort serial.Port
 	portName    string
+	profile     SerialProfile
 	mu          sync.Mutex
 	readChan    chan AuthEvent
 	stopRead    chan struct{}
+	stopWatch   chan struct{}
 	isReading   atomic.Bool
+	hub         transport.Hub
+	session     transport.Session
+	state       ConnectionState
+	lastError   string
+	attempts    int
+	autoConnect bool
+	recorder    atomic.Pointer[transport.Recorder]
+	detach      func()
 }
 
 type AuthEvent struct {
@@ -29,94 +42,116 @@ type AuthEvent struct {
 }
 
 func NewAuthService() *AuthService {
-	return &AuthService{}
+	return &AuthService{
+		readChan:    make(chan AuthEvent, 100),
+		state:       StateDisconnected,
+		autoConnect: true,
+	}
+}
+
+// setContext lưu context của Wails để phát sự kiện trạng thái kết nối
+func (a *AuthService) setContext(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ctx = ctx
 }
 
-func (a *AuthService) ListPorts() ([]string, error) {
-	ports, err := serial.GetPortsList()
+// openPort mở cổng COM theo profile và đặt trạng thái DTR/RTS
+func openPort(portName string, profile SerialProfile) (serial.Port, error) {
+	mode, err := profile.mode()
 	if err != nil {
 		return nil, err
 	}
-	if len(ports) == 0 {
-		return nil, errors.New("không tìm thấy cổng COM nào")
+
+	port, err := serial.Open(portName, mode)
+	if err != nil {
+		return nil, fmt.Errorf("kết nối %s thất bại: %w", portName, err)
+	}
+
+	if err := profile.applyModemLines(port); err != nil {
+		port.Close()
+		return nil, fmt.Errorf("kết nối %s thất bại: %w", portName, err)
 	}
-	// fmt.Println("Các cổng COM tìm thấy:", ports)
-	return ports, nil
+	return port, nil
 }
 
-func (a *AuthService) ConnectToPort(portName string) error {
+// ConnectToPort mở cổng COM với profile đường truyền cho trước.
+// Các trường để trống của profile lấy theo mặc định 115200 8N1.
+func (a *AuthService) ConnectToPort(portName string, profile SerialProfile) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	if a.currentPort != nil {
 		return errors.New("đã kết nối, vui lòng ngắt trước")
 	}
+	a.stopWatching()
 
-	mode := &serial.Mode{
-		BaudRate: 115200,
-		DataBits: 8,
-		Parity:   serial.NoParity,
-		StopBits: serial.OneStopBit,
-	}
+	profile = profile.withDefaults()
+	a.portName = portName
+	a.profile = profile
+	a.attempts = 0
+	a.setState(StateConnecting, nil)
 
-	port, err := serial.Open(portName, mode)
+	port, err := openPort(portName, profile)
 	if err != nil {
-		return fmt.Errorf("kết nối %s thất bại: %w", portName, err)
+		a.portName = ""
+		a.profile = SerialProfile{}
+		a.setState(StateDisconnected, err)
+		return err
 	}
 
+	a.session.Reset()
+	a.attachPort(port)
+
+	fmt.Printf("Đã kết nối COM: %s (%s)\n", portName, profile.Name)
+	return nil
+}
+
+// attachPort gắn cổng vừa mở vào service và bắt đầu đọc. Gọi khi đang giữ a.mu.
+func (a *AuthService) attachPort(port serial.Port) {
 	a.currentPort = port
-	a.portName = portName
-	a.readChan = make(chan AuthEvent, 100)
 	a.stopRead = make(chan struct{})
+	a.isReading.Store(true)
+	go a.readLoop(port, a.portName, a.stopRead)
 
-	if !a.isReading.Load() {
-		go a.readLoop()
-		a.isReading.Store(true)
-	}
-
-	fmt.Printf("Đã kết nối COM: %s\n", portName)
-	return nil
+	a.setState(StateConnected, nil)
 }
 
-func (a *AuthService) readLoop() {
-	log.Printf("Bắt đầu đọc COM: %s\n", a.portName)
-	defer log.Printf("Dừng đọc COM: %s\n", a.portName)
+func (a *AuthService) readLoop(port serial.Port, portName string, stop chan struct{}) {
+	log.Printf("Bắt đầu đọc COM: %s\n", portName)
+	defer log.Printf("Dừng đọc COM: %s\n", portName)
 
 	buf := make([]byte, 128)
 	var buffer []byte
 
 	for {
 		select {
-		case <-a.stopRead:
+		case <-stop:
 			return
 		default:
-			var port serial.Port
-
-			a.mu.Lock()
-			if a.currentPort == nil {
-				a.mu.Unlock()
-				time.Sleep(100 * time.Millisecond)
-				continue
-			}
-			port = a.currentPort
-			a.mu.Unlock()
-
 			n, err := port.Read(buf)
 
 			if err != nil {
+				select {
+				case <-stop:
+					// Cổng bị đóng chủ động bởi Disconnect
+					return
+				default:
+				}
+
 				// Kiểm tra các lỗi dự kiến khi ngắt kết nối
 				// Đặc biệt là lỗi "aborted" từ thư viện serial khi cổng đóng
 				if errors.Is(err, io.EOF) ||
 					strings.Contains(err.Error(), "aborted") ||
 					strings.Contains(err.Error(), "disconnected") ||
 					strings.Contains(err.Error(), "The handle is invalid") { // Thêm lỗi "handle is invalid" cho Windows
-					log.Printf("Lỗi đọc COM %s (ngắt kết nối hoặc cổng không hợp lệ): %v", a.portName, err)
-					// Đây là lỗi mong đợi khi ngắt kết nối, không gửi vào kênh lỗi
-					return // Thoát khỏi readLoop
+					log.Printf("Lỗi đọc COM %s (ngắt kết nối hoặc cổng không hợp lệ): %v", portName, err)
+				} else {
+					// Các lỗi khác không mong muốn thì gửi vào kênh lỗi
+					log.Printf("Lỗi đọc COM %s không mong muốn: %v", portName, err)
+					a.pushEvent(AuthEvent{Err: err})
 				}
-				// Các lỗi khác không mong muốn thì gửi vào kênh lỗi và thoát
-				log.Printf("Lỗi đọc COM %s không mong muốn: %v", a.portName, err)
-				a.readChan <- AuthEvent{Err: err} // <--- CHỈ GỬI LỖI NẾU KHÔNG PHẢI LỖI DO NGẮT KẾT NỐI
+				a.portLost(port, err)
 				return
 			}
 
@@ -133,7 +168,7 @@ func (a *AuthService) readLoop() {
 					if newlineIndex == -1 {
 						break
 					}
-					a.readChan <- AuthEvent{Data: string(buffer[:newlineIndex+1])}
+					a.receive(portName, string(buffer[:newlineIndex+1]))
 					buffer = buffer[newlineIndex+1:]
 				}
 			} else {
@@ -143,13 +178,61 @@ func (a *AuthService) readLoop() {
 	}
 }
 
+// receive đưa một dòng nhận được vào đường nhận: phát cho subscriber và readChan
+func (a *AuthService) receive(portName, line string) {
+	if trimmed := strings.TrimRight(line, "\r\n"); trimmed != "" {
+		a.hub.Publish(transport.Frame{Kind: transport.KindSerial, Endpoint: portName, Data: trimmed, Time: time.Now()})
+	}
+	a.pushEvent(AuthEvent{Data: line})
+}
+
+// inject đưa một dòng vào đường nhận như thể đọc được từ cổng COM (dùng khi phát lại capture)
+func (a *AuthService) inject(endpoint, line string) {
+	a.receive(endpoint, line+"\n")
+}
+
+// pushEvent đưa dữ liệu vào readChan cho GetResponse. Khi frontend nghe qua
+// Wails events thì không ai đọc readChan, nên bỏ dòng cũ nhất thay vì block readLoop.
+func (a *AuthService) pushEvent(event AuthEvent) {
+	for {
+		select {
+		case a.readChan <- event:
+			return
+		default:
+			select {
+			case <-a.readChan:
+			default:
+			}
+		}
+	}
+}
+
+// GetCurrentPort trả về cổng COM đang kết nối, rỗng nếu chưa kết nối hoặc đã mất kết nối
 func (a *AuthService) GetCurrentPort() string {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.state != StateConnected {
+		return ""
+	}
 	return a.portName
 }
 
+// setRecorder ghi lại mọi frame gửi/nhận qua cổng COM vào recorder (nil để tắt)
+func (a *AuthService) setRecorder(recorder *transport.Recorder) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.detach != nil {
+		a.detach()
+		a.detach = nil
+	}
+	a.recorder.Store(recorder)
+	if recorder != nil {
+		a.detach = recorder.Attach(&a.hub)
+	}
+}
+
 func (a *AuthService) GetResponse(timeout time.Duration) (string, error) {
 	select {
 	case msg := <-a.readChan:
@@ -162,18 +245,28 @@ func (a *AuthService) GetResponse(timeout time.Duration) (string, error) {
 	}
 }
 
-func (a *AuthService) Send(data string) error {
+func (a *AuthService) send(data string) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	if a.currentPort == nil {
+		if a.state == StateLost || a.state == StateReconnecting {
+			return fmt.Errorf("mất kết nối cổng %s, đang chờ kết nối lại", a.portName)
+		}
 		return errors.New("chưa kết nối cổng COM")
 	}
+	if a.profile.FlowControl == FlowRTSCTS {
+		if err := waitCTS(a.currentPort); err != nil {
+			return fmt.Errorf("lỗi khi gửi: %w", err)
+		}
+	}
 	_, err := a.currentPort.Write([]byte(data + "\n"))
 	if err != nil {
 		return fmt.Errorf("lỗi khi gửi: %w", err)
 	}
 
+	a.session.Record(data)
+	a.recorder.Load().Record(transport.DirectionTX, transport.Frame{Kind: transport.KindSerial, Endpoint: a.portName, Data: data, Time: time.Now()})
 	return nil
 }
 
@@ -181,6 +274,7 @@ func (a *AuthService) Disconnect() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	a.stopWatching()
 	if a.currentPort != nil {
 		if a.isReading.Load() {
 			close(a.stopRead)
@@ -188,80 +282,62 @@ func (a *AuthService) Disconnect() error {
 		}
 		a.currentPort.Close()
 		a.currentPort = nil
-		a.portName = ""
 		fmt.Println("Đã ngắt kết nối")
 	}
+	a.portName = ""
+	a.profile = SerialProfile{}
+	a.session.Reset()
+	a.setState(StateDisconnected, nil)
 	return nil
 }
 
-func (a *AuthService) Logout() error {
-	// Tạo JSON logout request
-	logoutData := map[string]string{
-		"type": "logout",
-	}
-	jsonBytes, err := json.Marshal(logoutData)
-	if err != nil {
-		return fmt.Errorf("lỗi khi tạo JSON đăng xuất: %w", err)
-	}
-
-	return a.Send(string(jsonBytes))
+// SerialTransport là đường truyền cổng COM của AuthService cho các service backend
+// (device, events, capture): gửi/nhận frame, phát lại capture, ghi capture và context của Wails
+type SerialTransport interface {
+	transport.Transport
+	// Inject đưa một dòng vào đường nhận như thể đọc được từ cổng COM (dùng khi phát lại capture)
+	Inject(endpoint, line string)
+	// SetRecorder ghi lại mọi frame gửi/nhận qua cổng COM vào recorder (nil để tắt)
+	SetRecorder(recorder *transport.Recorder)
+	// SetContext lưu context của Wails để phát sự kiện trạng thái kết nối
+	SetContext(ctx context.Context)
 }
 
-func (a *AuthService) Login(username, password string) error {
-	// Tạo JSON login request
-	loginData := map[string]string{
-		"type":     "login",
-		"username": username,
-		"password": password,
-	}
-	jsonBytes, err := json.Marshal(loginData)
-	if err != nil {
-		return fmt.Errorf("lỗi khi tạo JSON đăng nhập: %w", err)
-	}
-
-	return a.Send(string(jsonBytes))
+// Transport trả về đường truyền cổng COM của a. Là hàm của package chứ không phải method
+// để Wails không sinh binding JS cho Send, Subscribe, ... khi bind AuthService.
+func Transport(a *AuthService) SerialTransport {
+	return &serialTransport{service: a}
 }
 
-func (a *AuthService) ChangePassword(oldPassword, newPassword string) error {
-	changeData := map[string]string{
-		"type":         "change_password",
-		"old_password": oldPassword,
-		"new_password": newPassword,
-	}
-
-	jsonBytes, err := json.Marshal(changeData)
-	if err != nil {
-		return fmt.Errorf("lỗi khi mã hóa JSON: %w", err)
-	}
+// serialTransport gắn AuthService với interface Transport
+type serialTransport struct {
+	service *AuthService
+}
 
-	return a.Send(string(jsonBytes))
+func (t *serialTransport) Send(data string) error {
+	return t.service.send(data)
 }
 
-func (a *AuthService) AddUser(username, password string) error {
-	data := map[string]string{
-		"type":     "add_user",
-		"username": username,
-		"password": password,
-	}
+func (t *serialTransport) Kind() string {
+	return transport.KindSerial
+}
 
-	jsonBytes, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("lỗi khi tạo JSON: %w", err)
-	}
+func (t *serialTransport) Endpoint() string {
+	return t.service.GetCurrentPort()
+}
 
-	return a.Send(string(jsonBytes))
+func (t *serialTransport) Subscribe(handler func(transport.Frame)) func() {
+	return t.service.hub.Subscribe(handler)
 }
 
-func (a *AuthService) RemoveUser(username string) error {
-	data := map[string]string{
-		"type":     "remove_user",
-		"username": username,
-	}
+func (t *serialTransport) Inject(endpoint, line string) {
+	t.service.inject(endpoint, line)
+}
 
-	jsonBytes, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("lỗi khi tạo JSON: %w", err)
-	}
+func (t *serialTransport) SetRecorder(recorder *transport.Recorder) {
+	t.service.setRecorder(recorder)
+}
 
-	return a.Send(string(jsonBytes))
+func (t *serialTransport) SetContext(ctx context.Context) {
+	t.service.setContext(ctx)
 }
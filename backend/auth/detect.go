@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	serial "go.bug.st/serial.v1"
+	"go.bug.st/serial.v1/enumerator"
+)
+
+// defaultDetectTimeout là thời gian chờ phản hồi read_system_info trên mỗi cổng
+const defaultDetectTimeout = 1500 * time.Millisecond
+
+// detectProbe là lệnh gửi thử để nhận biết datalogger
+const detectProbe = `{"type":"read_system_info"}` + "\n"
+
+// PortInfo là thông tin chi tiết của một cổng COM
+type PortInfo struct {
+	Name         string `json:"name"`
+	IsUSB        bool   `json:"isUsb"`
+	VID          string `json:"vid"`
+	PID          string `json:"pid"`
+	SerialNumber string `json:"serialNumber"`
+	Product      string `json:"product"`
+}
+
+// DetectResult là kết quả thử một cổng trong AutoDetect
+type DetectResult struct {
+	Port     PortInfo `json:"port"`
+	IsLogger bool     `json:"isLogger"`
+	Info     string   `json:"info,omitempty"` // chuỗi read_system_info thiết bị trả về
+	Error    string   `json:"error,omitempty"`
+}
+
+// ListPorts trả về danh sách cổng COM kèm VID/PID, serial và tên sản phẩm USB nếu có
+func (a *AuthService) ListPorts() ([]PortInfo, error) {
+	names, err := serial.GetPortsList()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("không tìm thấy cổng COM nào")
+	}
+
+	// Thông tin chi tiết không có trên mọi hệ điều hành, lỗi thì chỉ trả về tên cổng
+	details := make(map[string]*enumerator.PortDetails)
+	if list, err := enumerator.GetDetailedPortsList(); err == nil {
+		for _, d := range list {
+			if d != nil && d.Name != "" {
+				details[d.Name] = d
+			}
+		}
+	}
+
+	ports := make([]PortInfo, 0, len(names))
+	for _, name := range names {
+		info := PortInfo{Name: name}
+		if d, ok := details[name]; ok {
+			info.IsUSB = d.IsUSB
+			info.VID = strings.ToUpper(d.VID)
+			info.PID = strings.ToUpper(d.PID)
+			info.SerialNumber = d.SerialNumber
+		}
+		if info.IsUSB {
+			info.Product = usbProduct(name)
+		}
+		ports = append(ports, info)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+	return ports, nil
+}
+
+// AutoDetect mở thử từng cổng COM với profile cho trước, gửi read_system_info
+// và báo cổng nào trả lời như một datalogger. Cổng đang kết nối không bị mở lại.
+func (a *AuthService) AutoDetect(profile SerialProfile, timeoutMs int) ([]DetectResult, error) {
+	ports, err := a.ListPorts()
+	if err != nil {
+		return nil, err
+	}
+
+	profile = profile.withDefaults()
+	if _, err := profile.mode(); err != nil {
+		return nil, err
+	}
+	timeout := defaultDetectTimeout
+	if timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+
+	a.mu.Lock()
+	current := ""
+	if a.currentPort != nil {
+		current = a.portName
+	}
+	a.mu.Unlock()
+
+	results := make([]DetectResult, len(ports))
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		results[i].Port = port
+		if port.Name == current {
+			results[i].IsLogger = true
+			results[i].Info = "đang kết nối"
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *DetectResult) {
+			defer wg.Done()
+			info, err := probePort(result.Port.Name, profile, timeout)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.IsLogger = true
+			result.Info = info
+		}(&results[i])
+	}
+	wg.Wait()
+
+	// Cổng có datalogger lên đầu
+	sort.SliceStable(results, func(i, j int) bool { return results[i].IsLogger && !results[j].IsLogger })
+	return results, nil
+}
+
+// probePort mở cổng, gửi read_system_info và chờ phản hồi đúng loại
+func probePort(portName string, profile SerialProfile, timeout time.Duration) (string, error) {
+	port, err := openPort(portName, profile)
+	if err != nil {
+		return "", err
+	}
+
+	replies := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(port)
+		for scanner.Scan() {
+			var reply struct {
+				Type string `json:"type"`
+				Data string `json:"data"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &reply) == nil && reply.Type == "read_system_info" {
+				replies <- reply.Data
+				return
+			}
+		}
+	}()
+	// Đóng cổng cũng làm Read đang chờ trả về, goroutine đọc sẽ kết thúc
+	defer port.Close()
+
+	if _, err := port.Write([]byte(detectProbe)); err != nil {
+		return "", fmt.Errorf("không thể gửi lệnh thử tới %s: %w", portName, err)
+	}
+
+	select {
+	case info := <-replies:
+		return info, nil
+	case <-time.After(timeout):
+		return "", fmt.Errorf("%s không phản hồi như datalogger", portName)
+	}
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// usbProduct đọc tên sản phẩm USB của cổng từ sysfs.
+// Bộ enumerator của thư viện serial chưa trả về trường này.
+func usbProduct(portName string) string {
+	devicePath, err := filepath.EvalSymlinks(filepath.Join("/sys/class/tty", filepath.Base(portName), "device"))
+	if err != nil {
+		return ""
+	}
+
+	// Đi ngược lên cây thiết bị tới nút USB có file product
+	for dir := devicePath; dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+		data, err := os.ReadFile(filepath.Join(dir, "product"))
+		if err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
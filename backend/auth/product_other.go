@@ -0,0 +1,8 @@
+//go:build !linux
+
+package auth
+
+// usbProduct chưa hỗ trợ trên hệ điều hành này
+func usbProduct(portName string) string {
+	return ""
+}
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	serial "go.bug.st/serial.v1"
+)
+
+// Các chế độ điều khiển luồng
+const (
+	FlowNone   = "none"
+	FlowRTSCTS = "rtscts"
+)
+
+// profilePath là file lưu các profile cổng COM người dùng tự đặt
+var profilePath = filepath.Join(".", "config", "serial_profiles.json")
+
+// ctsTimeout là thời gian chờ CTS khi dùng điều khiển luồng RTS/CTS
+const ctsTimeout = time.Second
+
+// SerialProfile là bộ thông số đường truyền cho cổng COM
+type SerialProfile struct {
+	Name        string `json:"name"`
+	BaudRate    int    `json:"baudrate"`
+	DataBits    int    `json:"databits"`
+	Parity      string `json:"parity"`        // "N", "E", "O", "M", "S" (giống rtu_master.parity)
+	StopBits    int    `json:"stopbits"`      // 1 hoặc 2
+	FlowControl string `json:"flowcontrol"`   // FlowNone hoặc FlowRTSCTS
+	DTR         *bool  `json:"dtr,omitempty"` // trạng thái DTR ban đầu, nil để giữ nguyên
+	RTS         *bool  `json:"rts,omitempty"` // trạng thái RTS ban đầu, nil để giữ nguyên
+}
+
+// builtinProfiles là các profile có sẵn, không xóa được
+var builtinProfiles = []SerialProfile{
+	{Name: "115200 8N1", BaudRate: 115200, DataBits: 8, Parity: "N", StopBits: 1, FlowControl: FlowNone},
+	{Name: "57600 8N1", BaudRate: 57600, DataBits: 8, Parity: "N", StopBits: 1, FlowControl: FlowNone},
+	{Name: "19200 8E1", BaudRate: 19200, DataBits: 8, Parity: "E", StopBits: 1, FlowControl: FlowNone},
+	{Name: "9600 8N1", BaudRate: 9600, DataBits: 8, Parity: "N", StopBits: 1, FlowControl: FlowNone},
+	{Name: "115200 8N1 RTS/CTS", BaudRate: 115200, DataBits: 8, Parity: "N", StopBits: 1, FlowControl: FlowRTSCTS},
+}
+
+// DefaultSerialProfile là thông số mặc định của datalogger (115200 8N1)
+func DefaultSerialProfile() SerialProfile {
+	return builtinProfiles[0]
+}
+
+// withDefaults điền các trường còn trống bằng giá trị mặc định
+func (p SerialProfile) withDefaults() SerialProfile {
+	def := DefaultSerialProfile()
+	if p.BaudRate == 0 {
+		p.BaudRate = def.BaudRate
+	}
+	if p.DataBits == 0 {
+		p.DataBits = def.DataBits
+	}
+	if p.Parity == "" {
+		p.Parity = def.Parity
+	}
+	if p.StopBits == 0 {
+		p.StopBits = def.StopBits
+	}
+	if p.FlowControl == "" {
+		p.FlowControl = def.FlowControl
+	}
+	if p.Name == "" {
+		p.Name = fmt.Sprintf("%d %d%s%d", p.BaudRate, p.DataBits, p.Parity, p.StopBits)
+	}
+	return p
+}
+
+// mode chuyển profile thành serial.Mode
+func (p SerialProfile) mode() (*serial.Mode, error) {
+	mode := &serial.Mode{BaudRate: p.BaudRate, DataBits: p.DataBits}
+
+	if p.BaudRate <= 0 {
+		return nil, fmt.Errorf("baudrate không hợp lệ: %d", p.BaudRate)
+	}
+	if p.DataBits < 5 || p.DataBits > 8 {
+		return nil, fmt.Errorf("databits không hợp lệ: %d (5-8)", p.DataBits)
+	}
+
+	switch strings.ToUpper(p.Parity) {
+	case "N":
+		mode.Parity = serial.NoParity
+	case "E":
+		mode.Parity = serial.EvenParity
+	case "O":
+		mode.Parity = serial.OddParity
+	case "M":
+		mode.Parity = serial.MarkParity
+	case "S":
+		mode.Parity = serial.SpaceParity
+	default:
+		return nil, fmt.Errorf("parity không hợp lệ: %s (N, E, O, M, S)", p.Parity)
+	}
+
+	switch p.StopBits {
+	case 1:
+		mode.StopBits = serial.OneStopBit
+	case 2:
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("stopbits không hợp lệ: %d (1 hoặc 2)", p.StopBits)
+	}
+
+	if p.FlowControl != FlowNone && p.FlowControl != FlowRTSCTS {
+		return nil, fmt.Errorf("flowcontrol không hợp lệ: %s (%s hoặc %s)", p.FlowControl, FlowNone, FlowRTSCTS)
+	}
+	return mode, nil
+}
+
+// applyModemLines đặt trạng thái DTR/RTS ban đầu sau khi mở cổng
+func (p SerialProfile) applyModemLines(port serial.Port) error {
+	if p.DTR != nil {
+		if err := port.SetDTR(*p.DTR); err != nil {
+			return fmt.Errorf("không thể đặt DTR: %w", err)
+		}
+	}
+
+	// RTS/CTS: máy tính luôn bật RTS để báo sẵn sàng nhận
+	rts := p.RTS
+	if p.FlowControl == FlowRTSCTS {
+		enabled := true
+		rts = &enabled
+	}
+	if rts != nil {
+		if err := port.SetRTS(*rts); err != nil {
+			return fmt.Errorf("không thể đặt RTS: %w", err)
+		}
+	}
+	return nil
+}
+
+// waitCTS chờ thiết bị bật CTS trước khi gửi (điều khiển luồng RTS/CTS).
+// Thư viện serial không bật CRTSCTS của driver nên bắt tay được làm ở đây.
+func waitCTS(port serial.Port) error {
+	deadline := time.Now().Add(ctsTimeout)
+	for {
+		bits, err := port.GetModemStatusBits()
+		if err != nil {
+			return fmt.Errorf("không đọc được CTS: %w", err)
+		}
+		if bits.CTS {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("thiết bị chưa sẵn sàng nhận (CTS tắt)")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func loadUserProfiles() ([]SerialProfile, error) {
+	data, err := os.ReadFile(profilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("không thể đọc file profile: %w", err)
+	}
+
+	var profiles []SerialProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("file profile không hợp lệ: %w", err)
+	}
+	return profiles, nil
+}
+
+func saveUserProfiles(profiles []SerialProfile) error {
+	if err := os.MkdirAll(filepath.Dir(profilePath), 0755); err != nil {
+		return fmt.Errorf("không thể tạo thư mục cấu hình: %w", err)
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("lỗi khi chuyển thành JSON: %w", err)
+	}
+	if err := os.WriteFile(profilePath, data, 0644); err != nil {
+		return fmt.Errorf("không thể lưu file profile: %w", err)
+	}
+	return nil
+}
+
+func isBuiltinProfile(name string) bool {
+	for _, p := range builtinProfiles {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSerialProfiles trả về các profile có sẵn và profile người dùng đã lưu
+func (a *AuthService) ListSerialProfiles() ([]SerialProfile, error) {
+	userProfiles, err := loadUserProfiles()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(userProfiles, func(i, j int) bool { return userProfiles[i].Name < userProfiles[j].Name })
+
+	profiles := append([]SerialProfile{}, builtinProfiles...)
+	return append(profiles, userProfiles...), nil
+}
+
+// SaveSerialProfile lưu (hoặc ghi đè) một profile theo tên
+func (a *AuthService) SaveSerialProfile(profile SerialProfile) error {
+	profile = profile.withDefaults()
+	if isBuiltinProfile(profile.Name) {
+		return fmt.Errorf("không thể ghi đè profile có sẵn '%s'", profile.Name)
+	}
+	if _, err := profile.mode(); err != nil {
+		return err
+	}
+
+	profiles, err := loadUserProfiles()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range profiles {
+		if profiles[i].Name == profile.Name {
+			profiles[i] = profile
+			replaced = true
+		}
+	}
+	if !replaced {
+		profiles = append(profiles, profile)
+	}
+	return saveUserProfiles(profiles)
+}
+
+// DeleteSerialProfile xóa profile người dùng theo tên
+func (a *AuthService) DeleteSerialProfile(name string) error {
+	if isBuiltinProfile(name) {
+		return fmt.Errorf("không thể xóa profile có sẵn '%s'", name)
+	}
+
+	profiles, err := loadUserProfiles()
+	if err != nil {
+		return err
+	}
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return saveUserProfiles(append(profiles[:i], profiles[i+1:]...))
+		}
+	}
+	return fmt.Errorf("profile '%s' không tồn tại", name)
+}
+
+// GetCurrentProfile trả về profile của cổng COM đang kết nối
+func (a *AuthService) GetCurrentProfile() SerialProfile {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.profile
+}
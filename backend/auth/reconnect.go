@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"log"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	serial "go.bug.st/serial.v1"
+)
+
+// ConnectionState là trạng thái kết nối cổng COM
+type ConnectionState string
+
+const (
+	StateDisconnected ConnectionState = "disconnected"
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+	StateLost         ConnectionState = "lost"
+	StateReconnecting ConnectionState = "reconnecting"
+)
+
+// StateEvent là tên sự kiện Wails phát khi trạng thái cổng COM thay đổi
+const StateEvent = "serial:state"
+
+// portPollInterval là chu kỳ quét danh sách cổng khi chờ cắm lại thiết bị
+const portPollInterval = time.Second
+
+// SerialStatus mô tả trạng thái kết nối cổng COM cho frontend
+type SerialStatus struct {
+	State    ConnectionState `json:"state"`
+	Port     string          `json:"port"`
+	Profile  string          `json:"profile"`
+	Error    string          `json:"error,omitempty"`
+	Attempts int             `json:"attempts"`
+	Streams  []string        `json:"streams"`
+}
+
+// status tạo SerialStatus từ trạng thái hiện tại. Gọi khi đang giữ a.mu.
+func (a *AuthService) status() SerialStatus {
+	return SerialStatus{
+		State:    a.state,
+		Port:     a.portName,
+		Profile:  a.profile.Name,
+		Error:    a.lastError,
+		Attempts: a.attempts,
+		Streams:  a.session.Streams(),
+	}
+}
+
+// setState đổi trạng thái và phát sự kiện StateEvent. Gọi khi đang giữ a.mu.
+func (a *AuthService) setState(state ConnectionState, err error) {
+	a.state = state
+	a.lastError = ""
+	if err != nil {
+		a.lastError = err.Error()
+	}
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, StateEvent, a.status())
+	}
+}
+
+// GetConnectionStatus trả về trạng thái kết nối cổng COM hiện tại
+func (a *AuthService) GetConnectionStatus() SerialStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.status()
+}
+
+// SetAutoReconnect bật/tắt tự động kết nối lại khi rút/cắm cáp USB
+func (a *AuthService) SetAutoReconnect(enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.autoConnect = enabled
+	if !enabled && (a.state == StateLost || a.state == StateReconnecting) {
+		a.stopWatching()
+	}
+}
+
+// stopWatching dừng goroutine chờ cắm lại cổng. Gọi khi đang giữ a.mu.
+func (a *AuthService) stopWatching() {
+	if a.stopWatch != nil {
+		close(a.stopWatch)
+		a.stopWatch = nil
+	}
+}
+
+// portLost được readLoop gọi khi cổng đang dùng bị lỗi (thường là rút cáp USB)
+func (a *AuthService) portLost(port serial.Port, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.currentPort != port {
+		// Cổng đã được thay hoặc đã ngắt chủ động
+		return
+	}
+
+	port.Close()
+	a.currentPort = nil
+	a.isReading.Store(false)
+	a.setState(StateLost, err)
+
+	if a.autoConnect {
+		a.stopWatch = make(chan struct{})
+		go a.watchReconnect(a.portName, a.profile, a.stopWatch)
+	}
+}
+
+// watchReconnect quét danh sách cổng, mở lại đúng cổng cũ khi nó xuất hiện
+// rồi gửi lại login và các luồng dữ liệu đang bật
+func (a *AuthService) watchReconnect(portName string, profile SerialProfile, stop chan struct{}) {
+	ticker := time.NewTicker(portPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if !portPresent(portName) {
+			continue
+		}
+
+		a.mu.Lock()
+		if a.stopWatch != stop {
+			a.mu.Unlock()
+			return
+		}
+		a.attempts++
+		a.setState(StateReconnecting, nil)
+		a.mu.Unlock()
+
+		port, err := openPort(portName, profile)
+
+		a.mu.Lock()
+		if a.stopWatch != stop {
+			// Người dùng đã ngắt kết nối trong lúc đang mở cổng
+			a.mu.Unlock()
+			if port != nil {
+				port.Close()
+			}
+			return
+		}
+		if err != nil {
+			log.Printf("Kết nối lại COM %s thất bại: %v", portName, err)
+			a.setState(StateLost, err)
+			a.mu.Unlock()
+			continue
+		}
+
+		a.stopWatch = nil
+		a.attachPort(port)
+		replay := a.session.Replay()
+		a.mu.Unlock()
+
+		log.Printf("Đã kết nối lại COM: %s", portName)
+		for _, line := range replay {
+			if err := a.send(line); err != nil {
+				log.Printf("Không thể gửi lại lệnh sau khi kết nối lại COM %s: %v", portName, err)
+				break
+			}
+		}
+		return
+	}
+}
+
+// portPresent kiểm tra cổng có trong danh sách cổng của hệ điều hành không
+func portPresent(portName string) bool {
+	ports, err := serial.GetPortsList()
+	if err != nil {
+		return false
+	}
+	for _, p := range ports {
+		if p == portName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"myproject/backend/transport"
+)
+
+// captureDir là thư mục mặc định chứa các file capture
+var captureDir = filepath.Join(".", "captures")
+
+// Options là tham số bắt đầu ghi capture
+type Options struct {
+	Path      string `json:"path"`      // rỗng để tự đặt tên trong thư mục captures
+	MaxSizeMB int    `json:"maxSizeMB"` // dung lượng mỗi file trước khi xoay, 0 để dùng mặc định
+	MaxFiles  int    `json:"maxFiles"`  // số file giữ lại kể cả file đang ghi, 0 để dùng mặc định
+}
+
+// FileInfo là một file capture có sẵn trên đĩa
+type FileInfo struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Modified string `json:"modified"`
+}
+
+// CaptureService cho frontend bật/tắt ghi lưu lượng giao thức (TX/RX) ra file JSONL
+// để gửi kèm khi báo lỗi cho nhóm firmware,
+// và phát lại capture vào đường nhận để tái hiện lỗi tại bàn làm việc
+type CaptureService struct {
+	ctx      context.Context
+	mu       sync.Mutex
+	recorder *transport.Recorder
+	sinks    map[string]Sink
+	replay   *replay
+}
+
+// NewCaptureService khởi tạo CaptureService với recorder dùng chung của các đường truyền
+// và đường nhận của cổng COM (serial) và socket (sockets) để phát lại
+func NewCaptureService(recorder *transport.Recorder, serial, sockets Sink) *CaptureService {
+	return &CaptureService{
+		recorder: recorder,
+		sinks: map[string]Sink{
+			transport.KindSerial: serial,
+			transport.KindTCP:    sockets,
+		},
+	}
+}
+
+// StartCapture bắt đầu ghi mọi frame gửi/nhận qua cổng COM và socket
+func (c *CaptureService) StartCapture(options Options) (transport.CaptureStatus, error) {
+	path := options.Path
+	if path == "" {
+		path = filepath.Join(captureDir, fmt.Sprintf("capture-%s.jsonl", time.Now().Format("20060102-150405")))
+	}
+	if options.MaxSizeMB < 0 || options.MaxFiles < 0 {
+		return c.recorder.Status(), fmt.Errorf("giới hạn file capture không được âm")
+	}
+
+	if err := c.recorder.Start(path, int64(options.MaxSizeMB)<<20, options.MaxFiles); err != nil {
+		return c.recorder.Status(), err
+	}
+	return c.recorder.Status(), nil
+}
+
+// StopCapture dừng ghi capture
+func (c *CaptureService) StopCapture() (transport.CaptureStatus, error) {
+	if err := c.recorder.Stop(); err != nil {
+		return c.recorder.Status(), err
+	}
+	return c.recorder.Status(), nil
+}
+
+// GetCaptureStatus trả về trạng thái ghi capture hiện tại
+func (c *CaptureService) GetCaptureStatus() transport.CaptureStatus {
+	return c.recorder.Status()
+}
+
+// ListCaptures liệt kê các file capture trong thư mục mặc định, mới nhất trước
+func (c *CaptureService) ListCaptures() ([]FileInfo, error) {
+	entries, err := os.ReadDir(captureDir)
+	if os.IsNotExist(err) {
+		return []FileInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("không thể đọc thư mục capture: %w", err)
+	}
+
+	files := []FileInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".jsonl") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, FileInfo{
+			Path:     filepath.Join(captureDir, entry.Name()),
+			Size:     info.Size(),
+			Modified: info.ModTime().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Modified > files[j].Modified })
+	return files, nil
+}
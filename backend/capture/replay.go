@@ -0,0 +1,233 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"myproject/backend/transport"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ReplayEvent là tên sự kiện Wails phát khi tiến độ phát lại thay đổi
+const ReplayEvent = "replay:state"
+
+// replayProgressInterval giới hạn tần suất phát ReplayEvent trong lúc phát lại
+const replayProgressInterval = 250 * time.Millisecond
+
+// maxCaptureLine là độ dài tối đa một dòng trong file capture (download_config rất dài)
+const maxCaptureLine = 8 << 20
+
+// Sink là đường nhận của một loại kết nối (auth.Transport, SocketManager)
+type Sink interface {
+	Inject(endpoint, line string)
+}
+
+// ReplayOptions là tham số phát lại một file capture
+type ReplayOptions struct {
+	Path      string  `json:"path"`
+	Speed     float64 `json:"speed"`     // 1 là thời gian thực, 2 là nhanh gấp đôi, 0 là phát ngay không chờ
+	Transport string  `json:"transport"` // rỗng để giữ loại kết nối trong capture, hoặc "serial"/"tcp"
+	Endpoint  string  `json:"endpoint"`  // rỗng để giữ endpoint trong capture, ví dụ "COM7" hoặc "192.168.1.10:19981"
+	MaxGapMs  int     `json:"maxGapMs"`  // thời gian chờ tối đa giữa hai frame, 0 là không giới hạn
+}
+
+// ReplayStatus mô tả tiến độ phát lại
+type ReplayStatus struct {
+	Active     bool    `json:"active"`
+	Path       string  `json:"path"`
+	Speed      float64 `json:"speed"`
+	Total      int     `json:"total"`      // số frame RX trong capture
+	Sent       int     `json:"sent"`       // số frame RX đã phát
+	PositionMs int64   `json:"positionMs"` // vị trí hiện tại tính từ đầu capture
+	DurationMs int64   `json:"durationMs"` // độ dài capture
+	Error      string  `json:"error,omitempty"`
+}
+
+// replay là một lần phát lại đang chạy
+type replay struct {
+	stop   chan struct{}
+	status ReplayStatus
+}
+
+// SetContext lưu context của Wails để phát sự kiện tiến độ phát lại
+func (c *CaptureService) SetContext(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ctx = ctx
+}
+
+// StartReplay phát lại phía thiết bị (các frame RX) của file capture vào đường nhận
+// của ứng dụng, như thể đang có datalogger thật kết nối
+func (c *CaptureService) StartReplay(options ReplayOptions) (ReplayStatus, error) {
+	if options.Speed < 0 {
+		return ReplayStatus{}, fmt.Errorf("tốc độ phát lại không hợp lệ: %v", options.Speed)
+	}
+	switch options.Transport {
+	case "", transport.KindSerial, transport.KindTCP:
+	default:
+		return ReplayStatus{}, fmt.Errorf("loại kết nối không hợp lệ: %s", options.Transport)
+	}
+
+	entries, err := LoadCapture(options.Path)
+	if err != nil {
+		return ReplayStatus{}, err
+	}
+
+	var rx []transport.CaptureEntry
+	for _, entry := range entries {
+		if entry.Direction == transport.DirectionRX {
+			if options.Transport != "" {
+				entry.Transport = options.Transport
+			}
+			if options.Endpoint != "" {
+				entry.Endpoint = options.Endpoint
+			}
+			if c.sinks[entry.Transport] == nil {
+				return ReplayStatus{}, fmt.Errorf("không phát lại được frame loại %q", entry.Transport)
+			}
+			rx = append(rx, entry)
+		}
+	}
+	if len(rx) == 0 {
+		return ReplayStatus{}, fmt.Errorf("file capture %s không có frame nhận nào", options.Path)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replay != nil && c.replay.status.Active {
+		return c.replay.status, fmt.Errorf("đang phát lại %s", c.replay.status.Path)
+	}
+	c.replay = &replay{
+		stop: make(chan struct{}),
+		status: ReplayStatus{
+			Active:     true,
+			Path:       options.Path,
+			Speed:      options.Speed,
+			Total:      len(rx),
+			DurationMs: rx[len(rx)-1].Time.Sub(rx[0].Time).Milliseconds(),
+		},
+	}
+	go c.runReplay(c.replay, rx, options)
+	return c.replay.status, nil
+}
+
+// StopReplay dừng phát lại
+func (c *CaptureService) StopReplay() ReplayStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replay == nil {
+		return ReplayStatus{}
+	}
+	if c.replay.status.Active {
+		close(c.replay.stop)
+		c.replay.status.Active = false
+	}
+	return c.replay.status
+}
+
+// GetReplayStatus trả về tiến độ phát lại hiện tại
+func (c *CaptureService) GetReplayStatus() ReplayStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replay == nil {
+		return ReplayStatus{}
+	}
+	return c.replay.status
+}
+
+// runReplay phát các frame theo khoảng cách thời gian trong capture chia cho tốc độ
+func (c *CaptureService) runReplay(r *replay, rx []transport.CaptureEntry, options ReplayOptions) {
+	maxGap := time.Duration(options.MaxGapMs) * time.Millisecond
+	lastEmit := time.Time{}
+
+	for i, entry := range rx {
+		if i > 0 && options.Speed > 0 {
+			gap := time.Duration(float64(entry.Time.Sub(rx[i-1].Time)) / options.Speed)
+			if maxGap > 0 && gap > maxGap {
+				gap = maxGap
+			}
+			if gap > 0 {
+				select {
+				case <-r.stop:
+					c.emitReplay(r)
+					return
+				case <-time.After(gap):
+				}
+			}
+		}
+
+		select {
+		case <-r.stop:
+			c.emitReplay(r)
+			return
+		default:
+		}
+
+		c.sinks[entry.Transport].Inject(entry.Endpoint, entry.Data)
+
+		c.mu.Lock()
+		r.status.Sent = i + 1
+		r.status.PositionMs = entry.Time.Sub(rx[0].Time).Milliseconds()
+		c.mu.Unlock()
+
+		if time.Since(lastEmit) >= replayProgressInterval {
+			lastEmit = time.Now()
+			c.emitReplay(r)
+		}
+	}
+
+	c.mu.Lock()
+	if r.status.Active {
+		close(r.stop)
+		r.status.Active = false
+	}
+	c.mu.Unlock()
+	c.emitReplay(r)
+}
+
+// emitReplay phát ReplayEvent cho frontend
+func (c *CaptureService) emitReplay(r *replay) {
+	c.mu.Lock()
+	ctx := c.ctx
+	status := r.status
+	c.mu.Unlock()
+
+	if ctx != nil {
+		runtime.EventsEmit(ctx, ReplayEvent, status)
+	}
+}
+
+// LoadCapture đọc toàn bộ file capture JSONL theo thứ tự ghi
+func LoadCapture(path string) ([]transport.CaptureEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("không thể mở file capture: %w", err)
+	}
+	defer file.Close()
+
+	var entries []transport.CaptureEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxCaptureLine)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry transport.CaptureEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("dòng %d của file capture không hợp lệ: %w", lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("lỗi khi đọc file capture: %w", err)
+	}
+	return entries, nil
+}
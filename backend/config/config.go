@@ -0,0 +1,60 @@
+// Package config là model có kiểu cho file cấu hình datalogger (test.json).
+// Mọi section đều giải mã/mã hóa JSON không mất dữ liệu: trường lạ được giữ nguyên,
+// trường không có trong dữ liệu gốc không bị thêm vào khi ghi lại, trường null vẫn là null
+// nếu chưa được gán giá trị khác.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Config là toàn bộ cấu hình của datalogger
+type Config struct {
+	Common       *Common         `json:"common"`
+	Control      *Control        `json:"control"`
+	Ais          []AnalogInput   `json:"ais"`
+	Dis          []DigitalInput  `json:"dis"`
+	Dos          []DigitalOutput `json:"dos"`
+	FTP          []FTP           `json:"ftp"`
+	ModbusReader []ModbusReader  `json:"modbus_reader"`
+	Prog         []Program       `json:"prog"`
+	Timers       []Timer         `json:"timers"`
+	Tags         []Tag           `json:"tags"`
+	RTUMaster    *RTUMaster      `json:"rtu_master"`
+	RTUSlave     *RTUSlave       `json:"rtu_slave"`
+	TCPMaster    *TCPMaster      `json:"tcp_master"`
+	TCPSlave     *TCPSlave       `json:"tcp_slave"`
+	extra
+}
+
+func (v *Config) UnmarshalJSON(data []byte) error {
+	type plain Config
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Config) MarshalJSON() ([]byte, error) {
+	type plain Config
+	return encode(plain(v), v.extra)
+}
+
+// Parse giải mã nội dung file cấu hình
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("cấu hình không hợp lệ: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Format mã hóa cấu hình thành JSON thụt lề 2 dấu cách như SaveJsonFile
+func Format(cfg *Config) ([]byte, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cấu hình trống")
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// sameJSON so sánh hai tài liệu JSON theo giá trị, không theo thứ tự khóa hay định dạng
+func sameJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y interface{}
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestRoundTripFiles(t *testing.T) {
+	files := []string{
+		"../../workspace/default.json",
+		"../workspace/test.json",
+		"../../aaa.json",
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		out, err := Format(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !sameJSON(t, data, out) {
+			t.Errorf("%s: ghi lại khác file gốc", path)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string // rỗng nếu giống in
+	}{
+		{
+			name: "trường vắng mặt không bị thêm vào",
+			in:   `{"common":{"ai_loc":5}}`,
+		},
+		{
+			name: "trường lạ được giữ",
+			in:   `{"common":{"ai_loc":0,"future":[1,2]},"new_section":{"a":1}}`,
+		},
+		{
+			name: "null được giữ",
+			in:   `{"common":null,"prog":[{"code":null,"en":true}],"rtu_master":null}`,
+		},
+		{
+			name: "mảng rỗng và giá trị 0",
+			in:   `{"ais":[],"common":{"ai_loc":0,"persist":false},"timers":[{"code":"","int":0,"one":false}]}`,
+		},
+		{
+			name: "khóa được sắp xếp",
+			in:   `{"prog":[{"en":true,"code":"set(0, 1);"}],"common":{"di_loc":1,"ai_loc":0}}`,
+			out:  `{"common":{"ai_loc":0,"di_loc":1},"prog":[{"code":"set(0, 1);","en":true}]}`,
+		},
+	}
+	for _, tt := range tests {
+		cfg, err := Parse([]byte(tt.in))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want := tt.out
+		if want == "" {
+			want = tt.in
+		}
+		if string(got) != want {
+			t.Errorf("%s:\n%s\ncần\n%s", tt.name, got, want)
+		}
+	}
+}
+
+func TestRoundTripEdits(t *testing.T) {
+	cfg, err := Parse([]byte(`{"common":{"ai_loc":0,"page_dur":null},"prog":[{"code":null,"en":false,"x":1}],"zz":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := cfg.UnknownFields(); !reflect.DeepEqual(fields, []string{"zz"}) {
+		t.Errorf("UnknownFields = %v, cần [zz]", fields)
+	}
+	if fields := cfg.Prog[0].UnknownFields(); !reflect.DeepEqual(fields, []string{"x"}) {
+		t.Errorf("prog[0].UnknownFields = %v, cần [x]", fields)
+	}
+
+	// Trường null được gán giá trị thì ghi giá trị mới, trường null khác vẫn là null
+	cfg.Prog[0].Code = "set(0, 1);"
+	cfg.Prog[0].En = true
+	got, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"common":{"ai_loc":0,"page_dur":null},"prog":[{"code":"set(0, 1);","en":true,"x":1}],"zz":true}`
+	if string(got) != want {
+		t.Errorf("\n%s\ncần\n%s", got, want)
+	}
+
+	// Trường vắng mặt được gán giá trị thì ghi giá trị mới, trường vắng mặt còn 0 vẫn bị bỏ
+	cfg, err = Parse([]byte(`{"common":{"ai_loc":0}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Common.ConfPort = 19981
+	cfg.RTUSlave = &RTUSlave{}
+	cfg.Tags = []Tag{{}}
+	got, err = json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(got, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 3 || fields["rtu_slave"] == nil || fields["tags"] == nil {
+		t.Errorf("%s: cần common, rtu_slave và tags", got)
+	}
+	if want := `{"ai_loc":0,"conf_port":19981}`; string(fields["common"]) != want {
+		t.Errorf("common = %s, cần %s", fields["common"], want)
+	}
+	back, err := Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.RTUSlave == nil || len(back.Tags) != 1 {
+		t.Errorf("đọc lại mất rtu_slave hoặc tags: %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{``, `[]`, `{"common":{"ai_loc":"1"}}`, `{"prog":{}}`} {
+		if _, err := Parse([]byte(in)); err == nil {
+			t.Errorf("Parse(%q) không báo lỗi", in)
+		}
+	}
+	if _, err := Format(nil); err == nil {
+		t.Error("Format(nil) không báo lỗi")
+	}
+}
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind là loại thay đổi của một trường
+type ChangeKind string
+
+const (
+	KindAdded   ChangeKind = "added"
+	KindRemoved ChangeKind = "removed"
+	KindChanged ChangeKind = "changed"
+)
+
+// LineOp là thao tác trên một dòng của text diff
+type LineOp string
+
+const (
+	LineSame   LineOp = " "
+	LineAdd    LineOp = "+"
+	LineRemove LineOp = "-"
+)
+
+// Line là một dòng của text diff
+type Line struct {
+	Op   LineOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// Change là một trường khác nhau giữa hai cấu hình.
+// Path là đường dẫn dễ đọc (tags[3].unit), Pointer là JSON pointer (/tags/3/unit).
+type Change struct {
+	Section string      `json:"section"`
+	Path    string      `json:"path"`
+	Pointer string      `json:"pointer"`
+	Kind    ChangeKind  `json:"kind"`
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+	Text    []Line      `json:"text,omitempty"` // text diff đầy đủ theo dòng, chỉ có với code script
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Kind == KindAdded:
+		return fmt.Sprintf("%s: thêm %s", c.Path, formatValue(c.New))
+	case c.Kind == KindRemoved:
+		return fmt.Sprintf("%s: xóa %s", c.Path, formatValue(c.Old))
+	case c.Text != nil:
+		return fmt.Sprintf("%s: sửa theo dòng", c.Path)
+	default:
+		return fmt.Sprintf("%s: %s → %s", c.Path, formatValue(c.Old), formatValue(c.New))
+	}
+}
+
+// SectionDiff là các thay đổi trong một section cấp cao nhất
+type SectionDiff struct {
+	Name    string   `json:"name"`
+	Changes []Change `json:"changes"`
+}
+
+// Diff là kết quả so sánh hai cấu hình, các section theo thứ tự của file mẫu
+type Diff struct {
+	Sections []SectionDiff `json:"sections"`
+	Total    int           `json:"total"`
+}
+
+// Lines trả về mỗi thay đổi một dòng, kèm các dòng thêm/xóa của text diff thụt lề bên dưới
+func (d *Diff) Lines() []string {
+	var lines []string
+	for _, section := range d.Sections {
+		for _, change := range section.Changes {
+			lines = append(lines, change.String())
+			for _, line := range change.Text {
+				if line.Op == LineSame {
+					continue
+				}
+				lines = append(lines, "    "+string(line.Op)+" "+line.Text)
+			}
+		}
+	}
+	return lines
+}
+
+// Compare so sánh cấu hình from (ví dụ trên thiết bị) với to (ví dụ file trong workspace).
+// Mảng được so theo chỉ số, trường lạ cũng được so sánh.
+func Compare(from, to *Config) (*Diff, error) {
+	a, err := json.Marshal(from)
+	if err != nil {
+		return nil, fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	b, err := json.Marshal(to)
+	if err != nil {
+		return nil, fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	return CompareData(a, b)
+}
+
+// CompareData so sánh hai nội dung file cấu hình dạng JSON
+func CompareData(from, to []byte) (*Diff, error) {
+	a, err := decodeGeneric(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeGeneric(to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{Sections: []SectionDiff{}}
+	for _, name := range sectionNames(a, b) {
+		var changes []Change
+		walk(name, name, pointer(name), field(a, name), field(b, name), &changes)
+		if len(changes) > 0 {
+			d.Sections = append(d.Sections, SectionDiff{Name: name, Changes: changes})
+			d.Total += len(changes)
+		}
+	}
+	return d, nil
+}
+
+func decodeGeneric(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("cấu hình không hợp lệ: %w", err)
+	}
+	delete(m, "type")
+	return m, nil
+}
+
+// sectionNames trả về tên section của cả hai phía: các section đã biết theo thứ tự
+// khai báo trong Config, sau đó là các section lạ theo thứ tự chữ cái
+func sectionNames(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, f := range fieldsOf(reflect.TypeOf(Config{})) {
+		seen[f.name] = true
+		if _, ok := a[f.name]; ok {
+			names = append(names, f.name)
+		} else if _, ok := b[f.name]; ok {
+			names = append(names, f.name)
+		}
+	}
+	var others []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				others = append(others, name)
+			}
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+// missing đánh dấu giá trị không tồn tại ở một phía (khác với null)
+type missing struct{}
+
+func walk(section, path, ptr string, a, b interface{}, out *[]Change) {
+	aMissing, bMissing := isMissing(a), isMissing(b)
+	if aMissing {
+		a = nil
+	}
+	if bMissing {
+		b = nil
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(section, path+"."+k, ptr+pointer(k), field(av, k), field(bv, k), out)
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			n := len(av)
+			if len(bv) > n {
+				n = len(bv)
+			}
+			for i := 0; i < n; i++ {
+				walk(section, path+"["+strconv.Itoa(i)+"]", ptr+pointer(i), item(av, i), item(bv, i), out)
+			}
+			return
+		}
+	}
+
+	change := Change{Section: section, Path: path, Pointer: ptr, Old: a, New: b}
+	switch {
+	case aMissing:
+		change.Kind = KindAdded
+	case bMissing:
+		change.Kind = KindRemoved
+	case equalValue(a, b):
+		return
+	default:
+		change.Kind = KindChanged
+		as, aok := a.(string)
+		bs, bok := b.(string)
+		if aok && bok && (strings.Contains(as, "\n") || strings.Contains(bs, "\n") || strings.HasSuffix(path, ".code")) {
+			change.Text = diffLines(as, bs)
+		}
+	}
+	*out = append(*out, change)
+}
+
+func field(m map[string]interface{}, k string) interface{} {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return missing{}
+}
+
+func item(s []interface{}, i int) interface{} {
+	if i < len(s) {
+		return s[i]
+	}
+	return missing{}
+}
+
+func isMissing(v interface{}) bool {
+	_, ok := v.(missing)
+	return ok
+}
+
+// equalValue so sánh hai giá trị JSON, số được so theo giá trị (1 bằng 1.0)
+func equalValue(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		if an == bn {
+			return true
+		}
+		af, err1 := an.Float64()
+		bf, err2 := bn.Float64()
+		return err1 == nil && err2 == nil && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	default:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+		return string(data)
+	}
+}
+
+// maxDiffLines giới hạn kích thước bảng LCS; script dài hơn được coi như thay toàn bộ
+const maxDiffLines = 2000
+
+// diffLines tạo text diff theo dòng bằng dãy con chung dài nhất
+func diffLines(a, b string) []Line {
+	al := strings.Split(a, "\n")
+	bl := strings.Split(b, "\n")
+	if len(al) > maxDiffLines || len(bl) > maxDiffLines {
+		lines := make([]Line, 0, len(al)+len(bl))
+		for _, l := range al {
+			lines = append(lines, Line{Op: LineRemove, Text: l})
+		}
+		for _, l := range bl {
+			lines = append(lines, Line{Op: LineAdd, Text: l})
+		}
+		return lines
+	}
+
+	// lcs[i][j] là độ dài dãy con chung dài nhất của al[i:] và bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(al) && j < len(bl) {
+		switch {
+		case al[i] == bl[j]:
+			lines = append(lines, Line{Op: LineSame, Text: al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: LineRemove, Text: al[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: LineAdd, Text: bl[j]})
+			j++
+		}
+	}
+	for ; i < len(al); i++ {
+		lines = append(lines, Line{Op: LineRemove, Text: al[i]})
+	}
+	for ; j < len(bl); j++ {
+		lines = append(lines, Line{Op: LineAdd, Text: bl[j]})
+	}
+	return lines
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompareData(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		sections []string
+		lines    []string
+	}{
+		{
+			name: "giống nhau, số so theo giá trị và bỏ qua type",
+			from: `{"type":"download_config","common":{"ai_loc":0,"scale":1.0}}`,
+			to:   `{"common":{"scale":1,"ai_loc":0}}`,
+		},
+		{
+			name:     "đổi giá trị",
+			from:     `{"tags":[{},{},{},{"unit":"mg/Nm3","en":true}]}`,
+			to:       `{"tags":[{},{},{},{"unit":"ppm","en":true}]}`,
+			sections: []string{"tags"},
+			lines:    []string{"tags[3].unit: mg/Nm3 → ppm"},
+		},
+		{
+			name:     "thêm và xóa",
+			from:     `{"common":{"ai_loc":0,"old":1},"timers":[{"int":5}]}`,
+			to:       `{"common":{"ai_loc":0,"di_loc":12},"timers":[{"int":5},{"int":10}]}`,
+			sections: []string{"common", "timers"},
+			lines:    []string{"common.di_loc: thêm 12", "common.old: xóa 1", `timers[1]: thêm {"int":10}`},
+		},
+		{
+			name:     "null khác với không có",
+			from:     `{"common":{"ai_loc":null}}`,
+			to:       `{"common":{}}`,
+			sections: []string{"common"},
+			lines:    []string{"common.ai_loc: xóa null"},
+		},
+		{
+			name:     "đổi kiểu",
+			from:     `{"rtu_slave":{"port":"1"}}`,
+			to:       `{"rtu_slave":[1]}`,
+			sections: []string{"rtu_slave"},
+			lines:    []string{"rtu_slave: {\"port\":\"1\"} → [1]"},
+		},
+		{
+			name:     "code script theo dòng",
+			from:     `{"prog":[{},{},{"code":"set(0, 1);\nset(1, 2);\nset(2, 3);"}]}`,
+			to:       `{"prog":[{},{},{"code":"set(0, 1);\nset(1, 5);\nset(2, 3);"}]}`,
+			sections: []string{"prog"},
+			lines:    []string{"prog[2].code: sửa theo dòng", "    - set(1, 2);", "    + set(1, 5);"},
+		},
+		{
+			name:     "section đã biết theo thứ tự Config, section lạ theo chữ cái",
+			from:     `{"zz":1,"tags":[],"aa":1}`,
+			to:       `{"zz":2,"tags":[1],"aa":2,"common":{"ai_loc":0}}`,
+			sections: []string{"common", "tags", "aa", "zz"},
+			lines:    []string{`common: thêm {"ai_loc":0}`, "tags[0]: thêm 1", "aa: 1 → 2", "zz: 1 → 2"},
+		},
+	}
+	for _, tt := range tests {
+		d, err := CompareData([]byte(tt.from), []byte(tt.to))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var sections []string
+		for _, s := range d.Sections {
+			sections = append(sections, s.Name)
+		}
+		if !reflect.DeepEqual(sections, tt.sections) {
+			t.Errorf("%s: section %v, cần %v", tt.name, sections, tt.sections)
+		}
+		if lines := d.Lines(); !reflect.DeepEqual(lines, tt.lines) {
+			t.Errorf("%s: thay đổi\n%s\ncần\n%s", tt.name, strings.Join(lines, "\n"), strings.Join(tt.lines, "\n"))
+		}
+	}
+}
+
+func TestCompareDataPointer(t *testing.T) {
+	d, err := CompareData([]byte(`{"common":{"a/b~c":1}}`), []byte(`{"common":{"a/b~c":2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Change{Section: "common", Path: "common.a/b~c", Pointer: "/common/a~1b~0c", Kind: KindChanged}
+	got := d.Sections[0].Changes[0]
+	got.Old, got.New = nil, nil
+	if !reflect.DeepEqual(got, want) || d.Total != 1 {
+		t.Errorf("thay đổi %+v (Total %d), cần %+v", got, d.Total, want)
+	}
+}
+
+func TestCompareDataInvalid(t *testing.T) {
+	if _, err := CompareData([]byte(`{"common":`), []byte(`{}`)); err == nil {
+		t.Error("JSON hỏng không báo lỗi")
+	}
+	if _, err := CompareData([]byte(`{}`), []byte(`[1]`)); err == nil {
+		t.Error("mảng ở cấp cao nhất không báo lỗi")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string // mỗi dòng là op + text
+	}{
+		{
+			name: "giống nhau",
+			a:    "a\nb",
+			b:    "a\nb",
+			want: " a| b",
+		},
+		{
+			name: "thêm ở giữa",
+			a:    "a\nc",
+			b:    "a\nb\nc",
+			want: " a|+b| c",
+		},
+		{
+			name: "xóa ở cuối",
+			a:    "a\nb\nc",
+			b:    "a\nb",
+			want: " a| b|-c",
+		},
+		{
+			name: "sửa một dòng thì xóa trước thêm sau",
+			a:    "a\nb\nc",
+			b:    "a\nx\nc",
+			want: " a|-b|+x| c",
+		},
+		{
+			name: "giữ dãy con chung dài nhất",
+			a:    "x\na\nb\nc",
+			b:    "a\nb\ny\nc",
+			want: "-x| a| b|+y| c",
+		},
+		{
+			name: "chuỗi rỗng là một dòng rỗng",
+			a:    "",
+			b:    "a",
+			want: "-|+a",
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, line := range diffLines(tt.a, tt.b) {
+			got = append(got, string(line.Op)+line.Text)
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("%s: diff %q, cần %q", tt.name, strings.Join(got, "|"), tt.want)
+		}
+	}
+}
+
+func TestDiffLinesTooLong(t *testing.T) {
+	// Quá maxDiffLines thì coi như thay toàn bộ thay vì dựng bảng LCS
+	long := strings.Repeat("x\n", maxDiffLines)
+	lines := diffLines(long, long)
+	if len(lines) != 2*(maxDiffLines+1) || lines[0].Op != LineRemove || lines[len(lines)-1].Op != LineAdd {
+		t.Errorf("có %d dòng, dòng đầu %q, dòng cuối %q", len(lines), lines[0].Op, lines[len(lines)-1].Op)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// extra giữ những gì struct không mô tả được để ghi lại JSON không mất dữ liệu:
+// các trường lạ (firmware mới hơn ứng dụng), các trường đã biết nhưng không có trong dữ liệu gốc
+// và các trường đã biết có giá trị null
+type extra struct {
+	unknown map[string]json.RawMessage
+	absent  map[string]bool
+	null    map[string]bool
+}
+
+// UnknownFields liệt kê tên các trường không có trong model, đã sắp xếp
+func (e extra) UnknownFields() []string {
+	names := make([]string, 0, len(e.unknown))
+	for name := range e.unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonField là một trường đã biết của struct: tên JSON và chỉ số trường
+type jsonField struct {
+	name  string
+	index int
+}
+
+// jsonFields lưu danh sách trường JSON của từng kiểu struct
+var jsonFields sync.Map
+
+// fieldsOf trả về các trường JSON đã biết của kiểu t, theo thứ tự khai báo
+func fieldsOf(t reflect.Type) []jsonField {
+	if cached, ok := jsonFields.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonField{name: name, index: i})
+	}
+	jsonFields.Store(t, fields)
+	return fields
+}
+
+// decode giải mã data vào plain (con trỏ tới kiểu không có UnmarshalJSON riêng)
+// và ghi lại trường lạ, trường vắng mặt vào e
+func decode(data []byte, plain interface{}, e *extra) error {
+	if err := json.Unmarshal(data, plain); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	e.unknown = nil
+	e.absent = nil
+	e.null = nil
+	for _, field := range fieldsOf(reflect.TypeOf(plain).Elem()) {
+		if raw, ok := fields[field.name]; ok {
+			if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+				if e.null == nil {
+					e.null = make(map[string]bool)
+				}
+				e.null[field.name] = true
+			}
+			delete(fields, field.name)
+			continue
+		}
+		if e.absent == nil {
+			e.absent = make(map[string]bool)
+		}
+		e.absent[field.name] = true
+	}
+	if len(fields) > 0 {
+		e.unknown = fields
+	}
+	return nil
+}
+
+// encode mã hóa plain rồi bỏ các trường nil và các trường vắng mặt trong dữ liệu gốc
+// còn giá trị 0, ghi lại null cho trường gốc là null và chưa được gán giá trị, thêm lại các trường lạ.
+// Khóa được sắp xếp theo thứ tự chữ cái như file cấu hình của thiết bị.
+func encode(plain interface{}, e extra) ([]byte, error) {
+	data, err := json.Marshal(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(plain)
+	for _, field := range fieldsOf(value.Type()) {
+		v := value.Field(field.index)
+		if e.absent[field.name] && v.IsZero() {
+			delete(fields, field.name)
+			continue
+		}
+		if e.null[field.name] && v.IsZero() {
+			fields[field.name] = json.RawMessage("null")
+			continue
+		}
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			if v.IsNil() {
+				delete(fields, field.name)
+			}
+		}
+	}
+	for name, raw := range e.unknown {
+		if _, exists := fields[name]; !exists {
+			fields[name] = raw
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"myproject/backend/script"
+)
+
+// MemorySize là số thanh ghi trong bộ nhớ của datalogger (Reg0..Reg255)
+const MemorySize = 256
+
+// Access là cách một nguồn dùng thanh ghi
+type Access string
+
+const (
+	AccessRead  Access = "read"
+	AccessWrite Access = "write"
+)
+
+// Loại xung đột bộ nhớ
+const (
+	ConflictMultipleWriters = "multiple_writers" // nhiều nguồn cùng ghi một thanh ghi
+	ConflictReadNotWritten  = "read_not_written" // có nguồn đọc nhưng không nguồn nào ghi
+	ConflictModbusOverlap   = "modbus_overlap"   // nguồn khác ghi vào vùng đọc nhiều thanh ghi của modbus_reader
+	ConflictOutOfRange      = "out_of_range"     // chỉ số ngoài 0..MemorySize-1
+)
+
+// MemoryRef là một lần tham chiếu tới thanh ghi từ cấu hình
+type MemoryRef struct {
+	Index   int    `json:"index"`
+	Access  Access `json:"access"`
+	Source  string `json:"source"`         // tags[3].val_idx, prog[2].code, ...
+	Pointer string `json:"pointer"`        // JSON pointer tới trường chứa tham chiếu
+	Line    int    `json:"line,omitempty"` // dòng trong code script (từ 1)
+	Enabled bool   `json:"enabled"`        // nguồn đang bật (en, dis/dos theo xung) hoặc luôn chạy (vùng *_loc)
+}
+
+// MemoryCell là các tham chiếu tới một thanh ghi
+type MemoryCell struct {
+	Index   int         `json:"index"`
+	Readers []MemoryRef `json:"readers"`
+	Writers []MemoryRef `json:"writers"`
+}
+
+// MemoryConflict là một vấn đề tìm được trên bản đồ bộ nhớ
+type MemoryConflict struct {
+	Kind    string      `json:"kind"`
+	Index   int         `json:"index"`
+	Message string      `json:"message"`
+	Refs    []MemoryRef `json:"refs"`
+}
+
+// MemoryMap là bản đồ sử dụng bộ nhớ của một cấu hình.
+// Cells chỉ gồm các thanh ghi có tham chiếu, theo thứ tự chỉ số.
+type MemoryMap struct {
+	Size      int              `json:"size"`
+	Cells     []MemoryCell     `json:"cells"`
+	Conflicts []MemoryConflict `json:"conflicts"`
+}
+
+// scriptRefs trả về các tham chiếu bộ nhớ có chỉ số hằng trong code. Câu lệnh lỗi cú pháp
+// bị bỏ qua, lỗi đó được báo khi kiểm tra cấu hình.
+func scriptRefs(code, source, ptr string, enabled bool) []MemoryRef {
+	var refs []MemoryRef
+	prog, _ := script.Parse(code)
+	script.Walk(prog, func(n script.Node) bool {
+		call, ok := n.(*script.Call)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		builtin, ok := script.Builtins[call.Name]
+		if !ok || builtin.Index != script.IndexMemory {
+			return true
+		}
+		index, ok := script.Const(call.Args[0])
+		if !ok || index != math.Trunc(index) {
+			return true
+		}
+		access := AccessRead
+		if builtin.Effect {
+			access = AccessWrite
+		}
+		refs = append(refs, MemoryRef{Index: int(index), Access: access, Source: source, Pointer: ptr, Line: call.Pos().Line, Enabled: enabled})
+		return true
+	})
+	return refs
+}
+
+// MemoryRefs liệt kê mọi tham chiếu tới bộ nhớ trong cấu hình:
+// vùng AI/DI/DO của common, dis/dos[].memory, tags, modbus_reader và code của prog/timers
+func MemoryRefs(cfg *Config) []MemoryRef {
+	var refs []MemoryRef
+	add := func(index int, access Access, source, ptr string, enabled bool) {
+		refs = append(refs, MemoryRef{Index: index, Access: access, Source: source, Pointer: ptr, Enabled: enabled})
+	}
+	if cfg == nil {
+		return refs
+	}
+
+	// Firmware ghi giá trị mọi kênh AI, trạng thái DI và DO của phần cứng vào các vùng
+	// liên tiếp bắt đầu từ *_loc, kể cả khi ais/dis/dos ngắn hơn số kênh
+	if c := cfg.Common; c != nil {
+		for i := 0; i < AnalogInputCount; i++ {
+			add(c.AiLoc+i, AccessWrite, fmt.Sprintf("common.ai_loc (AI %d)", i), "/common/ai_loc", true)
+		}
+		for i := 0; i < DigitalInputCount; i++ {
+			add(c.DiLoc+i, AccessWrite, fmt.Sprintf("common.di_loc (DI %d)", i), "/common/di_loc", true)
+		}
+		for i := 0; i < DigitalOutputCount; i++ {
+			add(c.DoLoc+i, AccessWrite, fmt.Sprintf("common.do_loc (DO %d)", i), "/common/do_loc", true)
+		}
+	}
+	// memory của dis/dos chỉ được dùng ở chế độ theo xung; ở chế độ theo mức vẫn liệt kê để tra cứu
+	for i, d := range cfg.Dis {
+		add(d.Memory, AccessWrite, fmt.Sprintf("dis[%d].memory", i), pointer("dis", i, "memory"), d.ActType == ActTypePulse)
+	}
+	for i, d := range cfg.Dos {
+		add(d.Memory, AccessRead, fmt.Sprintf("dos[%d].memory", i), pointer("dos", i, "memory"), d.ActType == ActTypePulse)
+	}
+	for i, t := range cfg.Tags {
+		add(t.ValIdx, AccessRead, fmt.Sprintf("tags[%d].val_idx", i), pointer("tags", i, "val_idx"), t.En)
+		add(t.StatIdx, AccessRead, fmt.Sprintf("tags[%d].stat_idx", i), pointer("tags", i, "stat_idx"), t.En)
+	}
+	for i, m := range cfg.ModbusReader {
+		for n := 0; n < m.NObj; n++ {
+			add(m.LocVal+n, AccessWrite, fmt.Sprintf("modbus_reader[%d].loc_val", i), pointer("modbus_reader", i, "loc_val"), m.En)
+		}
+		add(m.LocStat, AccessWrite, fmt.Sprintf("modbus_reader[%d].loc_stat", i), pointer("modbus_reader", i, "loc_stat"), m.En)
+	}
+	for i, p := range cfg.Prog {
+		refs = append(refs, scriptRefs(p.Code, fmt.Sprintf("prog[%d].code", i), pointer("prog", i, "code"), p.En)...)
+	}
+	for i, t := range cfg.Timers {
+		refs = append(refs, scriptRefs(t.Code, fmt.Sprintf("timers[%d].code", i), pointer("timers", i, "code"), t.En)...)
+	}
+	return refs
+}
+
+// AnalyzeMemory xây bản đồ bộ nhớ và tìm xung đột. Chỉ các nguồn đang bật được tính
+// khi tìm xung đột; nguồn tắt vẫn có trong Cells để tra cứu.
+func AnalyzeMemory(cfg *Config) *MemoryMap {
+	m := &MemoryMap{Size: MemorySize, Cells: []MemoryCell{}, Conflicts: []MemoryConflict{}}
+	cells := make(map[int]*MemoryCell)
+
+	for _, ref := range MemoryRefs(cfg) {
+		if ref.Index < 0 || ref.Index >= MemorySize {
+			m.Conflicts = append(m.Conflicts, MemoryConflict{
+				Kind:    ConflictOutOfRange,
+				Index:   ref.Index,
+				Message: fmt.Sprintf("%s dùng thanh ghi %d ngoài khoảng 0..%d", ref.Source, ref.Index, MemorySize-1),
+				Refs:    []MemoryRef{ref},
+			})
+			continue
+		}
+		cell, ok := cells[ref.Index]
+		if !ok {
+			cell = &MemoryCell{Index: ref.Index, Readers: []MemoryRef{}, Writers: []MemoryRef{}}
+			cells[ref.Index] = cell
+		}
+		if ref.Access == AccessWrite {
+			cell.Writers = append(cell.Writers, ref)
+		} else {
+			cell.Readers = append(cell.Readers, ref)
+		}
+	}
+
+	indexes := make([]int, 0, len(cells))
+	for index := range cells {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	for _, index := range indexes {
+		cell := cells[index]
+		m.Cells = append(m.Cells, *cell)
+
+		writers := enabledSources(cell.Writers)
+		if len(writers) > 1 {
+			m.Conflicts = append(m.Conflicts, MemoryConflict{
+				Kind:    ConflictMultipleWriters,
+				Index:   index,
+				Message: fmt.Sprintf("thanh ghi %d được ghi bởi %s", index, strings.Join(sourceNames(writers), ", ")),
+				Refs:    writers,
+			})
+		}
+		if readers := enabledSources(cell.Readers); len(readers) > 0 && len(writers) == 0 {
+			m.Conflicts = append(m.Conflicts, MemoryConflict{
+				Kind:    ConflictReadNotWritten,
+				Index:   index,
+				Message: fmt.Sprintf("thanh ghi %d được đọc bởi %s nhưng không có nguồn nào ghi", index, strings.Join(sourceNames(readers), ", ")),
+				Refs:    readers,
+			})
+		}
+	}
+
+	if cfg != nil {
+		for i, reader := range cfg.ModbusReader {
+			if reader.En && reader.NObj > 1 {
+				m.Conflicts = append(m.Conflicts, modbusOverlaps(cells, i, reader)...)
+			}
+		}
+	}
+	return m
+}
+
+// modbusOverlaps tìm nguồn khác ghi vào vùng loc_val..loc_val+n_obj-1 của một modbus_reader
+func modbusOverlaps(cells map[int]*MemoryCell, i int, reader ModbusReader) []MemoryConflict {
+	var conflicts []MemoryConflict
+	own := fmt.Sprintf("modbus_reader[%d].loc_val", i)
+	for index := reader.LocVal; index < reader.LocVal+reader.NObj; index++ {
+		cell, ok := cells[index]
+		if !ok {
+			continue
+		}
+		var others []MemoryRef
+		for _, ref := range enabledSources(cell.Writers) {
+			if ref.Source != own {
+				others = append(others, ref)
+			}
+		}
+		if len(others) > 0 {
+			conflicts = append(conflicts, MemoryConflict{
+				Kind:  ConflictModbusOverlap,
+				Index: index,
+				Message: fmt.Sprintf("thanh ghi %d nằm trong vùng đọc %d..%d (n_obj %d) của %s nhưng cũng được ghi bởi %s",
+					index, reader.LocVal, reader.LocVal+reader.NObj-1, reader.NObj, fmt.Sprintf("modbus_reader[%d]", i), strings.Join(sourceNames(others), ", ")),
+				Refs: others,
+			})
+		}
+	}
+	return conflicts
+}
+
+// enabledSources lọc tham chiếu của nguồn đang bật, mỗi nguồn chỉ giữ lần đầu
+// (một script ghi cùng thanh ghi nhiều lần vẫn chỉ là một nguồn)
+func enabledSources(refs []MemoryRef) []MemoryRef {
+	var result []MemoryRef
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.Enabled && !seen[ref.Source] {
+			seen[ref.Source] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+func sourceNames(refs []MemoryRef) []string {
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Source
+		if ref.Line > 0 {
+			names[i] += ":" + strconv.Itoa(ref.Line)
+		}
+	}
+	return names
+}
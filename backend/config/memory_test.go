@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// conflictIndexes nhóm chỉ số thanh ghi của các xung đột theo loại
+func conflictIndexes(m *MemoryMap) map[string][]int {
+	indexes := make(map[string][]int)
+	for _, c := range m.Conflicts {
+		indexes[c.Kind] = append(indexes[c.Kind], c.Index)
+	}
+	return indexes
+}
+
+func span(from, to int) []int {
+	var indexes []int
+	for i := from; i <= to; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func TestAnalyzeMemoryFiles(t *testing.T) {
+	tests := []struct {
+		path      string
+		conflicts map[string][]int
+	}{
+		{
+			// Các tag đọc thanh ghi chỉ được ghi bởi prog đang tắt
+			path: "../../workspace/default.json",
+			conflicts: map[string][]int{
+				ConflictReadNotWritten: {60, 106, 117, 205, 207, 216},
+			},
+		},
+		{
+			// ais rỗng nhưng firmware vẫn ghi 12 kênh AI từ ai_loc, nên prog đọc 0..8 không bị báo.
+			// 12 kênh DI từ di_loc 12 chồng lên vùng DO từ do_loc 20; prog[0] ghi vào vùng đọc của modbus_reader[6].
+			path: "../../aaa.json",
+			conflicts: map[string][]int{
+				ConflictMultipleWriters: append(span(20, 23), span(50, 58)...),
+				ConflictModbusOverlap:   span(50, 58),
+			},
+		},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if got := conflictIndexes(AnalyzeMemory(cfg)); !reflect.DeepEqual(got, tt.conflicts) {
+			t.Errorf("%s: xung đột %v, cần %v", tt.path, got, tt.conflicts)
+		}
+	}
+}
+
+func TestAnalyzeMemory(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		conflicts map[string][]int
+	}{
+		{
+			name: "vùng AI theo số kênh phần cứng",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":100,"do_loc":120},"ais":[],"prog":[{"code":"set(20, get(11));","en":true}],"tags":[{"en":true,"val_idx":20,"stat_idx":12}]}`,
+			conflicts: map[string][]int{
+				ConflictReadNotWritten: {12},
+			},
+		},
+		{
+			name: "dis theo mức không dùng memory",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":12,"do_loc":24},"dis":[{"act_type":0,"memory":12},{"act_type":0,"memory":40}]}`,
+		},
+		{
+			name: "dis theo xung ghi memory",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":12,"do_loc":24},"dis":[{"act_type":1,"memory":12},{"act_type":1,"memory":40}]}`,
+			conflicts: map[string][]int{
+				ConflictMultipleWriters: {12},
+			},
+		},
+		{
+			name: "dos theo xung đọc memory",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":12,"do_loc":24},"dos":[{"act_type":1,"memory":50},{"act_type":0,"memory":51},{"act_type":1,"memory":24}]}`,
+			conflicts: map[string][]int{
+				ConflictReadNotWritten: {50},
+			},
+		},
+		{
+			name: "nguồn tắt không tính",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":12,"do_loc":24},"prog":[{"code":"set(0, 1);","en":false},{"code":"set(60, 1); set(60, 2);","en":true}],"tags":[{"en":false,"val_idx":70,"stat_idx":71}]}`,
+		},
+		{
+			name: "vùng vượt bộ nhớ",
+			cfg:  `{"common":{"ai_loc":250,"di_loc":12,"do_loc":24}}`,
+			conflicts: map[string][]int{
+				ConflictOutOfRange: span(256, 261),
+			},
+		},
+		{
+			name: "modbus_reader chồng lên vùng khác",
+			cfg:  `{"common":{"ai_loc":0,"di_loc":12,"do_loc":24},"modbus_reader":[{"en":true,"loc_val":30,"n_obj":4,"loc_stat":80}]}`,
+			conflicts: map[string][]int{
+				ConflictMultipleWriters: {30, 31},
+				ConflictModbusOverlap:   {30, 31},
+			},
+		},
+	}
+	for _, tt := range tests {
+		cfg, err := Parse([]byte(tt.cfg))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want := tt.conflicts
+		if want == nil {
+			want = map[string][]int{}
+		}
+		if got := conflictIndexes(AnalyzeMemory(cfg)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: xung đột %v, cần %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestMemoryRefsLevelMode(t *testing.T) {
+	// memory của dis/dos theo mức vẫn có trong bản đồ để tra cứu nhưng không được tính là nguồn
+	cfg, err := Parse([]byte(`{"dis":[{"act_type":0,"memory":22}],"dos":[{"act_type":1,"memory":30}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MemoryRef{
+		{Index: 22, Access: AccessWrite, Source: "dis[0].memory", Pointer: "/dis/0/memory", Enabled: false},
+		{Index: 30, Access: AccessRead, Source: "dos[0].memory", Pointer: "/dos/0/memory", Enabled: true},
+	}
+	if got := MemoryRefs(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("MemoryRefs = %+v, cần %+v", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"myproject/backend/script"
+)
+
+// ScriptTests là các test case của script, lưu cạnh file cấu hình (station.json -> station.tests.json)
+type ScriptTests struct {
+	Cases []ScriptCase `json:"cases"`
+}
+
+// ScriptCase là một test case: đặt giá trị vào, chạy một prog/timer (hoặc cả cấu hình)
+// rồi so sánh bộ nhớ, DO và timer với giá trị mong đợi
+type ScriptCase struct {
+	Name      string          `json:"name"`
+	Prog      *int            `json:"prog,omitempty"`  // chạy riêng prog[i], kể cả khi đang tắt
+	Timer     *int            `json:"timer,omitempty"` // chạy riêng timers[i], kể cả khi đang tắt
+	AI        []float64       `json:"ai,omitempty"`    // giá trị AI (mA), ghi vào ai_loc+i
+	DI        []bool          `json:"di,omitempty"`    // trạng thái DI, ghi vào di_loc+i
+	Memory    map[int]float64 `json:"memory,omitempty"`
+	Cycles    int             `json:"cycles,omitempty"`
+	Expect    ScriptExpect    `json:"expect"`
+	Tolerance float64         `json:"tolerance,omitempty"` // sai số cho phép, mặc định so sánh theo float32
+}
+
+// ScriptExpect là trạng thái mong đợi sau khi chạy, chỉ các chỉ số được liệt kê mới được so sánh
+type ScriptExpect struct {
+	Memory  map[int]float64 `json:"memory,omitempty"`
+	Outputs map[int]bool    `json:"outputs,omitempty"`
+	Timers  map[int]bool    `json:"timers,omitempty"` // true là timer còn chạy
+}
+
+// Mismatch là một giá trị khác với mong đợi
+type Mismatch struct {
+	Target   string  `json:"target"` // memory, output, timer
+	Index    int     `json:"index"`
+	Expected float64 `json:"expected"`
+	Actual   float64 `json:"actual"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s[%d]: cần %s, được %s", m.Target, m.Index, script.FormatValue(m.Expected), script.FormatValue(m.Actual))
+}
+
+// ScriptCaseResult là kết quả của một test case
+type ScriptCaseResult struct {
+	Name       string          `json:"name"`
+	Target     string          `json:"target"` // prog[2], timers[0] hoặc config
+	Passed     bool            `json:"passed"`
+	Error      string          `json:"error,omitempty"`
+	Mismatches []Mismatch      `json:"mismatches"`
+	Changes    []script.Change `json:"changes"`
+}
+
+// ScriptTestReport là kết quả chạy mọi test case của một file cấu hình
+type ScriptTestReport struct {
+	Passed int                `json:"passed"`
+	Failed int                `json:"failed"`
+	Cases  []ScriptCaseResult `json:"cases"`
+}
+
+// String in báo cáo dạng văn bản, mỗi case một dòng kèm các giá trị sai lệch
+func (r *ScriptTestReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Cases {
+		status := "PASS"
+		if !c.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s %s (%s)\n", status, c.Name, c.Target)
+		if c.Error != "" {
+			fmt.Fprintf(&b, "    %s\n", c.Error)
+		}
+		for _, m := range c.Mismatches {
+			fmt.Fprintf(&b, "    %s\n", m)
+		}
+	}
+	fmt.Fprintf(&b, "%d đạt, %d lỗi\n", r.Passed, r.Failed)
+	return b.String()
+}
+
+// ScriptTestsPath là đường dẫn file test của một file cấu hình
+func ScriptTestsPath(configPath string) string {
+	return strings.TrimSuffix(configPath, ".json") + ".tests.json"
+}
+
+// ParseScriptTests đọc nội dung file test
+func ParseScriptTests(data []byte) (*ScriptTests, error) {
+	var tests ScriptTests
+	if err := json.Unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("file test không hợp lệ: %w", err)
+	}
+	return &tests, nil
+}
+
+// RunScriptTests chạy mọi test case trên cấu hình. Lỗi của một case (code sai, chỉ số ngoài
+// phạm vi) chỉ làm case đó không đạt.
+func RunScriptTests(cfg *Config, tests *ScriptTests) *ScriptTestReport {
+	report := &ScriptTestReport{Cases: []ScriptCaseResult{}}
+	if tests == nil {
+		return report
+	}
+	for i, c := range tests.Cases {
+		result := runScriptCase(cfg, c)
+		if result.Name == "" {
+			result.Name = fmt.Sprintf("cases[%d]", i)
+		}
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Cases = append(report.Cases, result)
+	}
+	return report
+}
+
+func runScriptCase(cfg *Config, c ScriptCase) ScriptCaseResult {
+	result := ScriptCaseResult{Name: c.Name, Target: "config", Mismatches: []Mismatch{}, Changes: []script.Change{}}
+	fail := func(err error) ScriptCaseResult {
+		result.Error = err.Error()
+		return result
+	}
+	if cfg == nil {
+		return fail(fmt.Errorf("cấu hình trống"))
+	}
+
+	input := SimulationInput{AI: c.AI, DI: c.DI, Memory: c.Memory, Cycles: c.Cycles}
+	var selected func(section string, i int) bool
+	switch {
+	case c.Prog != nil && c.Timer != nil:
+		return fail(fmt.Errorf("chỉ được chọn một trong prog và timer"))
+	case c.Prog != nil:
+		if *c.Prog < 0 || *c.Prog >= len(cfg.Prog) {
+			return fail(fmt.Errorf("không có prog[%d]", *c.Prog))
+		}
+		result.Target = fmt.Sprintf("prog[%d]", *c.Prog)
+		selected = func(section string, i int) bool { return section == "prog" && i == *c.Prog }
+	case c.Timer != nil:
+		if *c.Timer < 0 || *c.Timer >= len(cfg.Timers) {
+			return fail(fmt.Errorf("không có timers[%d]", *c.Timer))
+		}
+		result.Target = fmt.Sprintf("timers[%d]", *c.Timer)
+		selected = func(section string, i int) bool { return section == "timers" && i == *c.Timer }
+	default:
+		selected = func(section string, i int) bool {
+			if section == "prog" {
+				return cfg.Prog[i].En
+			}
+			return cfg.Timers[i].En
+		}
+	}
+
+	run, err := simulate(cfg, input, selected)
+	if err != nil {
+		return fail(err)
+	}
+	result.Changes = run.Changes
+
+	for _, index := range sortedKeys(c.Expect.Memory) {
+		if index < 0 || index >= len(run.Memory) {
+			return fail(fmt.Errorf("expect.memory: thanh ghi %d ngoài khoảng 0..%d", index, len(run.Memory)-1))
+		}
+		expected, actual := c.Expect.Memory[index], run.Memory[index]
+		if !sameValue(expected, actual, c.Tolerance) {
+			result.Mismatches = append(result.Mismatches, Mismatch{Target: script.TargetMemory, Index: index, Expected: expected, Actual: actual})
+		}
+	}
+	for _, index := range sortedKeys(c.Expect.Outputs) {
+		if index < 0 || index >= len(run.Outputs) {
+			return fail(fmt.Errorf("expect.outputs: DO %d ngoài khoảng 0..%d", index, len(run.Outputs)-1))
+		}
+		if c.Expect.Outputs[index] != run.Outputs[index] {
+			result.Mismatches = append(result.Mismatches, Mismatch{Target: script.TargetOutput, Index: index, Expected: flag(c.Expect.Outputs[index]), Actual: flag(run.Outputs[index])})
+		}
+	}
+	for _, index := range sortedKeys(c.Expect.Timers) {
+		if index < 0 || index >= len(run.Timers) {
+			return fail(fmt.Errorf("expect.timers: timer %d ngoài khoảng 0..%d", index, len(run.Timers)-1))
+		}
+		if c.Expect.Timers[index] != run.Timers[index] {
+			result.Mismatches = append(result.Mismatches, Mismatch{Target: script.TargetTimer, Index: index, Expected: flag(c.Expect.Timers[index]), Actual: flag(run.Timers[index])})
+		}
+	}
+	result.Passed = len(result.Mismatches) == 0
+	return result
+}
+
+// sameValue so sánh theo float32 như bộ nhớ thiết bị, hoặc theo tolerance nếu có
+func sameValue(expected, actual, tolerance float64) bool {
+	if float32(expected) == float32(actual) {
+		return true
+	}
+	return tolerance > 0 && math.Abs(expected-actual) <= tolerance
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+const scriptConfig = `{
+  "common": {"ai_loc": 0, "di_loc": 20},
+  "prog": [
+    {"code": "set(10, get(0) * 2);", "desc": "", "en": true},
+    {"code": "set(11, 1);", "desc": "đang tắt", "en": false},
+    {"code": "set(12, get(10) + get(20));", "desc": "", "en": true},
+    {"code": "set(0, 1 / get(30));", "desc": "lỗi chia cho 0", "en": false}
+  ],
+  "timers": [
+    {"code": "setdo(0, 1); timerstop(0);", "desc": "", "en": true, "int": 1, "one": false},
+    {"code": "set(13, get(13) + 1);", "desc": "", "en": true, "int": 1, "one": true}
+  ]
+}`
+
+func intPtr(i int) *int { return &i }
+
+func TestRunScriptTests(t *testing.T) {
+	cfg, err := Parse([]byte(scriptConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		c          ScriptCase
+		passed     bool
+		error      bool
+		mismatches []Mismatch
+	}{
+		{
+			name:   "prog riêng với AI",
+			c:      ScriptCase{Prog: intPtr(0), AI: []float64{12}, Expect: ScriptExpect{Memory: map[int]float64{10: 24}}},
+			passed: true,
+		},
+		{
+			name:   "prog đang tắt vẫn chạy khi được chọn",
+			c:      ScriptCase{Prog: intPtr(1), Expect: ScriptExpect{Memory: map[int]float64{11: 1}}},
+			passed: true,
+		},
+		{
+			name:   "cả cấu hình chạy prog theo thứ tự, bỏ prog tắt",
+			c:      ScriptCase{AI: []float64{4}, DI: []bool{true}, Expect: ScriptExpect{Memory: map[int]float64{10: 8, 11: 0, 12: 9}}},
+			passed: true,
+		},
+		{
+			name:   "timer one chỉ chạy một lần, timerstop dừng timer",
+			c:      ScriptCase{Cycles: 3, Expect: ScriptExpect{Memory: map[int]float64{13: 1}, Outputs: map[int]bool{0: true}, Timers: map[int]bool{0: false, 1: false}}},
+			passed: true,
+		},
+		{
+			name:   "so sánh theo float32",
+			c:      ScriptCase{Prog: intPtr(0), Memory: map[int]float64{0: 0.05}, Expect: ScriptExpect{Memory: map[int]float64{10: 0.1}}},
+			passed: true,
+		},
+		{
+			name:   "sai số cho phép",
+			c:      ScriptCase{Prog: intPtr(0), AI: []float64{4}, Tolerance: 0.5, Expect: ScriptExpect{Memory: map[int]float64{10: 8.4}}},
+			passed: true,
+		},
+		{
+			name:       "giá trị khác mong đợi",
+			c:          ScriptCase{Prog: intPtr(0), AI: []float64{4}, Expect: ScriptExpect{Memory: map[int]float64{10: 9}, Outputs: map[int]bool{0: true}}},
+			mismatches: []Mismatch{{Target: "memory", Index: 10, Expected: 9, Actual: 8}, {Target: "output", Index: 0, Expected: 1, Actual: 0}},
+		},
+		{name: "lỗi khi chạy", c: ScriptCase{Prog: intPtr(3)}, error: true},
+		{name: "không có prog", c: ScriptCase{Prog: intPtr(4)}, error: true},
+		{name: "không có timer", c: ScriptCase{Timer: intPtr(-1)}, error: true},
+		{name: "chọn cả prog và timer", c: ScriptCase{Prog: intPtr(0), Timer: intPtr(0)}, error: true},
+		{name: "expect ngoài khoảng", c: ScriptCase{Prog: intPtr(0), Expect: ScriptExpect{Outputs: map[int]bool{8: true}}}, error: true},
+	}
+	for _, tt := range tests {
+		report := RunScriptTests(cfg, &ScriptTests{Cases: []ScriptCase{tt.c}})
+		if len(report.Cases) != 1 {
+			t.Fatalf("%s: %d kết quả, cần 1", tt.name, len(report.Cases))
+		}
+		result := report.Cases[0]
+		if result.Passed != tt.passed || (result.Error != "") != tt.error {
+			t.Errorf("%s: passed %v, lỗi %q", tt.name, result.Passed, result.Error)
+		}
+		if tt.mismatches == nil {
+			tt.mismatches = []Mismatch{}
+		}
+		if !tt.error && !reflect.DeepEqual(result.Mismatches, tt.mismatches) {
+			t.Errorf("%s: sai lệch %v, cần %v", tt.name, result.Mismatches, tt.mismatches)
+		}
+		if passed := map[bool]int{true: 1}[tt.passed]; report.Passed != passed || report.Failed != 1-passed {
+			t.Errorf("%s: báo cáo %d đạt, %d lỗi", tt.name, report.Passed, report.Failed)
+		}
+	}
+}
+
+func TestRunScriptTestsWorkspace(t *testing.T) {
+	// Các test case mẫu trong workspace phải đạt với cấu hình mẫu
+	data, err := os.ReadFile("../../workspace/default.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(ScriptTestsPath("../../workspace/default.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests, err := ParseScriptTests(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := RunScriptTests(cfg, tests)
+	if report.Failed != 0 || report.Passed != len(tests.Cases) {
+		t.Errorf("workspace/default.tests.json:\n%s", report)
+	}
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SectionRef chỉ tới một section cấp cao nhất hoặc một phần tử của section mảng.
+// Index nil nghĩa là toàn bộ section.
+type SectionRef struct {
+	Name  string `json:"name"`
+	Index *int   `json:"index,omitempty"`
+}
+
+// Section tạo SectionRef cho toàn bộ section name
+func Section(name string) SectionRef {
+	return SectionRef{Name: name}
+}
+
+// Item tạo SectionRef cho phần tử index của section mảng name
+func Item(name string, index int) SectionRef {
+	return SectionRef{Name: name, Index: &index}
+}
+
+func (r SectionRef) String() string {
+	if r.Index == nil {
+		return r.Name
+	}
+	return r.Name + "[" + strconv.Itoa(*r.Index) + "]"
+}
+
+// Pointer trả về JSON pointer của section hoặc phần tử
+func (r SectionRef) Pointer() string {
+	if r.Index == nil {
+		return pointer(r.Name)
+	}
+	return pointer(r.Name, *r.Index)
+}
+
+// Contains cho biết JSON pointer ptr có nằm trong phạm vi của r hay không
+func (r SectionRef) Contains(ptr string) bool {
+	own := r.Pointer()
+	return ptr == own || strings.HasPrefix(ptr, own+"/")
+}
+
+// IsArraySection cho biết name có phải section dạng mảng (ais, ftp, tags, ...) hay không
+func IsArraySection(name string) bool {
+	t := reflect.TypeOf(Config{})
+	for _, f := range fieldsOf(t) {
+		if f.name == name {
+			return t.Field(f.index).Type.Kind() == reflect.Slice
+		}
+	}
+	return false
+}
+
+// sections mã hóa cfg thành bảng section -> JSON để lấy/thay từng phần
+func sections(cfg *Config) (map[string]json.RawMessage, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cấu hình trống")
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("lỗi khi tách section: %w", err)
+	}
+	return m, nil
+}
+
+// Extract trả về JSON của section hoặc phần tử ref trong cfg
+func Extract(cfg *Config, ref SectionRef) (json.RawMessage, error) {
+	m, err := sections(cfg)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := m[ref.Name]
+	if !ok {
+		return nil, fmt.Errorf("cấu hình không có section %s", ref.Name)
+	}
+	if ref.Index == nil {
+		return data, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("section %s không phải mảng", ref.Name)
+	}
+	if *ref.Index < 0 || *ref.Index >= len(items) {
+		return nil, fmt.Errorf("%s nằm ngoài mảng %d phần tử", ref, len(items))
+	}
+	return items[*ref.Index], nil
+}
+
+// Replace thay section hoặc phần tử ref của cfg bằng data, các phần khác giữ nguyên
+func Replace(cfg *Config, ref SectionRef, data json.RawMessage) error {
+	m, err := sections(cfg)
+	if err != nil {
+		return err
+	}
+	if ref.Index == nil {
+		m[ref.Name] = data
+	} else {
+		var items []json.RawMessage
+		if err := json.Unmarshal(m[ref.Name], &items); err != nil {
+			return fmt.Errorf("section %s không phải mảng", ref.Name)
+		}
+		if *ref.Index < 0 || *ref.Index >= len(items) {
+			return fmt.Errorf("%s nằm ngoài mảng %d phần tử", ref, len(items))
+		}
+		items[*ref.Index] = data
+		if m[ref.Name], err = json.Marshal(items); err != nil {
+			return fmt.Errorf("lỗi khi ghép section %s: %w", ref.Name, err)
+		}
+	}
+
+	merged, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("lỗi khi ghép cấu hình: %w", err)
+	}
+	updated, err := Parse(merged)
+	if err != nil {
+		return err
+	}
+	*cfg = *updated
+	return nil
+}
+
+// Changed trả về các phần khác nhau giữa from và to, nhỏ nhất có thể:
+// section mảng cùng số phần tử thì chỉ trả về các phần tử bị sửa, còn lại là cả section
+func Changed(from, to *Config) ([]SectionRef, error) {
+	diff, err := Compare(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []SectionRef
+	for _, section := range diff.Sections {
+		items, ok := changedItems(section)
+		if !ok {
+			refs = append(refs, Section(section.Name))
+			continue
+		}
+		for _, index := range items {
+			refs = append(refs, Item(section.Name, index))
+		}
+	}
+	return refs, nil
+}
+
+// changedItems trả về chỉ số các phần tử bị sửa của một section mảng;
+// ok là false nếu phải gửi cả section (không phải mảng, thêm/bớt phần tử, đổi kiểu)
+func changedItems(section SectionDiff) (items []int, ok bool) {
+	if !IsArraySection(section.Name) || resized(section) {
+		return nil, false
+	}
+	prefix := pointer(section.Name) + "/"
+	seen := make(map[int]bool)
+	for _, change := range section.Changes {
+		if !strings.HasPrefix(change.Pointer, prefix) {
+			return nil, false
+		}
+		index, err := strconv.Atoi(strings.SplitN(strings.TrimPrefix(change.Pointer, prefix), "/", 2)[0])
+		if err != nil {
+			return nil, false
+		}
+		if !seen[index] {
+			seen[index] = true
+			items = append(items, index)
+		}
+	}
+	return items, true
+}
+
+// resized cho biết section mảng có bị thêm/bớt phần tử hay không
+func resized(section SectionDiff) bool {
+	for _, change := range section.Changes {
+		depth := strings.Count(change.Pointer, "/")
+		if depth <= 2 && change.Kind != KindChanged {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuesFor lọc các vấn đề nằm trong phạm vi các section refs
+func IssuesFor(issues []Issue, refs []SectionRef) []Issue {
+	filtered := []Issue{}
+	for _, issue := range issues {
+		for _, ref := range refs {
+			if ref.Contains(issue.Pointer) {
+				filtered = append(filtered, issue)
+				break
+			}
+		}
+	}
+	return filtered
+}
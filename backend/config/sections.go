@@ -0,0 +1,358 @@
+package config
+
+// Common là section common: vị trí bộ nhớ của AI/DI/DO và các tham số chung
+type Common struct {
+	AiLoc           int  `json:"ai_loc"`    // thanh ghi đầu tiên của các kênh AI
+	ConfPort        int  `json:"conf_port"` // cổng TCP cấu hình (mặc định 19981)
+	DiLoc           int  `json:"di_loc"`    // thanh ghi đầu tiên của các kênh DI
+	DoLoc           int  `json:"do_loc"`    // thanh ghi đầu tiên của các kênh DO
+	NullCtx         bool `json:"null_ctx"`
+	PageDur         int  `json:"page_dur"`
+	Persist         bool `json:"persist"`   // lưu bộ nhớ khi mất điện
+	Precision       int  `json:"precision"` // số chữ số thập phân
+	TimeSync        int  `json:"time_sync"`
+	DataFieldsOrder int  `json:"data_fields_order"`
+	extra
+}
+
+func (v *Common) UnmarshalJSON(data []byte) error {
+	type plain Common
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Common) MarshalJSON() ([]byte, error) {
+	type plain Common
+	return encode(plain(v), v.extra)
+}
+
+// Control là section control: kết nối tới máy chủ điều khiển
+type Control struct {
+	Duty    int    `json:"duty"`
+	En      bool   `json:"en"`
+	Index   int    `json:"index"`
+	IP      string `json:"ip"`
+	Passwd  string `json:"passwd"`
+	Passwd2 string `json:"passwd2"`
+	Port    int    `json:"port"`
+	Port2   int    `json:"port2"`
+	Type    int    `json:"type"`
+	User    string `json:"user"`
+	User2   string `json:"user2"`
+	UUID    string `json:"uuid"`
+	UUID2   string `json:"uuid2"`
+	extra
+}
+
+func (v *Control) UnmarshalJSON(data []byte) error {
+	type plain Control
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Control) MarshalJSON() ([]byte, error) {
+	type plain Control
+	return encode(plain(v), v.extra)
+}
+
+// AnalogInput là một phần tử của section ais
+type AnalogInput struct {
+	MeasureMode int `json:"measure_mode"` // 0 là dòng (mA), 1 là áp (V)
+	extra
+}
+
+func (v *AnalogInput) UnmarshalJSON(data []byte) error {
+	type plain AnalogInput
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v AnalogInput) MarshalJSON() ([]byte, error) {
+	type plain AnalogInput
+	return encode(plain(v), v.extra)
+}
+
+// Giá trị act_type của dis và dos
+const (
+	ActTypeLevel = 0 // theo mức: trạng thái kênh nằm ở vùng di_loc/do_loc
+	ActTypePulse = 1 // theo xung: kênh dùng thanh ghi memory riêng
+)
+
+// DigitalInput là một phần tử của section dis
+type DigitalInput struct {
+	ActLev    int `json:"act_lev"`
+	ActType   int `json:"act_type"`
+	Increment int `json:"increment"` // giá trị cộng vào memory mỗi xung
+	Memory    int `json:"memory"`    // thanh ghi đếm xung, chỉ dùng khi act_type là ActTypePulse
+	Period    int `json:"period"`
+	extra
+}
+
+func (v *DigitalInput) UnmarshalJSON(data []byte) error {
+	type plain DigitalInput
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v DigitalInput) MarshalJSON() ([]byte, error) {
+	type plain DigitalInput
+	return encode(plain(v), v.extra)
+}
+
+// DigitalOutput là một phần tử của section dos
+type DigitalOutput struct {
+	ActLev  int `json:"act_lev"`
+	ActType int `json:"act_type"`
+	Duty    int `json:"duty"`
+	Memory  int `json:"memory"` // thanh ghi điều khiển đầu ra, chỉ dùng khi act_type là ActTypePulse
+	Period  int `json:"period"`
+	extra
+}
+
+func (v *DigitalOutput) UnmarshalJSON(data []byte) error {
+	type plain DigitalOutput
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v DigitalOutput) MarshalJSON() ([]byte, error) {
+	type plain DigitalOutput
+	return encode(plain(v), v.extra)
+}
+
+// FTPClient là phần client của một phần tử ftp: máy chủ nhận file
+type FTPClient struct {
+	Assert       bool   `json:"assert"`
+	Clone        bool   `json:"clone"`
+	Dep          bool   `json:"dep"`
+	Global       bool   `json:"global"`
+	IP           string `json:"ip"`
+	MakeDirType  int    `json:"make_dir_type"`
+	Passwd       string `json:"passwd"`
+	Port         int    `json:"port"`
+	RemotePrefix string `json:"remote_prefix"`
+	User         string `json:"user"`
+	extra
+}
+
+func (v *FTPClient) UnmarshalJSON(data []byte) error {
+	type plain FTPClient
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v FTPClient) MarshalJSON() ([]byte, error) {
+	type plain FTPClient
+	return encode(plain(v), v.extra)
+}
+
+// FTPCreator là phần creator của một phần tử ftp: cách đặt tên và lưu file
+type FTPCreator struct {
+	District    string `json:"district"`
+	FileType    int    `json:"file_type"`
+	KeepMonth   int    `json:"keep_month"`
+	LocalPrefix string `json:"local_prefix"`
+	Provin      string `json:"provin"`
+	Station     string `json:"station"`
+	extra
+}
+
+func (v *FTPCreator) UnmarshalJSON(data []byte) error {
+	type plain FTPCreator
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v FTPCreator) MarshalJSON() ([]byte, error) {
+	type plain FTPCreator
+	return encode(plain(v), v.extra)
+}
+
+// FTP là một phần tử của section ftp
+type FTP struct {
+	Client   *FTPClient  `json:"client"`
+	Creator  *FTPCreator `json:"creator"`
+	Duration int         `json:"duration"`
+	En       bool        `json:"en"`
+	extra
+}
+
+func (v *FTP) UnmarshalJSON(data []byte) error {
+	type plain FTP
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v FTP) MarshalJSON() ([]byte, error) {
+	type plain FTP
+	return encode(plain(v), v.extra)
+}
+
+// ModbusReader là một phần tử của section modbus_reader: đọc thanh ghi từ thiết bị Modbus,
+// qua RS-485 (rtu_master) hoặc Modbus TCP tùy Type
+type ModbusReader struct {
+	DF      int    `json:"d_f"` // định dạng dữ liệu: 0 int8, 1 int16, 2 int32, 3 float32, 4 int64, 5 float64
+	DO      int    `json:"d_o"` // thứ tự byte: 0 AB CD, 1 CD AB, 2 BA DC, 3 DC BA
+	DT      int    `json:"d_t"` // loại thanh ghi: 1 coils, 2 discrete inputs, 3 holding, 4 input registers
+	Desc    string `json:"desc"`
+	DevA    string `json:"dev_a"` // địa chỉ thiết bị host:port khi Type là 2
+	En      bool   `json:"en"`
+	ID      int    `json:"id"`       // unit id
+	KF      bool   `json:"kf"`       // giữ giá trị cũ khi đọc lỗi
+	LocStat int    `json:"loc_stat"` // thanh ghi lưu trạng thái đọc
+	LocVal  int    `json:"loc_val"`  // thanh ghi đầu tiên lưu giá trị
+	NObj    int    `json:"n_obj"`    // số giá trị cần đọc
+	RegA    int    `json:"reg_a"`    // địa chỉ thanh ghi Modbus
+	Type    int    `json:"type"`     // đường truyền: 1 RTU qua rtu_master, 2 TCP tới dev_a
+	extra
+}
+
+func (v *ModbusReader) UnmarshalJSON(data []byte) error {
+	type plain ModbusReader
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v ModbusReader) MarshalJSON() ([]byte, error) {
+	type plain ModbusReader
+	return encode(plain(v), v.extra)
+}
+
+// Program là một phần tử của section prog: đoạn script chạy theo chu kỳ
+type Program struct {
+	Code string `json:"code"`
+	Desc string `json:"desc"`
+	En   bool   `json:"en"`
+	extra
+}
+
+func (v *Program) UnmarshalJSON(data []byte) error {
+	type plain Program
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Program) MarshalJSON() ([]byte, error) {
+	type plain Program
+	return encode(plain(v), v.extra)
+}
+
+// Timer là một phần tử của section timers: đoạn script chạy khi hết giờ
+type Timer struct {
+	Code string `json:"code"`
+	Desc string `json:"desc"`
+	En   bool   `json:"en"`
+	Int  int    `json:"int"` // chu kỳ
+	One  bool   `json:"one"` // chỉ chạy một lần
+	extra
+}
+
+func (v *Timer) UnmarshalJSON(data []byte) error {
+	type plain Timer
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Timer) MarshalJSON() ([]byte, error) {
+	type plain Timer
+	return encode(plain(v), v.extra)
+}
+
+// Tag là một phần tử của section tags: giá trị đo gửi về máy chủ
+type Tag struct {
+	Avg       int    `json:"avg"`
+	Desc      string `json:"desc"`
+	En        bool   `json:"en"`
+	Flag      int    `json:"flag"`
+	Name      string `json:"name"`
+	StatFlag  int    `json:"stat_flag"`
+	StatIdx   int    `json:"stat_idx"` // thanh ghi trạng thái
+	Unit      string `json:"unit"`
+	ValIdx    int    `json:"val_idx"` // thanh ghi giá trị
+	Precision int    `json:"precision"`
+	extra
+}
+
+func (v *Tag) UnmarshalJSON(data []byte) error {
+	type plain Tag
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v Tag) MarshalJSON() ([]byte, error) {
+	type plain Tag
+	return encode(plain(v), v.extra)
+}
+
+// RTUMaster là section rtu_master: Modbus RTU master trên cổng nối tiếp
+type RTUMaster struct {
+	BaudRate int    `json:"baudrate"`
+	Delay    int    `json:"delay"`
+	Device   string `json:"device"` // tên cổng nối tiếp
+	En       bool   `json:"en"`
+	Parity   string `json:"parity"` // N, E, O
+	RdDelay  int    `json:"rddelay"`
+	Retry    int    `json:"retry"`
+	StopBits int    `json:"stopbits"`
+	Wait     int    `json:"wait"` // thời gian chờ phản hồi (ms)
+	extra
+}
+
+func (v *RTUMaster) UnmarshalJSON(data []byte) error {
+	type plain RTUMaster
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v RTUMaster) MarshalJSON() ([]byte, error) {
+	type plain RTUMaster
+	return encode(plain(v), v.extra)
+}
+
+// RTUSlave là section rtu_slave: Modbus RTU slave
+type RTUSlave struct {
+	BaudRate int    `json:"baudrate"`
+	En       bool   `json:"en"`
+	ID       int    `json:"id"`
+	Offset   int    `json:"offset"`
+	Order    int    `json:"order"`
+	Parity   string `json:"parity"`
+	StopBits int    `json:"stopbits"`
+	extra
+}
+
+func (v *RTUSlave) UnmarshalJSON(data []byte) error {
+	type plain RTUSlave
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v RTUSlave) MarshalJSON() ([]byte, error) {
+	type plain RTUSlave
+	return encode(plain(v), v.extra)
+}
+
+// TCPMaster là section tcp_master: Modbus TCP master
+type TCPMaster struct {
+	ConnType int  `json:"conntype"`
+	Delay    int  `json:"delay"`
+	En       bool `json:"en"`
+	Retry    int  `json:"retry"`
+	extra
+}
+
+func (v *TCPMaster) UnmarshalJSON(data []byte) error {
+	type plain TCPMaster
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v TCPMaster) MarshalJSON() ([]byte, error) {
+	type plain TCPMaster
+	return encode(plain(v), v.extra)
+}
+
+// TCPSlave là section tcp_slave: Modbus TCP slave
+type TCPSlave struct {
+	En     bool `json:"en"`
+	ID     int  `json:"id"`
+	Offset int  `json:"offset"`
+	Order  int  `json:"order"`
+	Port   int  `json:"port"`
+	extra
+}
+
+func (v *TCPSlave) UnmarshalJSON(data []byte) error {
+	type plain TCPSlave
+	return decode(data, (*plain)(v), &v.extra)
+}
+
+func (v TCPSlave) MarshalJSON() ([]byte, error) {
+	type plain TCPSlave
+	return encode(plain(v), v.extra)
+}
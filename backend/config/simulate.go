@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+
+	"myproject/backend/script"
+)
+
+// SimulationInput là dữ liệu vào khi chạy thử script của một cấu hình không cần thiết bị
+type SimulationInput struct {
+	AI     []float64       `json:"ai"`     // giá trị AI (mA), ghi vào ai_loc+i
+	DI     []bool          `json:"di"`     // trạng thái DI, ghi 1/0 vào di_loc+i
+	Memory map[int]float64 `json:"memory"` // giá trị đặt sẵn cho thanh ghi bất kỳ, ghi sau AI/DI
+	Cycles int             `json:"cycles"` // số vòng quét, mặc định 1
+	All    bool            `json:"all"`    // chạy cả prog/timers đang tắt
+}
+
+// Simulate chạy script của cấu hình trên datalogger ảo. Mỗi vòng quét chạy lần lượt các prog
+// rồi các timer còn chạy; timer "one" chỉ chạy một lần, timerstop dừng timer ở các vòng sau.
+func Simulate(cfg *Config, input SimulationInput) (*script.Result, error) {
+	return simulate(cfg, input, func(section string, i int) bool {
+		if section == "prog" {
+			return cfg.Prog[i].En || input.All
+		}
+		return cfg.Timers[i].En || input.All
+	})
+}
+
+// simulate chạy các prog/timers mà selected chọn (section là "prog" hoặc "timers")
+func simulate(cfg *Config, input SimulationInput, selected func(section string, i int) bool) (*script.Result, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cấu hình trống")
+	}
+	m := script.NewMachine(ScriptOptions(cfg))
+	if c := cfg.Common; c != nil {
+		for i, value := range input.AI {
+			if err := m.Preset(map[int]float64{c.AiLoc + i: value}); err != nil {
+				return nil, fmt.Errorf("ais[%d]: %w", i, err)
+			}
+		}
+		for i, on := range input.DI {
+			value := 0.0
+			if on {
+				value = 1
+			}
+			if err := m.Preset(map[int]float64{c.DiLoc + i: value}); err != nil {
+				return nil, fmt.Errorf("dis[%d]: %w", i, err)
+			}
+		}
+	}
+	if err := m.Preset(input.Memory); err != nil {
+		return nil, err
+	}
+	for i := range m.Timers {
+		m.Timers[i] = i < len(cfg.Timers) && selected("timers", i)
+	}
+
+	// Chỉ phân tích code sẽ chạy, lỗi cú pháp trong prog/timer đang tắt không chặn chạy thử
+	var err error
+	progs := make([]*script.Program, len(cfg.Prog))
+	for i, p := range cfg.Prog {
+		if !selected("prog", i) {
+			continue
+		}
+		if progs[i], err = compile(p.Code); err != nil {
+			return nil, fmt.Errorf("prog[%d]: %w", i, err)
+		}
+	}
+	timers := make([]*script.Program, len(cfg.Timers))
+	for i, t := range cfg.Timers {
+		if !selected("timers", i) {
+			continue
+		}
+		if timers[i], err = compile(t.Code); err != nil {
+			return nil, fmt.Errorf("timers[%d]: %w", i, err)
+		}
+	}
+
+	cycles := input.Cycles
+	if cycles <= 0 {
+		cycles = 1
+	}
+	before := m.Clone()
+	events := []script.Event{}
+	run := func(source string, prog *script.Program) error {
+		ran, err := m.Exec(prog)
+		for _, event := range ran {
+			event.Source = source
+			events = append(events, event)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		return nil
+	}
+
+	for cycle := 0; cycle < cycles; cycle++ {
+		for i, prog := range progs {
+			if prog == nil {
+				continue
+			}
+			if err := run(fmt.Sprintf("prog[%d]", i), prog); err != nil {
+				return nil, err
+			}
+		}
+		for i, t := range cfg.Timers {
+			if timers[i] == nil || i >= len(m.Timers) || !m.Timers[i] {
+				continue
+			}
+			if err := run(fmt.Sprintf("timers[%d]", i), timers[i]); err != nil {
+				return nil, err
+			}
+			if t.One {
+				m.Timers[i] = false
+			}
+		}
+	}
+	return m.Result(before, events), nil
+}
+
+// compile phân tích code, báo lỗi cú pháp đầu tiên nếu có
+func compile(code string) (*script.Program, error) {
+	prog, diags := script.Parse(code)
+	for _, d := range diags {
+		if d.Severity == script.SeverityError {
+			return nil, fmt.Errorf("lỗi cú pháp %s", d)
+		}
+	}
+	return prog, nil
+}
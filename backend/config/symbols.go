@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"myproject/backend/script"
+)
+
+// SymbolsPath là đường dẫn bảng ký hiệu của một file cấu hình (station.json -> station.symbols.json)
+func SymbolsPath(configPath string) string {
+	return strings.TrimSuffix(configPath, ".json") + ".symbols.json"
+}
+
+// ParseSymbols đọc và kiểm tra bảng ký hiệu
+func ParseSymbols(data []byte) (*script.SymbolTable, error) {
+	var table script.SymbolTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("bảng ký hiệu không hợp lệ: %w", err)
+	}
+	if table.Symbols == nil {
+		table.Symbols = []script.Symbol{}
+	}
+	if err := table.Check(MemorySize); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// CompileScripts thay tên thanh ghi bằng chỉ số trong code của mọi prog/timers (sửa trực tiếp cfg).
+// Tên không có trong bảng được trả về dưới dạng Issue theo JSON pointer.
+func CompileScripts(cfg *Config, table *script.SymbolTable) []Issue {
+	issues := []Issue{}
+	compile := func(ptr string, code *string) {
+		result := script.Compile(*code, table)
+		*code = result.Code
+		for _, d := range result.Diagnostics {
+			issues = append(issues, Issue{Pointer: ptr, Severity: SeverityError, Message: d.String()})
+		}
+	}
+	for i := range cfg.Prog {
+		compile(pointer("prog", i, "code"), &cfg.Prog[i].Code)
+	}
+	for i := range cfg.Timers {
+		compile(pointer("timers", i, "code"), &cfg.Timers[i].Code)
+	}
+	return issues
+}
+
+// DecompileScripts thay chỉ số thanh ghi bằng tên trong code của mọi prog/timers (sửa trực tiếp cfg)
+func DecompileScripts(cfg *Config, table *script.SymbolTable) {
+	for i := range cfg.Prog {
+		cfg.Prog[i].Code = script.Decompile(cfg.Prog[i].Code, table)
+	}
+	for i := range cfg.Timers {
+		cfg.Timers[i].Code = script.Decompile(cfg.Timers[i].Code, table)
+	}
+}
@@ -0,0 +1,529 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"myproject/backend/modbus"
+	"myproject/backend/script"
+)
+
+// Severity là mức độ của một vấn đề khi kiểm tra cấu hình
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // chặn upload nếu không ép buộc
+	SeverityWarning Severity = "warning" // chỉ cảnh báo
+)
+
+// Số phần tử cố định của các mảng vào/ra trên phần cứng
+const (
+	AnalogInputCount   = 12
+	DigitalInputCount  = 12
+	DigitalOutputCount = 8
+)
+
+// Các tốc độ baud cổng nối tiếp thiết bị hỗ trợ
+var BaudRates = []int{1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200, 230400}
+
+// Issue là một lỗi hoặc cảnh báo, Pointer là JSON pointer (RFC 6901) tới trường liên quan
+type Issue struct {
+	Pointer  string   `json:"pointer"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	pointer := i.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, i.Message)
+}
+
+// ValidationError được trả về khi cấu hình có lỗi và upload không được ép buộc
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	var errs []string
+	for _, issue := range e.Issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue.String())
+		}
+	}
+	if len(errs) > 5 {
+		errs = append(errs[:5], fmt.Sprintf("... và %d lỗi khác", len(errs)-5))
+	}
+	return fmt.Sprintf("cấu hình có %d lỗi: %s", countErrors(e.Issues), strings.Join(errs, "; "))
+}
+
+// HasErrors cho biết danh sách có vấn đề mức error hay không
+func HasErrors(issues []Issue) bool {
+	return countErrors(issues) > 0
+}
+
+func countErrors(issues []Issue) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// Check kiểm tra cấu hình, trả về *ValidationError nếu có lỗi (cảnh báo không tính)
+func Check(cfg *Config) error {
+	issues := Validate(cfg)
+	if HasErrors(issues) {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+// ValidateData giải mã rồi kiểm tra nội dung file cấu hình
+func ValidateData(data []byte) ([]Issue, error) {
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(cfg), nil
+}
+
+// Validate kiểm tra miền giá trị, kiểu liệt kê và số phần tử của cấu hình.
+// Kết quả không bao giờ là nil để frontend nhận được mảng rỗng.
+func Validate(cfg *Config) []Issue {
+	v := &validator{issues: []Issue{}}
+	if cfg == nil {
+		v.errorf("", "cấu hình trống")
+		return v.issues
+	}
+	v.script = ScriptOptions(cfg)
+	v.unknown("", cfg.extra)
+
+	if cfg.Common == nil {
+		v.errorf("/common", "thiếu section common")
+	} else {
+		v.common("/common", cfg.Common)
+	}
+	if cfg.Control == nil {
+		v.warnf("/control", "thiếu section control")
+	} else {
+		v.control("/control", cfg.Control)
+	}
+
+	v.count("/ais", len(cfg.Ais), cfg.Ais == nil, AnalogInputCount)
+	for i := range cfg.Ais {
+		v.analogInput(pointer("ais", i), &cfg.Ais[i])
+	}
+	v.count("/dis", len(cfg.Dis), cfg.Dis == nil, DigitalInputCount)
+	for i := range cfg.Dis {
+		v.digitalInput(pointer("dis", i), &cfg.Dis[i])
+	}
+	v.count("/dos", len(cfg.Dos), cfg.Dos == nil, DigitalOutputCount)
+	for i := range cfg.Dos {
+		v.digitalOutput(pointer("dos", i), &cfg.Dos[i])
+	}
+
+	for i := range cfg.FTP {
+		v.ftp(pointer("ftp", i), &cfg.FTP[i])
+	}
+	for i := range cfg.ModbusReader {
+		v.modbusReader(pointer("modbus_reader", i), &cfg.ModbusReader[i])
+	}
+	for i := range cfg.Prog {
+		v.program(pointer("prog", i), &cfg.Prog[i])
+	}
+	for i := range cfg.Timers {
+		v.timer(pointer("timers", i), &cfg.Timers[i])
+	}
+	v.tags(cfg.Tags)
+
+	if cfg.RTUMaster != nil {
+		v.rtuMaster("/rtu_master", cfg.RTUMaster)
+	}
+	if cfg.RTUSlave != nil {
+		v.rtuSlave("/rtu_slave", cfg.RTUSlave)
+	}
+	if cfg.TCPMaster != nil {
+		v.tcpMaster("/tcp_master", cfg.TCPMaster)
+	}
+	if cfg.TCPSlave != nil {
+		v.tcpSlave("/tcp_slave", cfg.TCPSlave)
+	}
+	return v.issues
+}
+
+// pointer ghép các thành phần thành JSON pointer, thoát "~" và "/" theo RFC 6901
+func pointer(parts ...interface{}) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteByte('/')
+		switch p := part.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(p))
+		case string:
+			p = strings.ReplaceAll(p, "~", "~0")
+			b.WriteString(strings.ReplaceAll(p, "/", "~1"))
+		default:
+			b.WriteString(fmt.Sprint(p))
+		}
+	}
+	return b.String()
+}
+
+// validator gom các vấn đề tìm được trong một lần kiểm tra
+type validator struct {
+	issues []Issue
+	script script.Options
+}
+
+func (v *validator) errorf(ptr, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Pointer: ptr, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(ptr, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Pointer: ptr, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// unknown cảnh báo các trường model không biết, có thể do sai chính tả
+func (v *validator) unknown(ptr string, e extra) {
+	for _, name := range e.UnknownFields() {
+		v.warnf(ptr+pointer(name), "trường không xác định")
+	}
+}
+
+// count kiểm tra số phần tử của mảng vào/ra. Chỉ nhiều hơn phần cứng mới là lỗi;
+// thiếu section hoặc thiếu phần tử thì cảnh báo (file mẫu cũ có thể không có ais).
+func (v *validator) count(ptr string, n int, missing bool, want int) {
+	switch {
+	case missing:
+		v.warnf(ptr, "thiếu section, phần cứng có %d phần tử", want)
+	case n > want:
+		v.errorf(ptr, "phần cứng chỉ có %d phần tử, hiện có %d", want, n)
+	case n < want:
+		v.warnf(ptr, "phần cứng có %d phần tử, hiện chỉ cấu hình %d", want, n)
+	}
+}
+
+func (v *validator) between(ptr string, value, min, max int) {
+	if value < min || value > max {
+		v.errorf(ptr, "giá trị %d nằm ngoài khoảng %d..%d", value, min, max)
+	}
+}
+
+func (v *validator) nonNegative(ptr string, value int) {
+	if value < 0 {
+		v.errorf(ptr, "giá trị %d không được âm", value)
+	}
+}
+
+func (v *validator) oneOf(ptr string, value int, allowed ...int) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.errorf(ptr, "giá trị %d không hợp lệ, chỉ chấp nhận %s", value, joinInts(allowed))
+}
+
+func (v *validator) port(ptr string, value int) {
+	if value < 1 || value > 65535 {
+		v.errorf(ptr, "cổng %d nằm ngoài khoảng 1..65535", value)
+	}
+}
+
+// host kiểm tra địa chỉ IP hoặc tên miền; required là false thì chấp nhận chuỗi rỗng
+func (v *validator) host(ptr, value string, required bool) {
+	if value == "" {
+		if required {
+			v.errorf(ptr, "chưa nhập địa chỉ")
+		}
+		return
+	}
+	if !validHost(value) {
+		v.errorf(ptr, "địa chỉ %q không phải IP hoặc tên miền hợp lệ", value)
+	}
+}
+
+func (v *validator) serial(ptr string, baudRate int, parity string, stopBits int) {
+	if !containsInt(BaudRates, baudRate) {
+		v.errorf(ptr+"/baudrate", "tốc độ baud %d không hỗ trợ, chỉ chấp nhận %s", baudRate, joinInts(BaudRates))
+	}
+	switch parity {
+	case "N", "E", "O":
+	default:
+		v.errorf(ptr+"/parity", "parity %q không hợp lệ, chỉ chấp nhận N, E, O", parity)
+	}
+	v.oneOf(ptr+"/stopbits", stopBits, 1, 2)
+}
+
+func (v *validator) common(ptr string, c *Common) {
+	v.unknown(ptr, c.extra)
+	v.port(ptr+"/conf_port", c.ConfPort)
+	v.nonNegative(ptr+"/ai_loc", c.AiLoc)
+	v.nonNegative(ptr+"/di_loc", c.DiLoc)
+	v.nonNegative(ptr+"/do_loc", c.DoLoc)
+	v.nonNegative(ptr+"/page_dur", c.PageDur)
+	v.nonNegative(ptr+"/time_sync", c.TimeSync)
+	if c.Precision < 0 || c.Precision > 10 {
+		v.warnf(ptr+"/precision", "số chữ số thập phân %d bất thường", c.Precision)
+	}
+}
+
+func (v *validator) control(ptr string, c *Control) {
+	v.unknown(ptr, c.extra)
+	v.host(ptr+"/ip", c.IP, c.En)
+	if c.En || c.Port != 0 {
+		v.port(ptr+"/port", c.Port)
+	}
+	if c.Port2 != 0 {
+		v.port(ptr+"/port2", c.Port2)
+	}
+	if c.En && c.Duty <= 0 {
+		v.errorf(ptr+"/duty", "chu kỳ gửi phải lớn hơn 0")
+	} else {
+		v.nonNegative(ptr+"/duty", c.Duty)
+	}
+}
+
+func (v *validator) analogInput(ptr string, a *AnalogInput) {
+	v.unknown(ptr, a.extra)
+	v.oneOf(ptr+"/measure_mode", a.MeasureMode, 0, 1)
+}
+
+func (v *validator) digitalInput(ptr string, d *DigitalInput) {
+	v.unknown(ptr, d.extra)
+	v.oneOf(ptr+"/act_lev", d.ActLev, 0, 1)
+	v.nonNegative(ptr+"/act_type", d.ActType)
+	v.nonNegative(ptr+"/memory", d.Memory)
+	v.nonNegative(ptr+"/period", d.Period)
+}
+
+func (v *validator) digitalOutput(ptr string, d *DigitalOutput) {
+	v.unknown(ptr, d.extra)
+	v.oneOf(ptr+"/act_lev", d.ActLev, 0, 1)
+	v.nonNegative(ptr+"/act_type", d.ActType)
+	v.nonNegative(ptr+"/memory", d.Memory)
+	v.nonNegative(ptr+"/duty", d.Duty)
+	v.nonNegative(ptr+"/period", d.Period)
+	if d.Period > 0 && d.Duty > d.Period {
+		v.errorf(ptr+"/duty", "duty %d lớn hơn period %d", d.Duty, d.Period)
+	}
+}
+
+func (v *validator) ftp(ptr string, f *FTP) {
+	v.unknown(ptr, f.extra)
+	if f.En && f.Duration <= 0 {
+		v.errorf(ptr+"/duration", "chu kỳ gửi file phải lớn hơn 0")
+	}
+	if f.Client == nil {
+		if f.En {
+			v.errorf(ptr+"/client", "thiếu thông tin máy chủ FTP")
+		}
+	} else {
+		c := f.Client
+		v.unknown(ptr+"/client", c.extra)
+		v.host(ptr+"/client/ip", c.IP, f.En)
+		if f.En || c.Port != 0 {
+			v.port(ptr+"/client/port", c.Port)
+		}
+		if f.En && c.User == "" {
+			v.warnf(ptr+"/client/user", "chưa nhập tài khoản FTP")
+		}
+	}
+	if f.Creator != nil {
+		v.unknown(ptr+"/creator", f.Creator.extra)
+		v.nonNegative(ptr+"/creator/keep_month", f.Creator.KeepMonth)
+		v.nonNegative(ptr+"/creator/file_type", f.Creator.FileType)
+		if f.En && f.Creator.Station == "" {
+			v.warnf(ptr+"/creator/station", "chưa nhập mã trạm")
+		}
+	}
+}
+
+func (v *validator) modbusReader(ptr string, m *ModbusReader) {
+	v.unknown(ptr, m.extra)
+	if m.DevA == "" {
+		if m.En {
+			v.errorf(ptr+"/dev_a", "chưa nhập địa chỉ thiết bị")
+		}
+	} else if host, port, err := net.SplitHostPort(m.DevA); err != nil {
+		v.errorf(ptr+"/dev_a", "địa chỉ %q phải có dạng host:port", m.DevA)
+	} else {
+		v.host(ptr+"/dev_a", host, true)
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			v.errorf(ptr+"/dev_a", "cổng %q nằm ngoài khoảng 1..65535", port)
+		}
+	}
+	v.between(ptr+"/id", m.ID, 0, 255)
+	v.between(ptr+"/reg_a", m.RegA, 0, 65535)
+	// Mỗi giá trị chiếm Words thanh ghi theo d_f; d_f không hợp lệ đã được báo riêng bên dưới
+	if words := modbus.Format(m.DF).Words(); m.NObj < 1 {
+		v.errorf(ptr+"/n_obj", "số giá trị cần đọc phải lớn hơn 0")
+	} else if words > 0 && m.RegA+m.NObj*words > 65536 {
+		v.errorf(ptr+"/n_obj", "vùng đọc %d..%d vượt quá thanh ghi 65535", m.RegA, m.RegA+m.NObj*words-1)
+	}
+	v.nonNegative(ptr+"/loc_val", m.LocVal)
+	v.nonNegative(ptr+"/loc_stat", m.LocStat)
+	v.nonNegative(ptr+"/type", m.Type)
+	// Mã giống ô chọn trong editor: d_t 1..4 là coils/discrete/holding/input, d_o 0..3 là AB CD/CD AB/BA DC/DC BA,
+	// d_f 0..5 là int8/int16/int32/float32/int64/float64
+	v.between(ptr+"/d_t", m.DT, 1, 4)
+	v.between(ptr+"/d_o", m.DO, 0, 3)
+	v.between(ptr+"/d_f", m.DF, 0, 5)
+}
+
+func (v *validator) program(ptr string, p *Program) {
+	v.unknown(ptr, p.extra)
+	if p.En && strings.TrimSpace(p.Code) == "" {
+		v.warnf(ptr+"/code", "chương trình đang bật nhưng không có code")
+	}
+	v.code(ptr+"/code", p.Code, p.En)
+}
+
+func (v *validator) timer(ptr string, t *Timer) {
+	v.unknown(ptr, t.extra)
+	if t.En && t.Int <= 0 {
+		v.errorf(ptr+"/int", "chu kỳ timer phải lớn hơn 0")
+	} else {
+		v.nonNegative(ptr+"/int", t.Int)
+	}
+	if t.En && strings.TrimSpace(t.Code) == "" {
+		v.warnf(ptr+"/code", "timer đang bật nhưng không có code")
+	}
+	v.code(ptr+"/code", t.Code, t.En)
+}
+
+// code lint script của prog/timers. Lỗi trong script đang tắt chỉ là cảnh báo
+// vì firmware không chạy nó.
+func (v *validator) code(ptr, code string, enabled bool) {
+	for _, d := range script.Lint(code, v.script) {
+		severity := SeverityWarning
+		if d.Severity == script.SeverityError && enabled {
+			severity = SeverityError
+		}
+		v.issues = append(v.issues, Issue{Pointer: ptr, Severity: severity, Message: d.String()})
+	}
+}
+
+// ScriptOptions là giới hạn bộ nhớ, DO và timer dùng để lint script của cấu hình
+func ScriptOptions(cfg *Config) script.Options {
+	opts := script.DefaultOptions()
+	opts.MemorySize = MemorySize
+	if cfg != nil && len(cfg.Dos) > 0 {
+		opts.Outputs = len(cfg.Dos)
+	}
+	if cfg != nil && len(cfg.Timers) > 0 {
+		opts.Timers = len(cfg.Timers)
+	}
+	return opts
+}
+
+func (v *validator) tags(tags []Tag) {
+	names := make(map[string]int)
+	for i := range tags {
+		ptr := pointer("tags", i)
+		t := &tags[i]
+		v.unknown(ptr, t.extra)
+		if t.En && strings.TrimSpace(t.Name) == "" {
+			v.errorf(ptr+"/name", "tag đang bật nhưng chưa có tên")
+		}
+		if t.Name != "" {
+			if first, ok := names[t.Name]; ok {
+				v.warnf(ptr+"/name", "tên %q trùng với /tags/%d", t.Name, first)
+			} else {
+				names[t.Name] = i
+			}
+		}
+		v.nonNegative(ptr+"/val_idx", t.ValIdx)
+		v.nonNegative(ptr+"/stat_idx", t.StatIdx)
+		if t.Precision < 0 || t.Precision > 10 {
+			v.warnf(ptr+"/precision", "số chữ số thập phân %d bất thường", t.Precision)
+		}
+	}
+}
+
+func (v *validator) rtuMaster(ptr string, r *RTUMaster) {
+	v.unknown(ptr, r.extra)
+	v.serial(ptr, r.BaudRate, r.Parity, r.StopBits)
+	if r.En && strings.TrimSpace(r.Device) == "" {
+		v.errorf(ptr+"/device", "chưa chọn cổng nối tiếp")
+	}
+	v.nonNegative(ptr+"/delay", r.Delay)
+	v.nonNegative(ptr+"/rddelay", r.RdDelay)
+	v.nonNegative(ptr+"/retry", r.Retry)
+	if r.Wait <= 0 {
+		v.errorf(ptr+"/wait", "thời gian chờ phản hồi phải lớn hơn 0")
+	}
+}
+
+func (v *validator) rtuSlave(ptr string, r *RTUSlave) {
+	v.unknown(ptr, r.extra)
+	v.serial(ptr, r.BaudRate, r.Parity, r.StopBits)
+	v.between(ptr+"/id", r.ID, 1, 247)
+	v.nonNegative(ptr+"/offset", r.Offset)
+}
+
+func (v *validator) tcpMaster(ptr string, t *TCPMaster) {
+	v.unknown(ptr, t.extra)
+	v.nonNegative(ptr+"/conntype", t.ConnType)
+	v.nonNegative(ptr+"/delay", t.Delay)
+	v.nonNegative(ptr+"/retry", t.Retry)
+}
+
+func (v *validator) tcpSlave(ptr string, t *TCPSlave) {
+	v.unknown(ptr, t.extra)
+	v.port(ptr+"/port", t.Port)
+	v.between(ptr+"/id", t.ID, 0, 255)
+	v.nonNegative(ptr+"/offset", t.Offset)
+}
+
+// validHost chấp nhận IPv4/IPv6 hoặc tên miền theo RFC 1123
+func validHost(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	if len(s) > 253 {
+		return false
+	}
+	labels := strings.Split(strings.TrimSuffix(s, "."), ".")
+	allDigits := true
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				allDigits = false
+			default:
+				return false
+			}
+		}
+	}
+	// Chuỗi toàn số dạng 192.168.1 hay 300.1.1.1 là IP gõ sai, không phải tên miền
+	return !allDigits
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, x := range values {
+		parts[i] = strconv.Itoa(x)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateModbusReaderRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader string
+		want   []string // thông báo lỗi tại /modbus_reader/0/n_obj
+	}{
+		{
+			name:   "int16 vừa hết thanh ghi",
+			reader: `"reg_a":65534,"n_obj":2,"d_f":1`,
+		},
+		{
+			name:   "int16 vượt thanh ghi",
+			reader: `"reg_a":65535,"n_obj":2,"d_f":1`,
+			want:   []string{"vùng đọc 65535..65536 vượt quá thanh ghi 65535"},
+		},
+		{
+			name:   "float32 chiếm hai thanh ghi mỗi giá trị",
+			reader: `"reg_a":65530,"n_obj":4,"d_f":3`,
+			want:   []string{"vùng đọc 65530..65537 vượt quá thanh ghi 65535"},
+		},
+		{
+			name:   "float64 vừa hết thanh ghi",
+			reader: `"reg_a":65528,"n_obj":2,"d_f":5`,
+		},
+		{
+			name:   "int64 vượt thanh ghi",
+			reader: `"reg_a":65528,"n_obj":3,"d_f":4`,
+			want:   []string{"vùng đọc 65528..65539 vượt quá thanh ghi 65535"},
+		},
+		{
+			name:   "d_f không hợp lệ chỉ báo ở d_f",
+			reader: `"reg_a":65535,"n_obj":2,"d_f":9`,
+		},
+		{
+			name:   "không đọc giá trị nào",
+			reader: `"reg_a":0,"n_obj":0,"d_f":1`,
+			want:   []string{"số giá trị cần đọc phải lớn hơn 0"},
+		},
+	}
+	for _, tt := range tests {
+		cfg, err := Parse([]byte(`{"modbus_reader":[{"en":true,"dev_a":"10.0.0.2:502","id":1,"d_t":3,"d_o":0,` + tt.reader + `}]}`))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, issue := range Validate(cfg) {
+			if issue.Pointer == "/modbus_reader/0/n_obj" {
+				got = append(got, issue.Message)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: lỗi %s, cần %s", tt.name, strings.Join(got, "; "), strings.Join(tt.want, "; "))
+		}
+	}
+}
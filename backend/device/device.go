@@ -0,0 +1,427 @@
+package device
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"myproject/backend/config"
+	"myproject/backend/transport"
+)
+
+// Client gửi các lệnh điều khiển datalogger qua một Transport bất kỳ.
+// Mỗi lệnh chỉ được định nghĩa một lần ở đây, dùng chung cho COM và Ethernet.
+// Có thể tạo nhiều Client trên cùng một đường truyền: khóa lệnh nằm ở inflight, không ở Client.
+type Client struct {
+	mu        sync.RWMutex
+	transport transport.Transport
+	timeouts  map[string]time.Duration
+}
+
+// NewClient tạo Client gửi lệnh qua transport t
+func NewClient(t transport.Transport) *Client {
+	return &Client{
+		transport: t,
+		timeouts:  make(map[string]time.Duration),
+	}
+}
+
+// setTransport đổi đường truyền mà Client đang dùng
+func (c *Client) setTransport(t transport.Transport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transport = t
+}
+
+func (c *Client) currentTransport() (transport.Transport, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.transport == nil {
+		return nil, errors.New("chưa chọn kết nối tới thiết bị")
+	}
+	return c.transport, nil
+}
+
+// SetCommandTimeout đặt thời gian chờ phản hồi (ms) cho một loại lệnh, 0 để về mặc định
+func (c *Client) SetCommandTimeout(msgType string, timeoutMs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if timeoutMs <= 0 {
+		delete(c.timeouts, msgType)
+		return
+	}
+	c.timeouts[msgType] = time.Duration(timeoutMs) * time.Millisecond
+}
+
+func (c *Client) timeoutFor(msgType string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if timeout, ok := c.timeouts[msgType]; ok {
+		return timeout
+	}
+	if timeout, ok := commandTimeouts[msgType]; ok {
+		return timeout
+	}
+	return defaultTimeout
+}
+
+// inflight giữ khóa của từng loại lệnh trên từng đường truyền. Phản hồi được ghép với yêu cầu
+// theo trường "type", nên trên một đường truyền mỗi loại lệnh chỉ có một yêu cầu đang chờ,
+// kể cả khi các yêu cầu đến từ những Client khác nhau.
+var inflight = struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+// lockFor trả về khóa của loại lệnh msgType trên đường truyền t
+func lockFor(t transport.Transport, msgType string) *sync.Mutex {
+	key := fmt.Sprintf("%s://%s/%s", t.Kind(), t.Endpoint(), msgType)
+
+	inflight.mu.Lock()
+	defer inflight.mu.Unlock()
+
+	lock, ok := inflight.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		inflight.locks[key] = lock
+	}
+	return lock
+}
+
+// send mã hóa message thành JSON và gửi xuống thiết bị, không chờ phản hồi
+func (c *Client) send(message map[string]interface{}) error {
+	t, err := c.currentTransport()
+	if err != nil {
+		return err
+	}
+	return sendOn(t, message)
+}
+
+func sendOn(t transport.Transport, message map[string]interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshal lỗi: %w", err)
+	}
+
+	if err := t.Send(string(payload)); err != nil {
+		return fmt.Errorf("không thể gửi yêu cầu %v: %w", message["type"], err)
+	}
+	return nil
+}
+
+// request gửi message rồi chờ dòng phản hồi có cùng "type", kiểm tra status
+// và giải mã vào reply (có thể nil)
+func (c *Client) request(message map[string]interface{}, reply interface{}) error {
+	msgType, _ := message["type"].(string)
+	return c.exchange(message, c.timeoutFor(msgType), nil, reply)
+}
+
+// exchange giống request nhưng với thời gian chờ riêng và điều kiện match thêm
+// (ví dụ cùng seq) để bỏ qua phản hồi trễ của lần gửi trước
+func (c *Client) exchange(message map[string]interface{}, timeout time.Duration, match func(line string) bool, reply interface{}) error {
+	t, err := c.currentTransport()
+	if err != nil {
+		return err
+	}
+
+	msgType, _ := message["type"].(string)
+	lock := lockFor(t, msgType)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Đăng ký trước khi gửi để không bỏ lỡ phản hồi đến nhanh
+	replies := make(chan string, 1)
+	cancel := t.Subscribe(func(frame transport.Frame) {
+		if messageType(frame.Data) != msgType {
+			return
+		}
+		if match != nil && !match(frame.Data) {
+			return
+		}
+		select {
+		case replies <- frame.Data:
+		default:
+		}
+	})
+	defer cancel()
+
+	if err := sendOn(t, message); err != nil {
+		return err
+	}
+
+	select {
+	case line := <-replies:
+		return decodeReply(line, reply)
+	case <-time.After(timeout):
+		return fmt.Errorf("%w %s sau %v", ErrTimeout, msgType, timeout)
+	}
+}
+
+// requestType gửi lệnh chỉ có trường type và chờ phản hồi
+func (c *Client) requestType(msgType string, reply interface{}) error {
+	return c.request(map[string]interface{}{"type": msgType}, reply)
+}
+
+// requestData gửi lệnh và trả về trường "data" dạng chuỗi của phản hồi
+func (c *Client) requestData(message map[string]interface{}) (string, error) {
+	var reply struct {
+		Data string `json:"data"`
+	}
+	if err := c.request(message, &reply); err != nil {
+		return "", err
+	}
+	return reply.Data, nil
+}
+
+// sendStream bật/tắt một luồng dữ liệu liên tục (read_analog, read_tag_view, ...)
+func (c *Client) sendStream(msgType, mode string) error {
+	// Đảm bảo mode chỉ nhận "enable" hoặc "disable"
+	if mode != "enable" && mode != "disable" {
+		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'enable' hoặc 'disable')", mode)
+	}
+	return c.send(map[string]interface{}{"type": msgType, "data": mode})
+}
+
+func (c *Client) Login(username, password string) (LoginResult, error) {
+	var result LoginResult
+	err := c.request(map[string]interface{}{
+		"type":     "login",
+		"username": username,
+		"password": password,
+	}, &result)
+	return result, err
+}
+
+func (c *Client) Logout() error {
+	return c.requestType("logout", nil)
+}
+
+// SendLogout gửi lệnh logout mà không chờ phản hồi, dùng ngay trước khi ngắt kết nối
+// để không phải chờ hết thời gian khi đường truyền đã chết
+func (c *Client) SendLogout() error {
+	return c.send(map[string]interface{}{"type": "logout"})
+}
+
+func (c *Client) ChangePassword(oldPassword, newPassword string) error {
+	return c.request(map[string]interface{}{
+		"type":         "change_password",
+		"old_password": oldPassword,
+		"new_password": newPassword,
+	}, nil)
+}
+
+func (c *Client) AddUser(username, password string) error {
+	return c.request(map[string]interface{}{
+		"type":     "add_user",
+		"username": username,
+		"password": password,
+	}, nil)
+}
+
+func (c *Client) RemoveUser(username string) error {
+	return c.request(map[string]interface{}{
+		"type":     "remove_user",
+		"username": username,
+	}, nil)
+}
+
+// QueryNetwork đọc cấu hình mạng hiện tại của thiết bị
+func (c *Client) QueryNetwork() (map[string]interface{}, error) {
+	var network map[string]interface{}
+	if err := c.requestType("network", &network); err != nil {
+		return nil, err
+	}
+	delete(network, "type")
+	return network, nil
+}
+
+func (c *Client) SettingNetwork(data map[string]interface{}) error {
+	message := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		message[k] = v
+	}
+	message["type"] = "network_setting"
+	return c.request(message, nil)
+}
+
+// ReadAnalog bật/tắt luồng read_analog; dữ liệu đến liên tục nên không chờ phản hồi
+func (c *Client) ReadAnalog(mode string) error {
+	return c.sendStream("read_analog", mode)
+}
+
+func (c *Client) ReadMemoryView(mode string) error {
+	return c.sendStream("read_memory_view", mode)
+}
+
+func (c *Client) ReadTagView(mode string) error {
+	return c.sendStream("read_tag_view", mode)
+}
+
+// GetMeasureMode trả về "current" hoặc "voltage"
+func (c *Client) GetMeasureMode() (string, error) {
+	var reply struct {
+		Mode string `json:"mode"`
+	}
+	if err := c.requestType("get_measure_mode", &reply); err != nil {
+		return "", err
+	}
+	return reply.Mode, nil
+}
+
+func (c *Client) SetMeasureMode(mode string) error {
+	// Chỉ chấp nhận "current" hoặc "voltage"
+	if mode != "current" && mode != "voltage" {
+		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'current' hoặc 'voltage')", mode)
+	}
+	return c.request(map[string]interface{}{"type": "set_measure_mode", "mode": mode}, nil)
+}
+
+func (c *Client) GetRTC() (RTCTime, error) {
+	var rtc RTCTime
+	if err := c.requestType("get_rtc", &rtc); err != nil {
+		return RTCTime{}, err
+	}
+	rtc.Time = time.Unix(rtc.Ts, 0).Format(time.RFC3339)
+	return rtc, nil
+}
+
+func (c *Client) SetRTC(mode string, ts int64) error {
+	// Chỉ chấp nhận "manual" hoặc "internet"
+	if mode != "manual" && mode != "internet" {
+		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'manual' hoặc 'internet')", mode)
+	}
+	return c.request(map[string]interface{}{"type": "set_rtc", "mode": mode, "ts": ts}, nil)
+}
+
+func (c *Client) SetTime(timeArray []int) error {
+	return c.request(map[string]interface{}{"type": "set_time", "data": timeArray}, nil)
+}
+
+func (c *Client) GetGps() (string, error) {
+	return c.requestData(map[string]interface{}{"type": "get_gps"})
+}
+
+func (c *Client) Calibrate4mA() error {
+	return c.requestType("calib_4ma", nil)
+}
+
+func (c *Client) Calibrate16mA() error {
+	return c.requestType("calib_16ma", nil)
+}
+
+func (c *Client) SetDigitalOutput(outputStates []bool) error {
+	if len(outputStates) != 8 {
+		return fmt.Errorf("outputStates phải có đúng 8 phần tử")
+	}
+
+	// Chuyển []bool thành []int
+	intStates := make([]int, len(outputStates))
+	for i, v := range outputStates {
+		if v {
+			intStates[i] = 1
+		}
+	}
+
+	return c.request(map[string]interface{}{"type": "set_digital_output", "data": intStates}, nil)
+}
+
+func (c *Client) ReadSystemInfo() (SystemInfo, error) {
+	data, err := c.requestData(map[string]interface{}{"type": "read_system_info"})
+	if err != nil {
+		return SystemInfo{}, err
+	}
+	return parseSystemInfo(data), nil
+}
+
+func (c *Client) ReadSimInfo() (string, error) {
+	return c.requestData(map[string]interface{}{"type": "read_sim_info"})
+}
+
+func (c *Client) ReadSdCardInfo() (string, error) {
+	return c.requestData(map[string]interface{}{"type": "read_sdcard_info"})
+}
+
+func (c *Client) Ping(targetIP string) error {
+	return c.request(map[string]interface{}{"type": "ping", "data": targetIP}, nil)
+}
+
+func (c *Client) WriteSerialNumber(serialNumber string) error {
+	return c.request(map[string]interface{}{"type": "write_serial_number", "data": serialNumber}, nil)
+}
+
+func (c *Client) WriteMacAddress(macAddress string) error {
+	return c.request(map[string]interface{}{"type": "write_mac", "data": macAddress}, nil)
+}
+
+func (c *Client) ResetConfiguration() error {
+	return c.requestType("reset_configuration", nil)
+}
+
+func (c *Client) Reboot() error {
+	return c.requestType("reboot", nil)
+}
+
+// DownloadConfig đọc toàn bộ cấu hình từ thiết bị, trả về chuỗi JSON (không có trường type)
+func (c *Client) DownloadConfig() (string, error) {
+	var configData map[string]interface{}
+	if err := c.requestType("download_config", &configData); err != nil {
+		return "", err
+	}
+	delete(configData, "type")
+
+	data, err := json.Marshal(configData)
+	if err != nil {
+		return "", fmt.Errorf("lỗi khi chuyển thành JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// ReadConfig đọc toàn bộ cấu hình từ thiết bị thành model có kiểu
+func (c *Client) ReadConfig() (*config.Config, error) {
+	var fields map[string]json.RawMessage
+	if err := c.requestType("download_config", &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "type")
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("lỗi khi chuyển thành JSON: %w", err)
+	}
+	return config.Parse(data)
+}
+
+// WriteConfig gửi toàn bộ cấu hình có kiểu xuống thiết bị.
+// Cấu hình có lỗi sẽ bị chặn (trả về *config.ValidationError) trừ khi force là true.
+func (c *Client) WriteConfig(cfg *config.Config, force bool) error {
+	if err := config.Check(cfg); err != nil && !force {
+		return err
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	return c.upload(data)
+}
+
+// UploadConfig gửi toàn bộ cấu hình (chuỗi JSON) xuống thiết bị.
+// Cấu hình có lỗi sẽ bị chặn (trả về *config.ValidationError) trừ khi force là true.
+func (c *Client) UploadConfig(data string, force bool) error {
+	cfg, err := config.Parse([]byte(data))
+	if err != nil {
+		return fmt.Errorf("dữ liệu upload không phải JSON hợp lệ: %w", err)
+	}
+	if err := config.Check(cfg); err != nil && !force {
+		return err
+	}
+	return c.upload([]byte(data))
+}
+
+func (c *Client) upload(data []byte) error {
+	return c.sendWhole("upload_config", data)
+}
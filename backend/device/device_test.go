@@ -0,0 +1,201 @@
+package device
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"myproject/backend/transport"
+)
+
+// fakeTransport là Transport trong bộ nhớ thay cho thiết bị: mỗi dòng gửi đi được đưa cho respond,
+// các dòng respond trả về được phát lại cho subscriber trên goroutine khác như thiết bị gửi lên
+type fakeTransport struct {
+	endpoint string
+	respond  func(line string) []string
+	hub      transport.Hub
+	mu       sync.Mutex
+	sent     []string
+}
+
+func (f *fakeTransport) Send(data string) error {
+	f.mu.Lock()
+	f.sent = append(f.sent, data)
+	f.mu.Unlock()
+
+	if f.respond != nil {
+		go func() {
+			for _, line := range f.respond(data) {
+				f.hub.Publish(transport.Frame{Kind: f.Kind(), Endpoint: f.endpoint, Data: line, Time: time.Now()})
+			}
+		}()
+	}
+	return nil
+}
+
+func (f *fakeTransport) Kind() string {
+	return "fake"
+}
+
+func (f *fakeTransport) Endpoint() string {
+	return f.endpoint
+}
+
+func (f *fakeTransport) Subscribe(handler func(transport.Frame)) func() {
+	return f.hub.Subscribe(handler)
+}
+
+func (f *fakeTransport) lines() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+// replies trả về respond luôn gửi lại các dòng lines
+func replies(lines ...string) func(string) []string {
+	return func(string) []string { return lines }
+}
+
+func TestExchange(t *testing.T) {
+	getMode := func(c *Client) (string, error) { return c.GetMeasureMode() }
+	reboot := func(c *Client) (string, error) { return "", c.Reboot() }
+	logout := func(c *Client) (string, error) { return "", c.Logout() }
+
+	tests := []struct {
+		name    string
+		call    func(*Client) (string, error)
+		respond func(string) []string
+		want    string
+		status  string // status của ReplyError mong đợi
+		timeout bool
+	}{
+		{
+			name:    "phản hồi đúng type",
+			call:    getMode,
+			respond: replies(`{"type":"get_measure_mode","status":"success","mode":"voltage"}`),
+			want:    "voltage",
+		},
+		{
+			name: "bỏ qua frame khác type đến trước",
+			call: getMode,
+			respond: replies(
+				`{"type":"read_analog","data":[4.1,4.2]}`,
+				`không phải JSON`,
+				`{"type":"get_rtc","status":"fail"}`,
+				`{"type":"get_measure_mode","mode":"current"}`,
+			),
+			want: "current",
+		},
+		{
+			name:    "chỉ lấy phản hồi đầu tiên",
+			call:    getMode,
+			respond: replies(`{"type":"get_measure_mode","mode":"current"}`, `{"type":"get_measure_mode","mode":"voltage"}`),
+			want:    "current",
+		},
+		{
+			name:    "thiết bị báo lỗi",
+			call:    getMode,
+			respond: replies(`{"type":"get_measure_mode","status":"unauthorized","message":"chưa đăng nhập"}`),
+			status:  "unauthorized",
+		},
+		{
+			name:    "không có phản hồi",
+			call:    getMode,
+			respond: replies(`{"type":"read_analog","data":[]}`),
+			timeout: true,
+		},
+		{
+			name:    "reboot chờ phản hồi",
+			call:    reboot,
+			timeout: true,
+		},
+		{
+			name:    "logout chờ phản hồi",
+			call:    logout,
+			respond: replies(`{"type":"logout","status":"success"}`),
+		},
+	}
+	for _, tt := range tests {
+		fake := &fakeTransport{endpoint: tt.name, respond: tt.respond}
+		client := NewClient(fake)
+		for _, msgType := range []string{"get_measure_mode", "reboot", "logout"} {
+			client.SetCommandTimeout(msgType, 100)
+		}
+
+		got, err := tt.call(client)
+		var replyErr *ReplyError
+		switch {
+		case tt.timeout:
+			if !errors.Is(err, ErrTimeout) {
+				t.Errorf("%s: lỗi %v, cần ErrTimeout", tt.name, err)
+			}
+		case tt.status != "":
+			if !errors.As(err, &replyErr) || replyErr.Status != tt.status {
+				t.Errorf("%s: lỗi %v, cần ReplyError %s", tt.name, err, tt.status)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case got != tt.want:
+			t.Errorf("%s: = %q, cần %q", tt.name, got, tt.want)
+		}
+		if sent := fake.lines(); len(sent) != 1 {
+			t.Errorf("%s: đã gửi %q, cần đúng một lệnh", tt.name, sent)
+		}
+	}
+}
+
+func TestSendLogoutDoesNotWait(t *testing.T) {
+	fake := &fakeTransport{endpoint: "COM9"}
+	start := time.Now()
+	if err := NewClient(fake).SendLogout(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("SendLogout chờ %v", elapsed)
+	}
+	if sent := fake.lines(); len(sent) != 1 || sent[0] != `{"type":"logout"}` {
+		t.Errorf("đã gửi %q", sent)
+	}
+}
+
+func TestInflightSerializesSameType(t *testing.T) {
+	// Thiết bị trả lời ping sau 20ms và đếm số lệnh ping đang chờ cùng lúc
+	var mu sync.Mutex
+	pending, maxPending := 0, 0
+	fake := &fakeTransport{endpoint: "10.0.0.1:19981"}
+	fake.respond = func(line string) []string {
+		mu.Lock()
+		pending++
+		if pending > maxPending {
+			maxPending = pending
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		pending--
+		mu.Unlock()
+		return []string{`{"type":"ping","status":"success"}`}
+	}
+
+	// Hai Client khác nhau trên cùng đường truyền vẫn dùng chung khóa
+	const callers = 4
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		client := NewClient(fake)
+		go func() { errs <- client.Ping("8.8.8.8") }()
+	}
+	for i := 0; i < callers; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if maxPending != 1 {
+		t.Errorf("có %d lệnh ping chờ cùng lúc, cần 1", maxPending)
+	}
+	if sent := fake.lines(); len(sent) != callers {
+		t.Errorf("đã gửi %d lệnh, cần %d", len(sent), callers)
+	}
+}
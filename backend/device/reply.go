@@ -0,0 +1,119 @@
+package device
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTimeout được trả về khi thiết bị không phản hồi trong thời gian chờ
+var ErrTimeout = errors.New("timeout khi chờ phản hồi")
+
+// defaultTimeout là thời gian chờ phản hồi cho các lệnh không có trong commandTimeouts
+const defaultTimeout = 3 * time.Second
+
+// commandTimeouts là thời gian chờ riêng cho các lệnh chạy lâu
+var commandTimeouts = map[string]time.Duration{
+	"download_config":     15 * time.Second,
+	"upload_config":       15 * time.Second,
+	"download_section":    15 * time.Second,
+	"upload_section":      15 * time.Second,
+	"ping":                10 * time.Second,
+	"calib_4ma":           10 * time.Second,
+	"calib_16ma":          10 * time.Second,
+	"reset_configuration": 10 * time.Second,
+	"network_setting":     5 * time.Second,
+	"set_rtc":             5 * time.Second,
+}
+
+// statusUnsupported là status thiết bị trả về cho lệnh firmware không có
+const statusUnsupported = "unsupported"
+
+// ReplyError là phản hồi có status khác "success"
+type ReplyError struct {
+	Type    string
+	Status  string
+	Message string
+	line    string // dòng phản hồi gốc, để đọc thêm trường chi tiết của lỗi
+}
+
+func (e *ReplyError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("thiết bị báo lỗi cho %s (%s): %s", e.Type, e.Status, e.Message)
+	}
+	return fmt.Sprintf("thiết bị báo lỗi cho %s: %s", e.Type, e.Status)
+}
+
+// unsupported cho biết thiết bị trả lời rõ là firmware không có lệnh.
+// Timeout và các lỗi khác (sai dữ liệu, chưa đăng nhập...) không phải dấu hiệu thiếu lệnh.
+func unsupported(err error) bool {
+	var replyErr *ReplyError
+	return errors.As(err, &replyErr) && replyErr.Status == statusUnsupported
+}
+
+// replyEnvelope là các trường chung của mọi phản hồi
+type replyEnvelope struct {
+	Type    string `json:"type"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// LoginResult là phản hồi của lệnh login
+type LoginResult struct {
+	Status string `json:"status"`
+	Role   string `json:"role"`
+}
+
+// RTCTime là thời gian đọc từ đồng hồ thiết bị
+type RTCTime struct {
+	Ts   int64  `json:"ts"`   // UTC giây
+	Time string `json:"time"` // RFC3339, giờ địa phương của máy tính
+}
+
+// SystemInfo là thông tin hệ thống thiết bị trả về
+type SystemInfo struct {
+	Raw   string   `json:"raw"`
+	Lines []string `json:"lines"`
+}
+
+// messageType đọc trường "type" của một dòng JSON, rỗng nếu không phải JSON
+func messageType(line string) string {
+	var envelope replyEnvelope
+	if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+		return ""
+	}
+	return envelope.Type
+}
+
+// decodeReply kiểm tra status và giải mã phản hồi vào reply (có thể nil)
+func decodeReply(line string, reply interface{}) error {
+	var envelope replyEnvelope
+	if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+		return fmt.Errorf("phản hồi không phải JSON hợp lệ: %w", err)
+	}
+
+	if envelope.Status != "" && envelope.Status != "success" {
+		return &ReplyError{Type: envelope.Type, Status: envelope.Status, Message: envelope.Message, line: line}
+	}
+
+	if reply == nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(line), reply); err != nil {
+		return fmt.Errorf("không thể giải mã phản hồi %s: %w", envelope.Type, err)
+	}
+	return nil
+}
+
+// parseSystemInfo tách chuỗi "a:1,b:2" thành từng dòng
+func parseSystemInfo(data string) SystemInfo {
+	info := SystemInfo{Raw: data}
+	for _, part := range strings.Split(data, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			info.Lines = append(info.Lines, part)
+		}
+	}
+	return info
+}
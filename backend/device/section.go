@@ -0,0 +1,206 @@
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"myproject/backend/config"
+)
+
+// Trạng thái của một section trong SectionReport
+const (
+	SectionUploaded       = "uploaded"        // thiết bị đã xác nhận
+	SectionFailed         = "failed"          // upload (hoặc đọc bản sao lưu) thất bại
+	SectionSkipped        = "skipped"         // không gửi vì section trước đã lỗi
+	SectionRolledBack     = "rolled_back"     // đã upload nhưng được khôi phục lại giá trị cũ
+	SectionRollbackFailed = "rollback_failed" // khôi phục thất bại, thiết bị có thể đang ở trạng thái lẫn
+)
+
+// SectionResult là kết quả upload một section
+type SectionResult struct {
+	Section config.SectionRef `json:"section"`
+	Status  string            `json:"status"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// SectionReport là kết quả của một lần upload nhiều section.
+// Lỗi của từng section nằm trong report thay vì error để frontend vẫn nhận được chi tiết.
+type SectionReport struct {
+	Results    []SectionResult `json:"results"`
+	Success    bool            `json:"success"`
+	RolledBack bool            `json:"rolledBack"`
+	Fallback   bool            `json:"fallback"` // thiết bị không hỗ trợ lệnh theo section, đã gửi cả cấu hình bằng upload_config
+	Error      string          `json:"error,omitempty"`
+}
+
+func sectionMessage(msgType string, ref config.SectionRef) map[string]interface{} {
+	message := map[string]interface{}{"type": msgType, "section": ref.Name}
+	if ref.Index != nil {
+		message["index"] = *ref.Index
+	}
+	return message
+}
+
+// downloadSection đọc JSON của một section (hoặc một phần tử) trên thiết bị
+func (c *Client) downloadSection(ref config.SectionRef, timeout time.Duration) (json.RawMessage, error) {
+	var reply struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := c.exchange(sectionMessage("download_section", ref), timeout, nil, &reply); err != nil {
+		return nil, err
+	}
+	if len(reply.Data) == 0 {
+		return nil, fmt.Errorf("thiết bị không trả về dữ liệu cho %s", ref)
+	}
+	return reply.Data, nil
+}
+
+func (c *Client) uploadSection(ref config.SectionRef, data json.RawMessage) error {
+	message := sectionMessage("upload_section", ref)
+	message["data"] = data
+	return c.request(message, nil)
+}
+
+// DownloadSection đọc một section (ví dụ ftp) hoặc một phần tử (modbus_reader[2]) trên thiết bị
+func (c *Client) DownloadSection(ref config.SectionRef) (interface{}, error) {
+	data, err := c.downloadSection(ref, c.timeoutFor("download_section"))
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("dữ liệu section %s không hợp lệ: %w", ref, err)
+	}
+	return value, nil
+}
+
+// UploadSections gửi lần lượt các phần refs của cfg xuống thiết bị, mỗi phần chờ xác nhận riêng.
+// Trước khi gửi, giá trị cũ của từng phần được đọc lại để khôi phục nếu một phần sau bị lỗi.
+// Nếu thiết bị trả lời download_section đầu tiên với status "unsupported", các phần refs được ghép
+// vào cấu hình đang có trên thiết bị và gửi một lần bằng upload_config (Fallback trong report).
+// Lỗi cấu hình trong phạm vi refs sẽ chặn upload (*config.ValidationError) trừ khi force là true.
+func (c *Client) UploadSections(cfg *config.Config, refs []config.SectionRef, force bool) (*SectionReport, error) {
+	return c.uploadSections(cfg, refs, force, nil)
+}
+
+// uploadSections là UploadSections; current là cấu hình đã đọc từ thiết bị (nil nếu chưa đọc),
+// chỉ dùng khi phải gửi cả cấu hình
+func (c *Client) uploadSections(cfg *config.Config, refs []config.SectionRef, force bool, current *config.Config) (*SectionReport, error) {
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("chưa chọn section nào để upload")
+	}
+	if issues := config.IssuesFor(config.Validate(cfg), refs); config.HasErrors(issues) && !force {
+		return nil, &config.ValidationError{Issues: issues}
+	}
+
+	payloads := make([]json.RawMessage, len(refs))
+	for i, ref := range refs {
+		data, err := config.Extract(cfg, ref)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = data
+	}
+
+	report := &SectionReport{Results: make([]SectionResult, len(refs))}
+	backups := make([]json.RawMessage, len(refs))
+	failed := -1
+	for i, ref := range refs {
+		report.Results[i] = SectionResult{Section: ref, Status: SectionSkipped}
+		if failed >= 0 {
+			continue
+		}
+
+		backup, err := c.downloadSection(ref, c.timeoutFor("download_section"))
+		if i == 0 && unsupported(err) {
+			return c.uploadWhole(refs, payloads, current)
+		}
+		if err == nil {
+			backups[i] = backup
+			err = c.uploadSection(ref, payloads[i])
+		}
+		if err != nil {
+			failed = i
+			report.Results[i].Status = SectionFailed
+			report.Results[i].Error = err.Error()
+			report.Error = fmt.Sprintf("upload %s thất bại: %v", ref, err)
+			continue
+		}
+		report.Results[i].Status = SectionUploaded
+	}
+
+	if failed < 0 {
+		report.Success = true
+		return report, nil
+	}
+
+	// Khôi phục theo thứ tự ngược các phần đã gửi. Phần bị lỗi cũng được ghi lại giá trị cũ
+	// nếu đã đọc được, vì timeout không có nghĩa là thiết bị chưa áp dụng.
+	report.RolledBack = true
+	for i := failed; i >= 0; i-- {
+		if backups[i] == nil {
+			continue
+		}
+		if err := c.uploadSection(refs[i], backups[i]); err != nil {
+			report.RolledBack = false
+			report.Results[i].Status = SectionRollbackFailed
+			report.Results[i].Error = err.Error()
+			continue
+		}
+		if i < failed {
+			report.Results[i].Status = SectionRolledBack
+		}
+	}
+	return report, nil
+}
+
+// uploadWhole ghép payloads của refs vào cấu hình trên thiết bị rồi gửi một lần bằng upload_config,
+// dùng cho firmware chưa có lệnh theo section. Các phần ngoài refs giữ nguyên như trên thiết bị.
+func (c *Client) uploadWhole(refs []config.SectionRef, payloads []json.RawMessage, current *config.Config) (*SectionReport, error) {
+	report := &SectionReport{Results: make([]SectionResult, len(refs)), Fallback: true}
+	fail := func(err error) (*SectionReport, error) {
+		for i, ref := range refs {
+			report.Results[i] = SectionResult{Section: ref, Status: SectionFailed, Error: err.Error()}
+		}
+		report.Error = fmt.Sprintf("upload_config thất bại: %v", err)
+		return report, nil
+	}
+
+	if current == nil {
+		var err error
+		if current, err = c.ReadConfig(); err != nil {
+			return fail(err)
+		}
+	}
+	for i, ref := range refs {
+		if err := config.Replace(current, ref, payloads[i]); err != nil {
+			return fail(err)
+		}
+	}
+	// Phạm vi refs đã được kiểm tra ở UploadSections, phần còn lại là cấu hình thiết bị đang chạy
+	if err := c.WriteConfig(current, true); err != nil {
+		return fail(err)
+	}
+	for i, ref := range refs {
+		report.Results[i] = SectionResult{Section: ref, Status: SectionUploaded}
+	}
+	report.Success = true
+	return report, nil
+}
+
+// PushConfigChanges đọc cấu hình trên thiết bị, so sánh với cfg và chỉ upload những phần đã sửa
+func (c *Client) PushConfigChanges(cfg *config.Config, force bool) (*SectionReport, error) {
+	current, err := c.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := config.Changed(current, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return &SectionReport{Results: []SectionResult{}, Success: true}, nil
+	}
+	return c.uploadSections(cfg, refs, force, current)
+}
@@ -0,0 +1,117 @@
+package device
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"myproject/backend/config"
+)
+
+// sequence trả về respond trả lời lần lượt theo danh sách của từng type, hết danh sách thì không trả lời
+func sequence(replies map[string][]string) func(string) []string {
+	var mu sync.Mutex
+	return func(line string) []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		msgType := messageType(line)
+		queue := replies[msgType]
+		if len(queue) == 0 {
+			return nil
+		}
+		replies[msgType] = queue[1:]
+		return []string{queue[0]}
+	}
+}
+
+func TestUploadSections(t *testing.T) {
+	data, err := os.ReadFile("../../workspace/default.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := []config.SectionRef{config.Section("common"), config.Section("timers")}
+
+	const (
+		download    = `{"type":"download_section","status":"success","data":{}}`
+		uploaded    = `{"type":"upload_section","status":"success"}`
+		uploadFail  = `{"type":"upload_section","status":"fail","message":"index không hợp lệ"}`
+		unsupported = `{"type":"download_section","status":"unsupported","message":"lệnh không được hỗ trợ"}`
+	)
+	tests := []struct {
+		name     string
+		replies  map[string][]string
+		sent     []string
+		statuses []string
+		success  bool
+		fallback bool
+	}{
+		{
+			name:     "lệnh theo section",
+			replies:  map[string][]string{"download_section": {download, download}, "upload_section": {uploaded, uploaded}},
+			sent:     []string{"download_section", "upload_section", "download_section", "upload_section"},
+			statuses: []string{SectionUploaded, SectionUploaded},
+			success:  true,
+		},
+		{
+			name: "firmware không có lệnh thì gửi cả cấu hình",
+			replies: map[string][]string{
+				"download_section": {unsupported},
+				"download_config":  {`{"type":"download_config","common":{"ai_loc":0}}`},
+				"upload_config":    {`{"type":"upload_config","status":"success"}`},
+			},
+			sent:     []string{"download_section", "download_config", "upload_config"},
+			statuses: []string{SectionUploaded, SectionUploaded},
+			success:  true,
+			fallback: true,
+		},
+		{
+			name:     "section đầu bị từ chối",
+			replies:  map[string][]string{"download_section": {`{"type":"download_section","status":"fail","message":"không có section"}`}},
+			sent:     []string{"download_section"},
+			statuses: []string{SectionFailed, SectionSkipped},
+		},
+		{
+			name:     "section đầu không phản hồi",
+			replies:  map[string][]string{},
+			sent:     []string{"download_section"},
+			statuses: []string{SectionFailed, SectionSkipped},
+		},
+		{
+			name:     "section sau lỗi thì khôi phục",
+			replies:  map[string][]string{"download_section": {download, download}, "upload_section": {uploaded, uploadFail, uploaded, uploaded}},
+			sent:     []string{"download_section", "upload_section", "download_section", "upload_section", "upload_section", "upload_section"},
+			statuses: []string{SectionRolledBack, SectionFailed},
+		},
+	}
+	for _, tt := range tests {
+		fake := &fakeTransport{endpoint: tt.name, respond: sequence(tt.replies)}
+		client := NewClient(fake)
+		for _, msgType := range []string{"download_section", "upload_section", "download_config", "upload_config"} {
+			client.SetCommandTimeout(msgType, 100)
+		}
+
+		report, err := client.UploadSections(cfg, refs, true)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var statuses []string
+		for _, result := range report.Results {
+			statuses = append(statuses, result.Status)
+		}
+		if !reflect.DeepEqual(statuses, tt.statuses) {
+			t.Errorf("%s: trạng thái %v, cần %v (%s)", tt.name, statuses, tt.statuses, report.Error)
+		}
+		if report.Success != tt.success || report.Fallback != tt.fallback {
+			t.Errorf("%s: success %v, fallback %v", tt.name, report.Success, report.Fallback)
+		}
+		if sent := sentTypes(fake); !reflect.DeepEqual(sent, tt.sent) {
+			t.Errorf("%s: đã gửi %v, cần %v", tt.name, sent, tt.sent)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package device
+
+import (
+	"context"
+	"fmt"
+
+	"myproject/backend/transport"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DeviceService là API lệnh thiết bị duy nhất cho frontend.
+// Frontend chọn kết nối một lần (UseSerial/UseSocket), sau đó gọi lệnh
+// mà không cần phân biệt COM hay Ethernet.
+type DeviceService struct {
+	*Client
+	ctx     context.Context
+	serial  transport.Transport
+	sockets *transport.SocketManager
+}
+
+// NewDeviceService khởi tạo DeviceService
+func NewDeviceService(serial transport.Transport, sockets *transport.SocketManager) *DeviceService {
+	return &DeviceService{
+		Client:  NewClient(nil),
+		serial:  serial,
+		sockets: sockets,
+	}
+}
+
+// SetContext lưu context Wails để gửi event tiến độ truyền
+func (d *DeviceService) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// UploadConfigChunked gửi cấu hình theo chunk, báo tiến độ qua event TransferProgressEvent
+func (d *DeviceService) UploadConfigChunked(data string, force bool, opts TransferOptions) error {
+	opts.Progress = EmitProgress(d.ctx)
+	return d.Client.UploadConfigChunked(data, force, opts)
+}
+
+// EmitProgress trả về hàm gửi tiến độ truyền lên frontend, nil nếu chưa có context
+func EmitProgress(ctx context.Context) func(TransferProgress) {
+	if ctx == nil {
+		return nil
+	}
+	return func(progress TransferProgress) {
+		runtime.EventsEmit(ctx, TransferProgressEvent, progress)
+	}
+}
+
+// UseSerial chọn cổng COM làm đường truyền cho các lệnh
+func (d *DeviceService) UseSerial() error {
+	if d.serial.Endpoint() == "" {
+		return fmt.Errorf("chưa kết nối cổng COM")
+	}
+	d.setTransport(d.serial)
+	return nil
+}
+
+// UseSocket chọn kết nối socket address:port làm đường truyền cho các lệnh
+func (d *DeviceService) UseSocket(address, port string) error {
+	if !d.sockets.IsActive(address, port) {
+		return fmt.Errorf("không có kết nối tới %s", transport.ConnectionKey(address, port))
+	}
+	d.setTransport(d.sockets.Transport(address, port))
+	return nil
+}
+
+// ClearConnection bỏ chọn đường truyền hiện tại
+func (d *DeviceService) ClearConnection() {
+	d.setTransport(nil)
+}
+
+// ActiveConnection trả về "<kind>://<endpoint>" của đường truyền đang chọn, rỗng nếu chưa chọn
+func (d *DeviceService) ActiveConnection() string {
+	t, err := d.currentTransport()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s", t.Kind(), t.Endpoint())
+}
@@ -0,0 +1,261 @@
+package device
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"myproject/backend/config"
+)
+
+// TransferProgressEvent là event gửi tiến độ truyền theo chunk lên frontend
+const TransferProgressEvent = "transfer:progress"
+
+// Tham số mặc định của truyền theo chunk
+const (
+	DefaultChunkSize     = 512
+	DefaultChunkRetries  = 3
+	DefaultChunkTimeout  = 3 * time.Second
+	maxChunkSize         = 16 * 1024
+	transferEndTimeout   = 15 * time.Second
+	statusChunkCRCError  = "crc_error"
+	statusTransferMissed = "missing"
+)
+
+// TransferOptions là tham số truyền theo chunk, giá trị 0 dùng mặc định
+type TransferOptions struct {
+	ChunkSize int `json:"chunkSize"` // số byte dữ liệu mỗi chunk (trước base64)
+	Retries   int `json:"retries"`   // số lần gửi lại mỗi chunk khi mất hoặc sai CRC
+	TimeoutMs int `json:"timeoutMs"` // thời gian chờ xác nhận mỗi chunk
+
+	// Progress được gọi sau mỗi chunk, có thể nil
+	Progress func(TransferProgress) `json:"-"`
+}
+
+func (o TransferOptions) withDefaults() TransferOptions {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultChunkSize
+	}
+	if o.ChunkSize > maxChunkSize {
+		o.ChunkSize = maxChunkSize
+	}
+	if o.Retries <= 0 {
+		o.Retries = DefaultChunkRetries
+	}
+	return o
+}
+
+func (o TransferOptions) timeout() time.Duration {
+	if o.TimeoutMs > 0 {
+		return time.Duration(o.TimeoutMs) * time.Millisecond
+	}
+	return DefaultChunkTimeout
+}
+
+// TransferProgress là tiến độ của một lần truyền
+type TransferProgress struct {
+	ID       string `json:"id"`
+	Target   string `json:"target"`  // lệnh thiết bị sẽ chạy khi nhận đủ (upload_config)
+	Sent     int    `json:"sent"`    // số chunk thiết bị đã xác nhận
+	Chunks   int    `json:"chunks"`  // tổng số chunk
+	Bytes    int    `json:"bytes"`   // số byte đã xác nhận
+	Total    int    `json:"total"`   // tổng số byte
+	Resumed  int    `json:"resumed"` // số chunk thiết bị đã có từ lần truyền trước
+	Retries  int    `json:"retries"` // tổng số lần gửi lại
+	Done     bool   `json:"done"`
+	Fallback bool   `json:"fallback"` // thiết bị không hỗ trợ transfer_begin, đã gửi một lần bằng lệnh target
+	Error    string `json:"error,omitempty"`
+}
+
+// transferID suy ra từ nội dung để lần gửi lại cùng dữ liệu tiếp tục từ chunk còn thiếu
+func transferID(payload []byte) string {
+	return fmt.Sprintf("%08x%x", crc32.ChecksumIEEE(payload), len(payload))
+}
+
+// transfer gửi payload theo chunk, mỗi chunk có CRC32 và chờ thiết bị xác nhận.
+// Khi nhận đủ, thiết bị kiểm tra CRC toàn bộ rồi chạy lệnh target với payload.
+// Firmware chưa hỗ trợ truyền theo chunk (trả status "unsupported" cho transfer_begin) nhận payload
+// trong một lệnh target như trước; mọi lỗi khác của transfer_begin được trả về.
+//
+//	{"type":"transfer_begin","id","target","size","chunks","chunk_size","crc"} -> {"have":[seq...]}
+//	{"type":"transfer_chunk","id","seq","data":base64,"crc"}                   -> {"id","seq"}
+//	{"type":"transfer_end","id"}                                               -> {"missing":[seq...]}
+func (c *Client) transfer(target string, payload []byte, opts TransferOptions) error {
+	opts = opts.withDefaults()
+	chunks := (len(payload) + opts.ChunkSize - 1) / opts.ChunkSize
+	progress := TransferProgress{ID: transferID(payload), Target: target, Chunks: chunks, Total: len(payload)}
+	report := func(err error) error {
+		if err != nil {
+			progress.Error = err.Error()
+		}
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+		return err
+	}
+
+	var begin struct {
+		Have []int `json:"have"`
+	}
+	err := c.request(map[string]interface{}{
+		"type":       "transfer_begin",
+		"id":         progress.ID,
+		"target":     target,
+		"size":       len(payload),
+		"chunks":     chunks,
+		"chunk_size": opts.ChunkSize,
+		"crc":        crc32.ChecksumIEEE(payload),
+	}, &begin)
+	if unsupported(err) {
+		progress.Fallback = true
+		if err := c.sendWhole(target, payload); err != nil {
+			return report(fmt.Errorf("thiết bị không nhận truyền theo chunk, gửi %s một lần cũng lỗi: %w", target, err))
+		}
+		progress.Sent, progress.Bytes, progress.Done = chunks, len(payload), true
+		return report(nil)
+	}
+	if err != nil {
+		return report(fmt.Errorf("thiết bị không nhận truyền theo chunk: %w", err))
+	}
+
+	have := make(map[int]bool, len(begin.Have))
+	for _, seq := range begin.Have {
+		if seq >= 0 && seq < chunks && !have[seq] {
+			have[seq] = true
+			progress.Resumed++
+			progress.Sent++
+			progress.Bytes += len(chunkOf(payload, seq, opts.ChunkSize))
+		}
+	}
+	report(nil)
+
+	pending := make([]int, 0, chunks)
+	for seq := 0; seq < chunks; seq++ {
+		if !have[seq] {
+			pending = append(pending, seq)
+		}
+	}
+
+	// Gửi các chunk còn thiếu, sau đó kết thúc; nếu thiết bị báo thiếu chunk thì gửi lại một lần
+	for attempt := 0; ; attempt++ {
+		for _, seq := range pending {
+			if err := c.sendChunk(progress.ID, seq, chunkOf(payload, seq, opts.ChunkSize), opts, &progress.Retries); err != nil {
+				return report(err)
+			}
+			if !have[seq] {
+				have[seq] = true
+				progress.Sent++
+				progress.Bytes += len(chunkOf(payload, seq, opts.ChunkSize))
+			}
+			report(nil)
+		}
+
+		var end struct {
+			Missing []int `json:"missing"`
+		}
+		err := c.exchange(map[string]interface{}{"type": "transfer_end", "id": progress.ID}, transferEndTimeout, matchField("id", progress.ID), &end)
+		var replyErr *ReplyError
+		if err == nil {
+			progress.Done = true
+			return report(nil)
+		}
+		if !errors.As(err, &replyErr) || replyErr.Status != statusTransferMissed || attempt > 0 {
+			return report(fmt.Errorf("thiết bị không hoàn tất truyền %s: %w", progress.ID, err))
+		}
+		if err := json.Unmarshal([]byte(replyErr.line), &end); err != nil {
+			return report(fmt.Errorf("phản hồi transfer_end không hợp lệ: %w", err))
+		}
+		pending = end.Missing
+		if len(pending) == 0 {
+			return report(fmt.Errorf("thiết bị không hoàn tất truyền %s: %w", progress.ID, err))
+		}
+	}
+}
+
+// sendWhole gửi payload (một object JSON) trong một lệnh target, thêm trường type
+func (c *Client) sendWhole(target string, payload []byte) error {
+	var message map[string]interface{}
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return fmt.Errorf("dữ liệu upload không phải JSON hợp lệ: %w", err)
+	}
+	message["type"] = target
+	return c.request(message, nil)
+}
+
+// sendChunk gửi một chunk, gửi lại khi hết thời gian chờ hoặc thiết bị báo sai CRC
+func (c *Client) sendChunk(id string, seq int, data []byte, opts TransferOptions, retries *int) error {
+	message := map[string]interface{}{
+		"type": "transfer_chunk",
+		"id":   id,
+		"seq":  seq,
+		"data": base64.StdEncoding.EncodeToString(data),
+		"crc":  crc32.ChecksumIEEE(data),
+	}
+	match := func(line string) bool {
+		var ack struct {
+			ID  string `json:"id"`
+			Seq *int   `json:"seq"`
+		}
+		return json.Unmarshal([]byte(line), &ack) == nil && ack.ID == id && ack.Seq != nil && *ack.Seq == seq
+	}
+
+	var err error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			*retries++
+		}
+		err = c.exchange(message, opts.timeout(), match, nil)
+		if err == nil {
+			return nil
+		}
+		var replyErr *ReplyError
+		if errors.As(err, &replyErr) && replyErr.Status != statusChunkCRCError {
+			break
+		}
+	}
+	return fmt.Errorf("chunk %d: %w", seq, err)
+}
+
+// matchField tạo điều kiện match phản hồi có trường key bằng value
+func matchField(key, value string) func(line string) bool {
+	return func(line string) bool {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			return false
+		}
+		v, _ := fields[key].(string)
+		return v == value
+	}
+}
+
+func chunkOf(payload []byte, seq, size int) []byte {
+	start := seq * size
+	end := start + size
+	if end > len(payload) {
+		end = len(payload)
+	}
+	return payload[start:end]
+}
+
+// UploadConfigChunked gửi cấu hình theo chunk có xác nhận và CRC, thay cho một dòng JSON lớn.
+// Gọi lại với cùng dữ liệu sau khi mất kết nối sẽ tiếp tục từ các chunk thiết bị chưa có.
+// Cấu hình có lỗi sẽ bị chặn (trả về *config.ValidationError) trừ khi force là true.
+func (c *Client) UploadConfigChunked(data string, force bool, opts TransferOptions) error {
+	cfg, err := config.Parse([]byte(data))
+	if err != nil {
+		return fmt.Errorf("dữ liệu upload không phải JSON hợp lệ: %w", err)
+	}
+	if err := config.Check(cfg); err != nil && !force {
+		return err
+	}
+
+	// Gửi dạng gọn, không thụt lề, để giảm số chunk
+	payload, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
+	}
+	return c.transfer("upload_config", payload, opts)
+}
@@ -0,0 +1,94 @@
+package device
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// byType trả về respond trả lời mỗi lệnh bằng dòng theo type của lệnh, không trả lời type không có trong replies
+func byType(replies map[string]string) func(string) []string {
+	return func(line string) []string {
+		if reply, ok := replies[messageType(line)]; ok {
+			return []string{reply}
+		}
+		return nil
+	}
+}
+
+// sentTypes liệt kê type của các lệnh đã gửi
+func sentTypes(f *fakeTransport) []string {
+	var types []string
+	for _, line := range f.lines() {
+		types = append(types, messageType(line))
+	}
+	return types
+}
+
+func TestTransferFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		begin    string // phản hồi transfer_begin, rỗng nếu thiết bị không trả lời
+		sent     []string
+		fallback bool
+		status   string // status của ReplyError mong đợi
+		timeout  bool
+	}{
+		{
+			name:     "firmware không có lệnh thì gửi một lần",
+			begin:    `{"type":"transfer_begin","status":"unsupported","message":"lệnh không được hỗ trợ"}`,
+			sent:     []string{"transfer_begin", "upload_config"},
+			fallback: true,
+		},
+		{
+			name:   "thiết bị từ chối thì trả lỗi",
+			begin:  `{"type":"transfer_begin","status":"fail","message":"bộ nhớ đầy"}`,
+			sent:   []string{"transfer_begin"},
+			status: "fail",
+		},
+		{
+			name:   "chưa đăng nhập thì trả lỗi",
+			begin:  `{"type":"transfer_begin","status":"unauthorized"}`,
+			sent:   []string{"transfer_begin"},
+			status: "unauthorized",
+		},
+		{
+			name:    "không phản hồi thì trả lỗi",
+			sent:    []string{"transfer_begin"},
+			timeout: true,
+		},
+	}
+	for _, tt := range tests {
+		replies := map[string]string{"upload_config": `{"type":"upload_config","status":"success"}`}
+		if tt.begin != "" {
+			replies["transfer_begin"] = tt.begin
+		}
+		fake := &fakeTransport{endpoint: tt.name, respond: byType(replies)}
+		client := NewClient(fake)
+		client.SetCommandTimeout("transfer_begin", 100)
+
+		var progress TransferProgress
+		err := client.transfer("upload_config", []byte(`{"common":{"ai_loc":0}}`), TransferOptions{
+			Progress: func(p TransferProgress) { progress = p },
+		})
+		var replyErr *ReplyError
+		switch {
+		case tt.timeout:
+			if !errors.Is(err, ErrTimeout) {
+				t.Errorf("%s: lỗi %v, cần ErrTimeout", tt.name, err)
+			}
+		case tt.status != "":
+			if !errors.As(err, &replyErr) || replyErr.Status != tt.status {
+				t.Errorf("%s: lỗi %v, cần ReplyError %s", tt.name, err, tt.status)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if progress.Fallback != tt.fallback || progress.Done != (err == nil) {
+			t.Errorf("%s: tiến độ %+v", tt.name, progress)
+		}
+		if sent := sentTypes(fake); !reflect.DeepEqual(sent, tt.sent) {
+			t.Errorf("%s: đã gửi %v, cần %v", tt.name, sent, tt.sent)
+		}
+	}
+}
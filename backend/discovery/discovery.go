@@ -0,0 +1,233 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DefaultPort là cổng cấu hình mặc định của datalogger (common.conf_port)
+const DefaultPort = 19981
+
+// DeviceEvent là tên sự kiện Wails phát mỗi khi tìm thấy một thiết bị
+const DeviceEvent = "discovery:device"
+
+// Các cách tìm thấy thiết bị
+const (
+	MethodBroadcast = "broadcast"
+	MethodSweep     = "sweep"
+)
+
+// Options là tham số cho một lần dò tìm
+type Options struct {
+	Port      int  `json:"port"`      // 0 để dùng DefaultPort
+	TimeoutMs int  `json:"timeoutMs"` // tổng thời gian dò, 0 để dùng mặc định
+	Broadcast bool `json:"broadcast"` // gửi gói UDP broadcast
+	Sweep     bool `json:"sweep"`     // quét TCP cổng cấu hình trên các mạng con
+}
+
+// DefaultOptions dùng cả broadcast và quét TCP trong 3 giây
+func DefaultOptions() Options {
+	return Options{Port: DefaultPort, TimeoutMs: 3000, Broadcast: true, Sweep: true}
+}
+
+// Device là một datalogger tìm thấy trên mạng LAN
+type Device struct {
+	Address string   `json:"address"`
+	Port    int      `json:"port"`
+	Serial  string   `json:"serial"`
+	Mac     string   `json:"mac"`
+	Methods []string `json:"methods"`
+	Info    string   `json:"info,omitempty"` // chuỗi read_system_info gốc nếu có
+}
+
+// Key trả về address:port dùng để gộp kết quả
+func (d Device) Key() string {
+	return fmt.Sprintf("%s:%d", d.Address, d.Port)
+}
+
+// DiscoveryService tìm datalogger trong mạng LAN để điền sẵn vào hộp thoại kết nối
+type DiscoveryService struct {
+	ctx     context.Context
+	mu      sync.Mutex
+	running bool
+	last    []Device
+}
+
+// NewDiscoveryService khởi tạo DiscoveryService
+func NewDiscoveryService() *DiscoveryService {
+	return &DiscoveryService{}
+}
+
+// SetContext lưu context của Wails để phát sự kiện
+func (s *DiscoveryService) SetContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+}
+
+// GetDefaultOptions trả về tham số dò tìm mặc định
+func (s *DiscoveryService) GetDefaultOptions() Options {
+	return DefaultOptions()
+}
+
+// Discover dò tìm datalogger trên các mạng con của máy tính.
+// Mỗi thiết bị tìm thấy được phát qua DeviceEvent ngay khi có,
+// kết quả cuối cùng được gộp theo address:port.
+func (s *DiscoveryService) Discover(options Options) ([]Device, error) {
+	if options.Port == 0 {
+		options.Port = DefaultPort
+	}
+	if options.Port < 0 || options.Port > 65535 {
+		return nil, fmt.Errorf("cổng không hợp lệ: %d", options.Port)
+	}
+	if options.TimeoutMs <= 0 {
+		options.TimeoutMs = DefaultOptions().TimeoutMs
+	}
+	if !options.Broadcast && !options.Sweep {
+		return nil, fmt.Errorf("cần bật ít nhất một cách dò: broadcast hoặc sweep")
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("đang dò tìm thiết bị, vui lòng chờ")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	networks, err := localNetworks()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(options.TimeoutMs) * time.Millisecond
+	found := make(chan Device, 16)
+	var wg sync.WaitGroup
+
+	if options.Broadcast {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			broadcast(networks, options.Port, timeout, found)
+		}()
+	}
+	if options.Sweep {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sweep(networks, options.Port, timeout, found)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	devices := make(map[string]*Device)
+	for device := range found {
+		existing, ok := devices[device.Key()]
+		if !ok {
+			copied := device
+			devices[device.Key()] = &copied
+			s.emit(copied)
+			continue
+		}
+		existing.merge(device)
+		s.emit(*existing)
+	}
+
+	result := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, *device)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key() < result[j].Key() })
+
+	s.mu.Lock()
+	s.last = result
+	s.mu.Unlock()
+	return result, nil
+}
+
+// LastResult trả về kết quả của lần dò gần nhất
+func (s *DiscoveryService) LastResult() []Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Device(nil), s.last...)
+}
+
+// emit phát DeviceEvent cho frontend
+func (s *DiscoveryService) emit(device Device) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if ctx != nil {
+		runtime.EventsEmit(ctx, DeviceEvent, device)
+	}
+}
+
+// merge bổ sung thông tin từ một kết quả khác của cùng thiết bị
+func (d *Device) merge(other Device) {
+	if d.Serial == "" {
+		d.Serial = other.Serial
+	}
+	if d.Mac == "" {
+		d.Mac = other.Mac
+	}
+	if d.Info == "" {
+		d.Info = other.Info
+	}
+	for _, method := range other.Methods {
+		if !containsString(d.Methods, method) {
+			d.Methods = append(d.Methods, method)
+		}
+	}
+}
+
+// applyInfo lấy serial và MAC từ chuỗi "key:value,key:value" của read_system_info
+func (d *Device) applyInfo(info string) {
+	d.Info = info
+	for _, part := range strings.Split(info, ",") {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		d.applyField(key, strings.TrimSpace(value))
+	}
+}
+
+// applyField gán serial hoặc MAC theo tên trường
+func (d *Device) applyField(key, value string) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	switch {
+	case value == "":
+	case strings.Contains(key, "mac"):
+		if d.Mac == "" {
+			d.Mac = value
+		}
+	case strings.Contains(key, "serial") || key == "sn":
+		if d.Serial == "" {
+			d.Serial = value
+		}
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
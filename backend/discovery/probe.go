@@ -0,0 +1,241 @@
+package discovery
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSweepHosts giới hạn số địa chỉ quét trên mỗi mạng con (tương đương /24)
+const maxSweepHosts = 254
+
+// sweepWorkers là số kết nối TCP thử đồng thời
+const sweepWorkers = 64
+
+// probeLine là lệnh gửi tới thiết bị để lấy serial và MAC
+const probeLine = `{"type":"read_system_info"}` + "\n"
+
+// broadcastLine là gói UDP broadcast hỏi các thiết bị trong mạng
+const broadcastLine = `{"type":"discover"}` + "\n"
+
+// localNetwork là một mạng con IPv4 của máy tính
+type localNetwork struct {
+	ip        net.IP
+	network   *net.IPNet
+	broadcast net.IP
+}
+
+// localNetworks liệt kê các mạng con IPv4 đang hoạt động (bỏ qua loopback)
+func localNetworks() ([]localNetwork, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("không thể đọc danh sách card mạng: %w", err)
+	}
+
+	var networks []localNetwork
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP.To4()
+			if ip == nil {
+				continue
+			}
+			mask := net.IP(ipNet.Mask).To4()
+			if mask == nil {
+				continue
+			}
+			broadcast := make(net.IP, 4)
+			for i := range ip {
+				broadcast[i] = ip[i] | ^mask[i]
+			}
+			networks = append(networks, localNetwork{
+				ip:        ip,
+				network:   &net.IPNet{IP: ip.Mask(ipNet.Mask), Mask: ipNet.Mask},
+				broadcast: broadcast,
+			})
+		}
+	}
+
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("không tìm thấy mạng IPv4 nào đang hoạt động")
+	}
+	return networks, nil
+}
+
+// hosts trả về các địa chỉ cần quét trong mạng con.
+// Mạng lớn hơn /24 chỉ quét /24 chứa địa chỉ của máy tính.
+func (n localNetwork) hosts() []net.IP {
+	ones, bits := n.network.Mask.Size()
+	base := n.network.IP
+	if bits-ones > 8 {
+		base = n.ip.Mask(net.CIDRMask(24, 32))
+		ones = 24
+	}
+
+	start := binary.BigEndian.Uint32(base.To4())
+	count := uint32(1) << uint(32-ones)
+
+	var hosts []net.IP
+	for offset := uint32(1); offset+1 < count && len(hosts) < maxSweepHosts; offset++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, start+offset)
+		if ip.Equal(n.ip) {
+			continue
+		}
+		hosts = append(hosts, ip)
+	}
+	return hosts
+}
+
+// broadcast gửi gói discover tới địa chỉ broadcast của từng mạng con và
+// nhận phản hồi cho tới khi hết thời gian
+func broadcast(networks []localNetwork, port int, timeout time.Duration, found chan<- Device) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	targets := []net.IP{net.IPv4bcast}
+	for _, network := range networks {
+		targets = append(targets, network.broadcast)
+	}
+	for _, target := range targets {
+		conn.WriteToUDP([]byte(broadcastLine), &net.UDPAddr{IP: target, Port: port})
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buffer := make([]byte, 4096)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		device, ok := parseBroadcastReply(buffer[:n], from, port)
+		if ok {
+			found <- device
+		}
+	}
+}
+
+// parseBroadcastReply đọc phản hồi discover, data có thể là object
+// {"serial":...,"mac":...,"conf_port":...} hoặc chuỗi "key:value,..."
+func parseBroadcastReply(payload []byte, from *net.UDPAddr, port int) (Device, bool) {
+	var reply struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &reply); err != nil || reply.Type != "discover" {
+		return Device{}, false
+	}
+
+	device := Device{Address: from.IP.String(), Port: port, Methods: []string{MethodBroadcast}}
+
+	var fields map[string]interface{}
+	var info string
+	switch {
+	case json.Unmarshal(reply.Data, &fields) == nil:
+		for key, value := range fields {
+			if strings.EqualFold(key, "conf_port") {
+				if p, ok := value.(float64); ok && p > 0 {
+					device.Port = int(p)
+				}
+				continue
+			}
+			if s, ok := value.(string); ok {
+				device.applyField(key, s)
+			}
+		}
+	case json.Unmarshal(reply.Data, &info) == nil:
+		device.applyInfo(info)
+	}
+	return device, true
+}
+
+// sweep thử kết nối TCP tới cổng cấu hình của từng địa chỉ trong các mạng con
+func sweep(networks []localNetwork, port int, timeout time.Duration, found chan<- Device) {
+	deadline := time.Now().Add(timeout)
+	dialTimeout := timeout / 4
+	if dialTimeout > 500*time.Millisecond {
+		dialTimeout = 500 * time.Millisecond
+	}
+
+	addresses := make(chan net.IP)
+	var wg sync.WaitGroup
+	for i := 0; i < sweepWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ip := range addresses {
+				if device, ok := probe(ip, port, dialTimeout, deadline); ok {
+					found <- device
+				}
+			}
+		}()
+	}
+
+	for _, network := range networks {
+		for _, ip := range network.hosts() {
+			if time.Now().After(deadline) {
+				break
+			}
+			addresses <- ip
+		}
+	}
+	close(addresses)
+	wg.Wait()
+}
+
+// probe kết nối tới ip:port, gửi read_system_info và đọc serial/MAC.
+// Thiết bị mở cổng nhưng không trả lời vẫn được báo về (chưa có serial/MAC).
+func probe(ip net.IP, port int, dialTimeout time.Duration, deadline time.Time) (Device, bool) {
+	address := net.JoinHostPort(ip.String(), fmt.Sprint(port))
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return Device{}, false
+	}
+	defer conn.Close()
+
+	device := Device{Address: ip.String(), Port: port, Methods: []string{MethodSweep}}
+
+	replyDeadline := time.Now().Add(time.Second)
+	if replyDeadline.After(deadline) {
+		replyDeadline = deadline
+	}
+	conn.SetDeadline(replyDeadline)
+	if _, err := conn.Write([]byte(probeLine)); err != nil {
+		return device, true
+	}
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return device, true
+		}
+		var reply struct {
+			Type string `json:"type"`
+			Data string `json:"data"`
+		}
+		if json.Unmarshal([]byte(line), &reply) != nil || reply.Type != "read_system_info" {
+			continue
+		}
+		device.applyInfo(reply.Data)
+		return device, true
+	}
+}
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"myproject/backend/transport"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Tên sự kiện gửi lên frontend
+const (
+	// TopicPrefix + type của message, ví dụ "device:read_analog", chỉ phát cho loại đã đăng ký bằng Listen
+	TopicPrefix = "device:"
+	// TopicMessage nhận message JSON từ thiết bị của các loại chưa đăng ký Listen
+	TopicMessage = "device:message"
+	// TopicRaw nhận các dòng không phải JSON
+	TopicRaw = "device:raw"
+)
+
+// knownTypes là các loại message thiết bị gửi về, dùng cho Topics()
+var knownTypes = []string{
+	"read_analog", "read_tag_view", "read_memory_view",
+	"read_system_info", "read_sim_info", "read_sdcard_info",
+	"get_rtc", "set_rtc", "get_measure_mode", "set_measure_mode", "get_gps",
+	"download_config", "upload_config", "network", "network_setting",
+	"login", "logout", "change_password", "calib_4ma", "calib_16ma",
+	"set_digital_output", "ping", "write_serial_number", "write_mac",
+	"reset_configuration", "reboot",
+}
+
+// Source là nguồn phát frame (auth.Transport, SocketManager, ...)
+type Source interface {
+	Subscribe(handler func(transport.Frame)) func()
+}
+
+// Message là nội dung một sự kiện gửi lên frontend
+type Message struct {
+	Type     string                 `json:"type"`
+	Kind     string                 `json:"kind"`
+	Endpoint string                 `json:"endpoint"`
+	Time     int64                  `json:"time"` // Unix milli giây
+	Payload  map[string]interface{} `json:"payload,omitempty"`
+	Raw      string                 `json:"raw"`
+}
+
+// Topic trả về tên sự kiện của một loại message
+func Topic(msgType string) string {
+	return TopicPrefix + msgType
+}
+
+// EventService đẩy dữ liệu thiết bị lên frontend qua Wails events thay cho polling
+type EventService struct {
+	ctx     context.Context
+	sources []Source
+	mu      sync.RWMutex
+	muted   map[string]bool
+	listens map[string]int // số listener riêng của mỗi loại message
+	cancels []func()
+}
+
+// NewEventService khởi tạo EventService với các nguồn frame
+func NewEventService(sources ...Source) *EventService {
+	return &EventService{
+		sources: sources,
+		muted:   make(map[string]bool),
+		listens: make(map[string]int),
+	}
+}
+
+// Startup bắt đầu chuyển tiếp frame, gọi từ OnStartup của Wails
+func (e *EventService) Startup(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.ctx = ctx
+	for _, source := range e.sources {
+		e.cancels = append(e.cancels, source.Subscribe(e.forward))
+	}
+}
+
+// Shutdown dừng chuyển tiếp frame
+func (e *EventService) Shutdown(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, cancel := range e.cancels {
+		cancel()
+	}
+	e.cancels = nil
+}
+
+// Topics liệt kê các sự kiện frontend có thể lắng nghe; sự kiện theo loại message cần gọi Listen trước
+func (e *EventService) Topics() []string {
+	topics := []string{TopicMessage, TopicRaw}
+	for _, msgType := range knownTypes {
+		topics = append(topics, Topic(msgType))
+	}
+	return topics
+}
+
+// Mute tạm ngừng phát sự kiện cho một loại message (ví dụ luồng read_memory_view khi ẩn màn hình)
+func (e *EventService) Mute(msgType string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.muted[msgType] = true
+}
+
+// Unmute phát lại sự kiện cho một loại message
+func (e *EventService) Unmute(msgType string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.muted, msgType)
+}
+
+// Listen báo frontend có listener riêng cho một loại message: từ đó message loại này chỉ phát
+// trên Topic(msgType) thay vì TopicMessage, để mỗi frame chỉ được phát một lần
+func (e *EventService) Listen(msgType string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listens[msgType]++
+}
+
+// Unlisten hủy một lần Listen; hết listener riêng thì message loại này phát lại trên TopicMessage
+func (e *EventService) Unlisten(msgType string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.listens[msgType] <= 1 {
+		delete(e.listens, msgType)
+		return
+	}
+	e.listens[msgType]--
+}
+
+// MutedTypes liệt kê các loại message đang bị tắt
+func (e *EventService) MutedTypes() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	types := make([]string, 0, len(e.muted))
+	for msgType := range e.muted {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// forward phân tích frame và phát sự kiện tương ứng
+func (e *EventService) forward(frame transport.Frame) {
+	e.mu.RLock()
+	ctx := e.ctx
+	e.mu.RUnlock()
+	if ctx == nil {
+		return
+	}
+
+	message := Message{
+		Kind:     frame.Kind,
+		Endpoint: frame.Endpoint,
+		Time:     frame.Time.UnixMilli(),
+		Raw:      frame.Data,
+	}
+
+	if err := json.Unmarshal([]byte(frame.Data), &message.Payload); err != nil {
+		runtime.EventsEmit(ctx, TopicRaw, message)
+		return
+	}
+	message.Type, _ = message.Payload["type"].(string)
+
+	e.mu.RLock()
+	muted := e.muted[message.Type]
+	specific := message.Type != "" && e.listens[message.Type] > 0
+	e.mu.RUnlock()
+	switch {
+	case muted:
+	case specific:
+		runtime.EventsEmit(ctx, Topic(message.Type), message)
+	default:
+		runtime.EventsEmit(ctx, TopicMessage, message)
+	}
+}
@@ -0,0 +1,221 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// DataType là loại đối tượng Modbus của modbus_reader (d_t), trùng với mã hàm đọc
+type DataType int
+
+const (
+	Coils            DataType = 1
+	DiscreteInputs   DataType = 2
+	HoldingRegisters DataType = 3
+	InputRegisters   DataType = 4
+)
+
+func (t DataType) String() string {
+	switch t {
+	case Coils:
+		return "coils"
+	case DiscreteInputs:
+		return "discrete inputs"
+	case HoldingRegisters:
+		return "holding registers"
+	case InputRegisters:
+		return "input registers"
+	}
+	return fmt.Sprintf("d_t %d", int(t))
+}
+
+// Bits cho biết đối tượng là bit (coils, discrete inputs) thay vì thanh ghi 16 bit
+func (t DataType) Bits() bool {
+	return t == Coils || t == DiscreteInputs
+}
+
+// ByteOrder là thứ tự byte của giá trị nhiều byte (d_o). A là byte cao nhất của giá trị,
+// thứ tự được đọc theo byte nhận được trên đường truyền.
+type ByteOrder int
+
+const (
+	OrderABCD ByteOrder = 0 // big-endian
+	OrderCDAB ByteOrder = 1 // đảo thứ tự word
+	OrderBADC ByteOrder = 2 // đảo byte trong mỗi word
+	OrderDCBA ByteOrder = 3 // little-endian
+)
+
+func (o ByteOrder) String() string {
+	switch o {
+	case OrderABCD:
+		return "AB CD"
+	case OrderCDAB:
+		return "CD AB"
+	case OrderBADC:
+		return "BA DC"
+	case OrderDCBA:
+		return "DC BA"
+	}
+	return fmt.Sprintf("d_o %d", int(o))
+}
+
+func (o ByteOrder) swapWords() bool { return o == OrderCDAB || o == OrderDCBA }
+func (o ByteOrder) swapBytes() bool { return o == OrderBADC || o == OrderDCBA }
+
+// Format là định dạng giá trị (d_f). Số nguyên đều có dấu; 8 bit là byte thấp của thanh ghi.
+type Format int
+
+const (
+	Int8    Format = 0
+	Int16   Format = 1
+	Int32   Format = 2
+	Float32 Format = 3
+	Int64   Format = 4
+	Float64 Format = 5
+)
+
+func (f Format) String() string {
+	switch f {
+	case Int8:
+		return "int8"
+	case Int16:
+		return "int16"
+	case Int32:
+		return "int32"
+	case Float32:
+		return "float32"
+	case Int64:
+		return "int64"
+	case Float64:
+		return "float64"
+	}
+	return fmt.Sprintf("d_f %d", int(f))
+}
+
+// Words là số thanh ghi 16 bit của một giá trị, 0 nếu định dạng không hợp lệ
+func (f Format) Words() int {
+	switch f {
+	case Int8, Int16:
+		return 1
+	case Int32, Float32:
+		return 2
+	case Int64, Float64:
+		return 4
+	}
+	return 0
+}
+
+// canonical đổi các word của một giá trị theo thứ tự order về big-endian (AB CD) và ngược lại;
+// đảo word và đảo byte đều tự nghịch đảo nên dùng được cho cả hai chiều
+func canonical(words []uint16, order ByteOrder) []byte {
+	n := len(words)
+	b := make([]byte, 2*n)
+	for i, w := range words {
+		if order.swapWords() {
+			w = words[n-1-i]
+		}
+		if order.swapBytes() {
+			w = w<<8 | w>>8
+		}
+		binary.BigEndian.PutUint16(b[2*i:], w)
+	}
+	return b
+}
+
+func checkCodes(format Format, order ByteOrder) error {
+	if format.Words() == 0 {
+		return fmt.Errorf("định dạng d_f %d không hợp lệ (0..5)", int(format))
+	}
+	if order < OrderABCD || order > OrderDCBA {
+		return fmt.Errorf("thứ tự byte d_o %d không hợp lệ (0..3)", int(order))
+	}
+	return nil
+}
+
+// Decode giải mã các thanh ghi thành giá trị theo định dạng và thứ tự byte,
+// số thanh ghi phải là bội của format.Words()
+func Decode(registers []uint16, format Format, order ByteOrder) ([]float64, error) {
+	if err := checkCodes(format, order); err != nil {
+		return nil, err
+	}
+	n := format.Words()
+	if len(registers)%n != 0 {
+		return nil, fmt.Errorf("%d thanh ghi không chia hết cho %d thanh ghi mỗi giá trị %s", len(registers), n, format)
+	}
+	values := make([]float64, 0, len(registers)/n)
+	for i := 0; i < len(registers); i += n {
+		b := canonical(registers[i:i+n], order)
+		var v float64
+		switch format {
+		case Int8:
+			v = float64(int8(b[1]))
+		case Int16:
+			v = float64(int16(binary.BigEndian.Uint16(b)))
+		case Int32:
+			v = float64(int32(binary.BigEndian.Uint32(b)))
+		case Float32:
+			v = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case Int64:
+			v = float64(int64(binary.BigEndian.Uint64(b)))
+		case Float64:
+			v = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Encode mã hóa một giá trị thành thanh ghi, ngược với Decode; dùng cho slave giả lập
+func Encode(value float64, format Format, order ByteOrder) ([]uint16, error) {
+	if err := checkCodes(format, order); err != nil {
+		return nil, err
+	}
+	integer := func(min, max float64) error {
+		if value != math.Trunc(value) || value < min || value > max {
+			return fmt.Errorf("giá trị %g không phải số %s", value, format)
+		}
+		return nil
+	}
+	b := make([]byte, 2*format.Words())
+	switch format {
+	case Int8:
+		if err := integer(math.MinInt8, math.MaxInt8); err != nil {
+			return nil, err
+		}
+		b[1] = byte(int8(value))
+	case Int16:
+		if err := integer(math.MinInt16, math.MaxInt16); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint16(b, uint16(int16(value)))
+	case Int32:
+		if err := integer(math.MinInt32, math.MaxInt32); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(b, uint32(int32(value)))
+	case Float32:
+		if math.Abs(value) > math.MaxFloat32 {
+			return nil, fmt.Errorf("giá trị %g vượt quá float32", value)
+		}
+		binary.BigEndian.PutUint32(b, math.Float32bits(float32(value)))
+	case Int64:
+		// 2^63 làm tròn từ MaxInt64 đã vượt quá int64 nên lấy số float64 liền trước
+		if err := integer(math.MinInt64, math.Nextafter(math.MaxInt64, 0)); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(b, uint64(int64(value)))
+	case Float64:
+		binary.BigEndian.PutUint64(b, math.Float64bits(value))
+	}
+
+	words := make([]uint16, format.Words())
+	for i := range words {
+		words[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+	back := canonical(words, order)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint16(back[2*i:])
+	}
+	return words, nil
+}
@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var orders = []ByteOrder{OrderABCD, OrderCDAB, OrderBADC, OrderDCBA}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		format Format
+		values []float64
+	}{
+		{Int8, []float64{0, 1, -1, math.MinInt8, math.MaxInt8}},
+		{Int16, []float64{0, 1, -1, 1234, math.MinInt16, math.MaxInt16}},
+		{Int32, []float64{0, -1, 123456789, math.MinInt32, math.MaxInt32}},
+		{Float32, []float64{0, 1, -1.5, 23.5, float64(float32(math.Pi)), math.MaxFloat32, -math.SmallestNonzeroFloat32}},
+		{Int64, []float64{0, -1, 1 << 53, math.MinInt64, math.Nextafter(math.MaxInt64, 0)}},
+		{Float64, []float64{0, -273.15, math.Pi, math.SmallestNonzeroFloat64, math.MaxFloat64, math.Inf(1)}},
+	}
+	for _, tt := range tests {
+		for _, order := range orders {
+			for _, value := range tt.values {
+				words, err := Encode(value, tt.format, order)
+				if err != nil {
+					t.Fatalf("Encode(%g, %s, %s): %v", value, tt.format, order, err)
+				}
+				if len(words) != tt.format.Words() {
+					t.Fatalf("Encode(%g, %s, %s) trả %d thanh ghi, cần %d", value, tt.format, order, len(words), tt.format.Words())
+				}
+				got, err := Decode(words, tt.format, order)
+				if err != nil {
+					t.Fatalf("Decode(%04X, %s, %s): %v", words, tt.format, order, err)
+				}
+				if len(got) != 1 || got[0] != value {
+					t.Errorf("Decode(Encode(%g)) theo %s %s = %v", value, tt.format, order, got)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeByteOrder(t *testing.T) {
+	// 1.0 float32 là 3F 80 00 00, 0x01020304 int32 là 01 02 03 04
+	tests := []struct {
+		format    Format
+		order     ByteOrder
+		registers []uint16
+		want      float64
+	}{
+		{Float32, OrderABCD, []uint16{0x3F80, 0x0000}, 1},
+		{Float32, OrderCDAB, []uint16{0x0000, 0x3F80}, 1},
+		{Float32, OrderBADC, []uint16{0x803F, 0x0000}, 1},
+		{Float32, OrderDCBA, []uint16{0x0000, 0x803F}, 1},
+		{Int32, OrderABCD, []uint16{0x0102, 0x0304}, 0x01020304},
+		{Int32, OrderCDAB, []uint16{0x0304, 0x0102}, 0x01020304},
+		{Int32, OrderBADC, []uint16{0x0201, 0x0403}, 0x01020304},
+		{Int32, OrderDCBA, []uint16{0x0403, 0x0201}, 0x01020304},
+		{Int16, OrderABCD, []uint16{0xFFFE}, -2},
+		{Int16, OrderBADC, []uint16{0xFEFF}, -2},
+		{Int8, OrderABCD, []uint16{0xAB80}, -128}, // chỉ lấy byte thấp
+		{Int8, OrderBADC, []uint16{0x7FAB}, 127},
+		{Int64, OrderCDAB, []uint16{0x0004, 0x0003, 0x0002, 0x0001}, 0x0001000200030004},
+		{Float64, OrderABCD, []uint16{0x3FF0, 0, 0, 0}, 1},
+		{Float64, OrderDCBA, []uint16{0, 0, 0, 0xF03F}, 1},
+	}
+	for _, tt := range tests {
+		got, err := Decode(tt.registers, tt.format, tt.order)
+		if err != nil {
+			t.Fatalf("Decode(%04X, %s, %s): %v", tt.registers, tt.format, tt.order, err)
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Decode(%04X, %s, %s) = %v, cần %g", tt.registers, tt.format, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeMultipleValues(t *testing.T) {
+	got, err := Decode([]uint16{0x0000, 0x3F80, 0x0000, 0xC000}, Float32, OrderCDAB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{1, -2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %v, cần %v", got, want)
+	}
+}
+
+func TestEncodeDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"int8 vượt khoảng", encodeErr(128, Int8, OrderABCD)},
+		{"int16 có phần lẻ", encodeErr(1.5, Int16, OrderABCD)},
+		{"int32 vượt khoảng", encodeErr(math.MaxInt32+1, Int32, OrderABCD)},
+		{"int64 bằng 2^63", encodeErr(math.MaxInt64, Int64, OrderABCD)},
+		{"float32 vượt khoảng", encodeErr(math.MaxFloat64, Float32, OrderABCD)},
+		{"d_f không hợp lệ", encodeErr(1, Format(6), OrderABCD)},
+		{"d_o không hợp lệ", encodeErr(1, Int16, ByteOrder(4))},
+		{"số thanh ghi lẻ", decodeErr([]uint16{1, 2, 3}, Int32, OrderABCD)},
+		{"decode d_f không hợp lệ", decodeErr([]uint16{1}, Format(-1), OrderABCD)},
+		{"decode d_o không hợp lệ", decodeErr([]uint16{1}, Int16, ByteOrder(-1))},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: không báo lỗi", tt.name)
+		}
+	}
+}
+
+func encodeErr(value float64, format Format, order ByteOrder) error {
+	_, err := Encode(value, format, order)
+	return err
+}
+
+func decodeErr(registers []uint16, format Format, order ByteOrder) error {
+	_, err := Decode(registers, format, order)
+	return err
+}
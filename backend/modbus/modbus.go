@@ -0,0 +1,191 @@
+// Package modbus là Modbus master chạy trên máy tính để kiểm tra các mục modbus_reader
+// trước khi giao cho datalogger: đọc thanh ghi qua TCP hoặc RTU và giải mã như logger.
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Giới hạn số đối tượng của một lần đọc theo chuẩn Modbus
+const (
+	MaxBits      = 2000
+	MaxRegisters = 125
+)
+
+// ErrTimeout là lỗi không nhận được phản hồi trong thời gian chờ
+var ErrTimeout = errors.New("hết thời gian chờ phản hồi Modbus")
+
+// Exception là phản hồi lỗi của slave (mã hàm có bit 0x80)
+type Exception struct {
+	Function byte `json:"function"`
+	Code     byte `json:"code"`
+}
+
+func (e *Exception) Error() string {
+	return fmt.Sprintf("slave trả exception %d (%s) cho hàm %d", e.Code, exceptionName(e.Code), e.Function)
+}
+
+func exceptionName(code byte) string {
+	switch code {
+	case 1:
+		return "hàm không được hỗ trợ"
+	case 2:
+		return "địa chỉ thanh ghi không hợp lệ"
+	case 3:
+		return "giá trị/số lượng không hợp lệ"
+	case 4:
+		return "lỗi thiết bị slave"
+	case 5:
+		return "slave đã nhận, đang xử lý"
+	case 6:
+		return "slave bận"
+	case 8:
+		return "lỗi parity bộ nhớ"
+	case 10:
+		return "gateway không có đường tới thiết bị"
+	case 11:
+		return "thiết bị sau gateway không phản hồi"
+	}
+	return "không rõ"
+}
+
+// Transport gửi một PDU (mã hàm và dữ liệu) tới unit và trả về PDU phản hồi
+type Transport interface {
+	Send(unit byte, pdu []byte) ([]byte, error)
+}
+
+// Query là một lần đọc như một mục modbus_reader: Count giá trị kiểu Format từ Address
+type Query struct {
+	Unit     int       `json:"unit"`
+	DataType DataType  `json:"dataType"`
+	Address  int       `json:"address"`
+	Count    int       `json:"count"`
+	Format   Format    `json:"format"`
+	Order    ByteOrder `json:"order"`
+}
+
+// Quantity là số bit hoặc thanh ghi cần đọc
+func (q Query) Quantity() int {
+	if q.DataType.Bits() {
+		return q.Count
+	}
+	return q.Count * q.Format.Words()
+}
+
+// Validate kiểm tra mã loại, định dạng và giới hạn số đối tượng của một lần đọc
+func (q Query) Validate() error {
+	if q.Unit < 0 || q.Unit > 255 {
+		return fmt.Errorf("unit id %d ngoài khoảng 0..255", q.Unit)
+	}
+	if q.DataType < Coils || q.DataType > InputRegisters {
+		return fmt.Errorf("loại dữ liệu d_t %d không hợp lệ (1..4)", int(q.DataType))
+	}
+	if !q.DataType.Bits() {
+		if err := checkCodes(q.Format, q.Order); err != nil {
+			return err
+		}
+	}
+	if q.Count < 1 {
+		return fmt.Errorf("số giá trị cần đọc phải lớn hơn 0")
+	}
+	limit := MaxRegisters
+	if q.DataType.Bits() {
+		limit = MaxBits
+	}
+	if q.Quantity() > limit {
+		return fmt.Errorf("%d giá trị %s cần %d %s, vượt quá %d mỗi lần đọc", q.Count, q.Format, q.Quantity(), q.DataType, limit)
+	}
+	if q.Address < 0 || q.Address+q.Quantity() > 65536 {
+		return fmt.Errorf("vùng đọc %d..%d vượt quá địa chỉ 65535", q.Address, q.Address+q.Quantity()-1)
+	}
+	return nil
+}
+
+// Value là một giá trị đã giải mã
+type Value struct {
+	Address int      `json:"address"` // địa chỉ Modbus đầu tiên của giá trị
+	Raw     []uint16 `json:"raw"`
+	Value   float64  `json:"value"`
+	Stored  float64  `json:"stored"` // giá trị logger lưu vào bộ nhớ (float32)
+}
+
+// ReadResult là kết quả một lần đọc: thanh ghi thô (bit là 0/1) và giá trị đã giải mã
+type ReadResult struct {
+	Query    Query    `json:"query"`
+	Raw      []uint16 `json:"raw"`
+	Values   []Value  `json:"values"`
+	Duration int64    `json:"durationMs"`
+}
+
+// Read gửi yêu cầu đọc của q qua t và giải mã phản hồi
+func Read(t Transport, q Query) (*ReadResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	function := byte(q.DataType)
+	pdu := make([]byte, 5)
+	pdu[0] = function
+	binary.BigEndian.PutUint16(pdu[1:], uint16(q.Address))
+	binary.BigEndian.PutUint16(pdu[3:], uint16(q.Quantity()))
+
+	start := time.Now()
+	resp, err := t.Send(byte(q.Unit), pdu)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := parseRead(function, q.Quantity(), resp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReadResult{Query: q, Raw: raw, Values: []Value{}, Duration: time.Since(start).Milliseconds()}
+	if q.DataType.Bits() {
+		for i, bit := range raw {
+			result.Values = append(result.Values, Value{Address: q.Address + i, Raw: raw[i : i+1], Value: float64(bit), Stored: float64(bit)})
+		}
+		return result, nil
+	}
+	values, err := Decode(raw, q.Format, q.Order)
+	if err != nil {
+		return nil, err
+	}
+	n := q.Format.Words()
+	for i, v := range values {
+		result.Values = append(result.Values, Value{Address: q.Address + i*n, Raw: raw[i*n : (i+1)*n], Value: v, Stored: float64(float32(v))})
+	}
+	return result, nil
+}
+
+// parseRead kiểm tra PDU phản hồi của hàm đọc 1..4 và trả về bit (0/1) hoặc thanh ghi
+func parseRead(function byte, quantity int, resp []byte) ([]uint16, error) {
+	if len(resp) >= 2 && resp[0] == function|0x80 {
+		return nil, &Exception{Function: function, Code: resp[1]}
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("phản hồi Modbus quá ngắn (%d byte)", len(resp))
+	}
+	if resp[0] != function {
+		return nil, fmt.Errorf("phản hồi có mã hàm %d, cần %d", resp[0], function)
+	}
+	size := 2 * quantity
+	if DataType(function).Bits() {
+		size = (quantity + 7) / 8
+	}
+	if int(resp[1]) != size || len(resp) != 2+size {
+		return nil, fmt.Errorf("phản hồi có %d byte dữ liệu, cần %d", len(resp)-2, size)
+	}
+
+	data := resp[2:]
+	raw := make([]uint16, quantity)
+	for i := range raw {
+		if DataType(function).Bits() {
+			raw[i] = uint16(data[i/8]>>(i%8)) & 1
+		} else {
+			raw[i] = binary.BigEndian.Uint16(data[2*i:])
+		}
+	}
+	return raw, nil
+}
@@ -0,0 +1,75 @@
+package modbus
+
+import (
+	"errors"
+)
+
+// Trạng thái của một lần đọc khi poll
+const (
+	StatusOK        = "ok"
+	StatusTimeout   = "timeout"
+	StatusCRC       = "crc"
+	StatusException = "exception"
+	StatusError     = "error"
+)
+
+// PollTarget là một mục cần đọc, Name để hiển thị (ví dụ modbus_reader[2] SO2)
+type PollTarget struct {
+	Name  string `json:"name"`
+	Query Query  `json:"query"`
+}
+
+// PollResult là kết quả đọc một mục
+type PollResult struct {
+	Name      string      `json:"name"`
+	Query     Query       `json:"query"`
+	Status    string      `json:"status"`
+	Exception int         `json:"exception,omitempty"` // mã exception khi Status là exception
+	Error     string      `json:"error,omitempty"`
+	Result    *ReadResult `json:"result,omitempty"`
+}
+
+// PollReport là kết quả một lượt poll
+type PollReport struct {
+	OK      int          `json:"ok"`
+	Failed  int          `json:"failed"`
+	Results []PollResult `json:"results"`
+	Stats   RTUStats     `json:"stats"`
+}
+
+// Classify phân loại lỗi đọc thành trạng thái poll và mã exception
+func Classify(err error) (status string, exception int) {
+	var ex *Exception
+	switch {
+	case err == nil:
+		return StatusOK, 0
+	case errors.As(err, &ex):
+		return StatusException, int(ex.Code)
+	case errors.Is(err, ErrTimeout):
+		return StatusTimeout, 0
+	case errors.Is(err, ErrCRC):
+		return StatusCRC, 0
+	}
+	return StatusError, 0
+}
+
+// Poll đọc lần lượt các mục qua t; lỗi của một mục không dừng các mục sau
+func Poll(t Transport, targets []PollTarget) *PollReport {
+	report := &PollReport{Results: []PollResult{}}
+	for _, target := range targets {
+		result, err := Read(t, target.Query)
+		r := PollResult{Name: target.Name, Query: target.Query, Result: result}
+		r.Status, r.Exception = Classify(err)
+		if err != nil {
+			r.Error = err.Error()
+			report.Failed++
+		} else {
+			report.OK++
+		}
+		report.Results = append(report.Results, r)
+	}
+	if c, ok := t.(*RTUClient); ok {
+		report.Stats = c.Stats()
+	}
+	return report
+}
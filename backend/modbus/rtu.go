@@ -0,0 +1,269 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	serial "go.bug.st/serial.v1"
+)
+
+// ErrCRC là lỗi frame RTU nhận được có CRC sai, thường do nhiễu, sai baudrate/parity hoặc đấu dây A/B
+var ErrCRC = errors.New("sai CRC")
+
+// RTUSettings là thông số đường truyền và thời gian chờ, cùng ý nghĩa với rtu_master
+type RTUSettings struct {
+	BaudRate int    `json:"baudrate"`
+	Parity   string `json:"parity"` // N, E, O
+	StopBits int    `json:"stopbits"`
+	Wait     int    `json:"wait"`  // thời gian chờ phản hồi (ms)
+	Retry    int    `json:"retry"` // số lần gửi lại khi timeout hoặc sai CRC
+	Delay    int    `json:"delay"` // thời gian nghỉ giữa hai yêu cầu (ms)
+}
+
+// mode chuyển thông số thành serial.Mode (8 data bit)
+func (s RTUSettings) mode() (*serial.Mode, error) {
+	mode := &serial.Mode{BaudRate: s.BaudRate, DataBits: 8}
+	if s.BaudRate <= 0 {
+		return nil, fmt.Errorf("baudrate không hợp lệ: %d", s.BaudRate)
+	}
+	switch strings.ToUpper(s.Parity) {
+	case "N":
+		mode.Parity = serial.NoParity
+	case "E":
+		mode.Parity = serial.EvenParity
+	case "O":
+		mode.Parity = serial.OddParity
+	default:
+		return nil, fmt.Errorf("parity không hợp lệ: %s (N, E, O)", s.Parity)
+	}
+	switch s.StopBits {
+	case 1:
+		mode.StopBits = serial.OneStopBit
+	case 2:
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("stopbits không hợp lệ: %d (1 hoặc 2)", s.StopBits)
+	}
+	return mode, nil
+}
+
+// frameGap là khoảng lặng 3.5 ký tự giữa hai frame RTU, tối thiểu 1.75 ms theo chuẩn khi baudrate > 19200
+func (s RTUSettings) frameGap() time.Duration {
+	if s.BaudRate <= 0 || s.BaudRate > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return time.Duration(3.5 * 11 * float64(time.Second) / float64(s.BaudRate))
+}
+
+// RTUStats đếm số yêu cầu và lỗi đường truyền của một RTUClient
+type RTUStats struct {
+	Requests  int `json:"requests"`
+	Retries   int `json:"retries"`
+	Timeouts  int `json:"timeouts"`
+	CRCErrors int `json:"crcErrors"`
+}
+
+// RTUClient là Modbus RTU master trên một cổng nối tiếp. Cổng được đọc liên tục bởi một goroutine
+// vì serial.Port không có thời gian chờ khi đọc; Close cũng làm goroutine đó kết thúc.
+type RTUClient struct {
+	port     io.ReadWriteCloser
+	settings RTUSettings
+
+	mu      sync.Mutex
+	chunks  chan []byte
+	readErr error
+	last    time.Time
+	stats   RTUStats
+}
+
+// OpenRTU mở cổng nối tiếp với thông số của settings
+func OpenRTU(portName string, settings RTUSettings) (*RTUClient, error) {
+	mode, err := settings.mode()
+	if err != nil {
+		return nil, err
+	}
+	port, err := serial.Open(portName, mode)
+	if err != nil {
+		return nil, fmt.Errorf("không thể mở cổng %s: %w", portName, err)
+	}
+	return NewRTUClient(port, settings), nil
+}
+
+// NewRTUClient tạo master trên một cổng đã mở (cổng nối tiếp hoặc pty)
+func NewRTUClient(port io.ReadWriteCloser, settings RTUSettings) *RTUClient {
+	c := &RTUClient{port: port, settings: settings, chunks: make(chan []byte, 64)}
+	go c.read()
+	return c
+}
+
+func (c *RTUClient) read() {
+	buf := make([]byte, 256)
+	for {
+		n, err := c.port.Read(buf)
+		if n > 0 {
+			c.chunks <- append([]byte(nil), buf[:n]...)
+		}
+		if err != nil {
+			c.readErr = err
+			close(c.chunks)
+			return
+		}
+	}
+}
+
+// Close đóng cổng
+func (c *RTUClient) Close() error {
+	return c.port.Close()
+}
+
+// Stats trả về số yêu cầu, lần gửi lại, timeout và lỗi CRC từ khi mở cổng
+func (c *RTUClient) Stats() RTUStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Send gửi PDU tới unit, gửi lại tối đa Retry lần khi timeout hoặc sai CRC.
+// Exception của slave không được gửi lại.
+func (c *RTUClient) Send(unit byte, pdu []byte) ([]byte, error) {
+	if unit == 0 {
+		return nil, errors.New("unit 0 là địa chỉ broadcast, slave RTU không trả lời lệnh đọc")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Requests++
+	var err error
+	for attempt := 0; attempt <= c.settings.Retry; attempt++ {
+		if attempt > 0 {
+			c.stats.Retries++
+		}
+		var resp []byte
+		resp, err = c.exchange(unit, pdu)
+		switch {
+		case err == nil:
+			return resp, nil
+		case errors.Is(err, ErrTimeout):
+			c.stats.Timeouts++
+		case errors.Is(err, ErrCRC):
+			c.stats.CRCErrors++
+		default:
+			return nil, err
+		}
+	}
+	if c.settings.Retry > 0 {
+		return nil, fmt.Errorf("%w (đã thử %d lần)", err, c.settings.Retry+1)
+	}
+	return nil, err
+}
+
+// exchange gửi một frame và chờ đủ frame phản hồi
+func (c *RTUClient) exchange(unit byte, pdu []byte) ([]byte, error) {
+	gap := c.settings.frameGap()
+	if delay := time.Duration(c.settings.Delay) * time.Millisecond; delay > gap {
+		gap = delay
+	}
+	if wait := time.Until(c.last.Add(gap)); wait > 0 {
+		time.Sleep(wait)
+	}
+	// Bỏ dữ liệu còn sót từ phản hồi trễ của yêu cầu trước
+	for drained := false; !drained; {
+		select {
+		case _, ok := <-c.chunks:
+			if !ok {
+				return nil, c.closedError()
+			}
+		default:
+			drained = true
+		}
+	}
+
+	frame := append([]byte{unit}, pdu...)
+	frame = appendCRC(frame)
+	if _, err := c.port.Write(frame); err != nil {
+		return nil, fmt.Errorf("không thể gửi frame RTU: %w", err)
+	}
+	defer func() { c.last = time.Now() }()
+
+	wait := time.Duration(c.settings.Wait) * time.Millisecond
+	if wait <= 0 {
+		wait = time.Second
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	var buf []byte
+	for {
+		if n := rtuLength(buf); n > 0 && len(buf) >= n {
+			return checkRTU(buf[:n], unit)
+		}
+		select {
+		case chunk, ok := <-c.chunks:
+			if !ok {
+				return nil, c.closedError()
+			}
+			buf = append(buf, chunk...)
+		case <-timer.C:
+			if len(buf) == 0 {
+				return nil, fmt.Errorf("%w sau %v, unit %d không trả lời", ErrTimeout, wait, unit)
+			}
+			return nil, fmt.Errorf("%w sau %v, chỉ nhận %d byte: % X", ErrTimeout, wait, len(buf), buf)
+		}
+	}
+}
+
+func (c *RTUClient) closedError() error {
+	return fmt.Errorf("cổng nối tiếp đã đóng: %v", c.readErr)
+}
+
+// rtuLength là độ dài frame phản hồi của hàm đọc 1..4 hoặc exception, 0 nếu chưa đủ byte để biết
+func rtuLength(buf []byte) int {
+	switch {
+	case len(buf) >= 2 && buf[1]&0x80 != 0:
+		return 5
+	case len(buf) >= 3 && buf[1] >= byte(Coils) && buf[1] <= byte(InputRegisters):
+		return 5 + int(buf[2])
+	}
+	// Chưa đủ byte, hoặc mã hàm lạ: chờ hết thời gian và báo các byte đã nhận
+	return 0
+}
+
+// checkRTU kiểm tra CRC và unit của frame, trả về PDU
+func checkRTU(frame []byte, unit byte) ([]byte, error) {
+	n := len(frame)
+	want := crc16(frame[:n-2])
+	got := uint16(frame[n-2]) | uint16(frame[n-1])<<8
+	if want != got {
+		return nil, fmt.Errorf("%w: frame % X có CRC %04X, tính được %04X", ErrCRC, frame, got, want)
+	}
+	if frame[0] != unit {
+		return nil, fmt.Errorf("phản hồi từ unit %d, cần %d", frame[0], unit)
+	}
+	return frame[1 : n-2], nil
+}
+
+// crc16 là CRC-16/MODBUS (đa thức 0xA001, giá trị đầu 0xFFFF)
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// appendCRC thêm CRC vào cuối frame, byte thấp trước
+func appendCRC(frame []byte) []byte {
+	crc := crc16(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
@@ -0,0 +1,138 @@
+package modbus
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint16
+	}{
+		{[]byte("123456789"), 0x4B37}, // giá trị kiểm tra của CRC-16/MODBUS
+		{[]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A}, 0xCDC5},
+		{[]byte{0x11, 0x03, 0x00, 0x6B, 0x00, 0x03}, 0x8776},
+		{nil, 0xFFFF},
+	}
+	for _, tt := range tests {
+		if got := crc16(tt.data); got != tt.want {
+			t.Errorf("crc16(% X) = %04X, cần %04X", tt.data, got, tt.want)
+		}
+	}
+	// CRC được gửi byte thấp trước
+	frame := appendCRC([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A})
+	if want := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A, 0xC5, 0xCD}; !bytes.Equal(frame, want) {
+		t.Errorf("appendCRC = % X, cần % X", frame, want)
+	}
+}
+
+func TestRTUFraming(t *testing.T) {
+	tests := []struct {
+		name   string
+		frame  []byte
+		length int
+		pdu    []byte // nil nếu frame phải bị từ chối
+		crc    bool   // lỗi phải là ErrCRC
+	}{
+		{"đọc thanh ghi", appendCRC([]byte{1, 3, 2, 0x12, 0x34}), 7, []byte{3, 2, 0x12, 0x34}, false},
+		{"đọc coils", appendCRC([]byte{1, 1, 1, 0x05}), 6, []byte{1, 1, 0x05}, false},
+		{"exception", appendCRC([]byte{1, 0x83, 2}), 5, []byte{0x83, 2}, false},
+		{"sai CRC", []byte{1, 3, 2, 0x12, 0x34, 0x00, 0x00}, 7, nil, true},
+		{"unit khác", appendCRC([]byte{2, 3, 2, 0x12, 0x34}), 7, nil, false},
+	}
+	for _, tt := range tests {
+		if n := rtuLength(tt.frame); n != tt.length {
+			t.Errorf("%s: rtuLength = %d, cần %d", tt.name, n, tt.length)
+			continue
+		}
+		pdu, err := checkRTU(tt.frame, 1)
+		switch {
+		case tt.pdu != nil && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.pdu != nil && !bytes.Equal(pdu, tt.pdu):
+			t.Errorf("%s: PDU = % X, cần % X", tt.name, pdu, tt.pdu)
+		case tt.pdu == nil && err == nil:
+			t.Errorf("%s: không báo lỗi", tt.name)
+		case tt.crc && !errors.Is(err, ErrCRC):
+			t.Errorf("%s: lỗi %v, cần ErrCRC", tt.name, err)
+		}
+	}
+
+	// Chưa đủ byte để biết độ dài frame
+	for _, partial := range [][]byte{nil, {1}, {1, 3}, {1, 0x10, 0}} {
+		if n := rtuLength(partial); n != 0 {
+			t.Errorf("rtuLength(% X) = %d, cần 0", partial, n)
+		}
+	}
+}
+
+// rtuPair nối RTUClient với slave qua một đường ống trong bộ nhớ thay cho pty
+func rtuPair(t *testing.T, slave *Slave, settings RTUSettings) *RTUClient {
+	t.Helper()
+	master, port := net.Pipe()
+	go slave.ServeRTU(port)
+	client := NewRTUClient(master, settings)
+	t.Cleanup(func() {
+		client.Close()
+		port.Close()
+	})
+	return client
+}
+
+func TestRTURead(t *testing.T) {
+	slave := NewSlave(1, 100)
+	if err := slave.SetValue(HoldingRegisters, 4, Float32, OrderDCBA, 12.5); err != nil {
+		t.Fatal(err)
+	}
+	client := rtuPair(t, slave, RTUSettings{BaudRate: 115200, Wait: 500})
+
+	result, err := Read(client, Query{Unit: 1, DataType: HoldingRegisters, Address: 4, Count: 1, Format: Float32, Order: OrderDCBA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Values) != 1 || result.Values[0].Value != 12.5 {
+		t.Errorf("giá trị = %+v, cần 12.5", result.Values)
+	}
+
+	_, err = Read(client, Query{Unit: 1, DataType: InputRegisters, Address: 100, Count: 1, Format: Int16})
+	var exception *Exception
+	if !errors.As(err, &exception) || exception.Code != 2 || exception.Function != byte(InputRegisters) {
+		t.Errorf("lỗi %v, cần exception 2 của hàm 4", err)
+	}
+	if stats := client.Stats(); stats.Requests != 2 || stats.Retries != 0 {
+		t.Errorf("stats = %+v, cần 2 yêu cầu và không gửi lại", stats)
+	}
+}
+
+func TestRTULineErrors(t *testing.T) {
+	query := Query{Unit: 1, DataType: HoldingRegisters, Count: 1, Format: Int16}
+	tests := []struct {
+		name  string
+		slave func(*Slave)
+		err   error
+		stats RTUStats
+	}{
+		{"luôn sai CRC", func(s *Slave) { s.CRCErrorRate = 1 }, ErrCRC, RTUStats{Requests: 1, Retries: 2, CRCErrors: 3}},
+		{"không phản hồi", func(s *Slave) { s.DropRate = 1 }, ErrTimeout, RTUStats{Requests: 1, Retries: 2, Timeouts: 3}},
+	}
+	for _, tt := range tests {
+		slave := NewSlave(1, 10)
+		tt.slave(slave)
+		client := rtuPair(t, slave, RTUSettings{BaudRate: 115200, Wait: 50, Retry: 2})
+		_, err := Read(client, query)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: lỗi %v, cần %v", tt.name, err, tt.err)
+		}
+		if stats := client.Stats(); stats != tt.stats {
+			t.Errorf("%s: stats = %+v, cần %+v", tt.name, stats, tt.stats)
+		}
+	}
+
+	client := rtuPair(t, NewSlave(0, 10), RTUSettings{BaudRate: 115200, Wait: 50})
+	if _, err := client.Send(0, []byte{3, 0, 0, 0, 1}); err == nil {
+		t.Error("gửi lệnh đọc tới unit 0 (broadcast) không báo lỗi")
+	}
+}
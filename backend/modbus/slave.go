@@ -0,0 +1,240 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"sync"
+)
+
+// Slave là thiết bị Modbus giả lập trong bộ nhớ, dùng thay máy phân tích thật khi
+// kiểm thử master. Địa chỉ từ Size trở lên trả exception 2 như thiết bị có vùng thanh ghi giới hạn.
+type Slave struct {
+	Unit byte // unit id được trả lời, 0 để trả lời mọi unit
+	Size int  // số địa chỉ của mỗi bảng
+
+	// Nhiễu giả lập cho RTU (0..1): tỉ lệ phản hồi sai CRC và tỉ lệ không phản hồi
+	CRCErrorRate float64
+	DropRate     float64
+
+	mu       sync.Mutex
+	coils    []bool
+	discrete []bool
+	holding  []uint16
+	input    []uint16
+}
+
+// NewSlave tạo slave với mọi bit và thanh ghi bằng 0
+func NewSlave(unit byte, size int) *Slave {
+	if size <= 0 || size > 65536 {
+		size = 65536
+	}
+	return &Slave{
+		Unit:     unit,
+		Size:     size,
+		coils:    make([]bool, size),
+		discrete: make([]bool, size),
+		holding:  make([]uint16, size),
+		input:    make([]uint16, size),
+	}
+}
+
+// SetBits đặt giá trị coils hoặc discrete inputs từ address
+func (s *Slave) SetBits(t DataType, address int, values ...bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	table := s.coils
+	switch t {
+	case Coils:
+	case DiscreteInputs:
+		table = s.discrete
+	default:
+		return fmt.Errorf("%s không phải loại bit", t)
+	}
+	if address < 0 || address+len(values) > s.Size {
+		return fmt.Errorf("vùng %d..%d ngoài khoảng 0..%d", address, address+len(values)-1, s.Size-1)
+	}
+	copy(table[address:], values)
+	return nil
+}
+
+// SetRegisters đặt giá trị holding hoặc input registers từ address
+func (s *Slave) SetRegisters(t DataType, address int, values ...uint16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	table := s.holding
+	switch t {
+	case HoldingRegisters:
+	case InputRegisters:
+		table = s.input
+	default:
+		return fmt.Errorf("%s không phải loại thanh ghi", t)
+	}
+	if address < 0 || address+len(values) > s.Size {
+		return fmt.Errorf("vùng %d..%d ngoài khoảng 0..%d", address, address+len(values)-1, s.Size-1)
+	}
+	copy(table[address:], values)
+	return nil
+}
+
+// SetValue mã hóa value theo format/order rồi ghi vào thanh ghi từ address
+func (s *Slave) SetValue(t DataType, address int, format Format, order ByteOrder, value float64) error {
+	if t.Bits() {
+		return s.SetBits(t, address, value != 0)
+	}
+	words, err := Encode(value, format, order)
+	if err != nil {
+		return err
+	}
+	return s.SetRegisters(t, address, words...)
+}
+
+// Handle xử lý một PDU yêu cầu và trả PDU phản hồi (hỗ trợ hàm đọc 1..4)
+func (s *Slave) Handle(pdu []byte) []byte {
+	if len(pdu) == 0 {
+		return nil
+	}
+	function := pdu[0]
+	exception := func(code byte) []byte {
+		return []byte{function | 0x80, code}
+	}
+	if function < byte(Coils) || function > byte(InputRegisters) {
+		return exception(1)
+	}
+	if len(pdu) != 5 {
+		return exception(3)
+	}
+	address := int(binary.BigEndian.Uint16(pdu[1:]))
+	quantity := int(binary.BigEndian.Uint16(pdu[3:]))
+	t := DataType(function)
+	limit := MaxRegisters
+	if t.Bits() {
+		limit = MaxBits
+	}
+	if quantity < 1 || quantity > limit {
+		return exception(3)
+	}
+	if address+quantity > s.Size {
+		return exception(2)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Bits() {
+		table := s.coils
+		if t == DiscreteInputs {
+			table = s.discrete
+		}
+		data := make([]byte, (quantity+7)/8)
+		for i := 0; i < quantity; i++ {
+			if table[address+i] {
+				data[i/8] |= 1 << (i % 8)
+			}
+		}
+		return append([]byte{function, byte(len(data))}, data...)
+	}
+	table := s.holding
+	if t == InputRegisters {
+		table = s.input
+	}
+	resp := make([]byte, 2+2*quantity)
+	resp[0], resp[1] = function, byte(2*quantity)
+	for i := 0; i < quantity; i++ {
+		binary.BigEndian.PutUint16(resp[2+2*i:], table[address+i])
+	}
+	return resp
+}
+
+// ServeTCP nhận kết nối Modbus TCP cho tới khi listener bị đóng
+func (s *Slave) ServeTCP(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := s.serveTCPConn(conn); err != nil && !errors.Is(err, io.EOF) {
+				log.Printf("Modbus slave %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// serveTCPConn trả lời từng frame MBAP; unit khác Unit nhận exception 11 như gateway
+func (s *Slave) serveTCPConn(conn net.Conn) error {
+	header := make([]byte, mbapSize)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return err
+		}
+		length := int(binary.BigEndian.Uint16(header[4:]))
+		if length < 2 || length > 254 {
+			return fmt.Errorf("header MBAP có độ dài %d không hợp lệ", length)
+		}
+		pdu := make([]byte, length-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return err
+		}
+
+		resp := []byte{pdu[0] | 0x80, 11}
+		if s.Unit == 0 || header[6] == s.Unit {
+			resp = s.Handle(pdu)
+		}
+		frame := make([]byte, mbapSize+len(resp))
+		copy(frame, header[:4])
+		binary.BigEndian.PutUint16(frame[4:], uint16(len(resp)+1))
+		frame[6] = header[6]
+		copy(frame[mbapSize:], resp)
+		if _, err := conn.Write(frame); err != nil {
+			return err
+		}
+	}
+}
+
+// rtuRequestSize là độ dài frame yêu cầu của hàm đọc 1..4: unit, hàm, địa chỉ, số lượng, CRC
+const rtuRequestSize = 8
+
+// ServeRTU trả lời các frame RTU trên conn (đầu master của pty) cho tới khi đọc lỗi.
+// Frame sai CRC hoặc gửi cho unit khác bị bỏ qua không trả lời như slave thật.
+func (s *Slave) ServeRTU(conn io.ReadWriter) error {
+	var buf []byte
+	chunk := make([]byte, 256)
+	for {
+		n, err := conn.Read(chunk)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, chunk[:n]...)
+
+		for len(buf) >= rtuRequestSize {
+			frame := buf[:rtuRequestSize]
+			if crc16(frame[:rtuRequestSize-2]) != uint16(frame[6])|uint16(frame[7])<<8 {
+				// Mất đồng bộ: trượt một byte để tìm đầu frame
+				buf = buf[1:]
+				continue
+			}
+			buf = buf[rtuRequestSize:]
+			if s.Unit != 0 && frame[0] != s.Unit {
+				continue
+			}
+			if rand.Float64() < s.DropRate {
+				continue
+			}
+			resp := appendCRC(append([]byte{frame[0]}, s.Handle(frame[1:6])...))
+			if rand.Float64() < s.CRCErrorRate {
+				resp[len(resp)-1] ^= 0xFF
+			}
+			if _, err := conn.Write(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// mbapSize là độ dài header MBAP: transaction, protocol, length, unit
+const mbapSize = 7
+
+// TCPClient là Modbus TCP master trên một kết nối
+type TCPClient struct {
+	conn        net.Conn
+	timeout     time.Duration
+	mu          sync.Mutex
+	transaction uint16
+}
+
+// DialTCP kết nối tới slave Modbus TCP, address dạng host:port như dev_a
+func DialTCP(address string, timeout time.Duration) (*TCPClient, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("không kết nối được Modbus TCP %s: %w", address, err)
+	}
+	return &TCPClient{conn: conn, timeout: timeout}, nil
+}
+
+// Close đóng kết nối
+func (c *TCPClient) Close() error {
+	return c.conn.Close()
+}
+
+// Send gửi PDU kèm header MBAP và chờ phản hồi có cùng transaction id
+func (c *TCPClient) Send(unit byte, pdu []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.transaction++
+	frame := make([]byte, mbapSize+len(pdu))
+	binary.BigEndian.PutUint16(frame[0:], c.transaction)
+	binary.BigEndian.PutUint16(frame[4:], uint16(len(pdu)+1))
+	frame[6] = unit
+	copy(frame[mbapSize:], pdu)
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		return nil, c.wrap(err)
+	}
+
+	header := make([]byte, mbapSize)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return nil, c.wrap(err)
+	}
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if length < 2 || length > 254 {
+		return nil, fmt.Errorf("header MBAP có độ dài %d không hợp lệ", length)
+	}
+	body := make([]byte, length-1)
+	if _, err := io.ReadFull(c.conn, body); err != nil {
+		return nil, c.wrap(err)
+	}
+
+	switch {
+	case binary.BigEndian.Uint16(header[0:]) != c.transaction:
+		return nil, fmt.Errorf("phản hồi có transaction id %d, cần %d", binary.BigEndian.Uint16(header[0:]), c.transaction)
+	case binary.BigEndian.Uint16(header[2:]) != 0:
+		return nil, fmt.Errorf("phản hồi có protocol id %d, cần 0", binary.BigEndian.Uint16(header[2:]))
+	case header[6] != unit:
+		return nil, fmt.Errorf("phản hồi từ unit %d, cần %d", header[6], unit)
+	}
+	return body, nil
+}
+
+// wrap đổi lỗi quá hạn của kết nối thành ErrTimeout
+func (c *TCPClient) wrap(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w sau %v", ErrTimeout, c.timeout)
+	}
+	return fmt.Errorf("lỗi kết nối Modbus TCP: %w", err)
+}
+
+// ReadTCP kết nối tới address, đọc một lần theo q rồi đóng kết nối
+func ReadTCP(address string, q Query, timeout time.Duration) (*ReadResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := DialTCP(address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return Read(client, q)
+}
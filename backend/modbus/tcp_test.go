@@ -0,0 +1,141 @@
+package modbus
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// serveSlave chạy slave trên 127.0.0.1:0 và trả về địa chỉ để ReadTCP kết nối
+func serveSlave(t *testing.T, slave *Slave) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go slave.ServeTCP(listener)
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().String()
+}
+
+func TestReadTCP(t *testing.T) {
+	slave := NewSlave(1, 100)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(slave.SetValue(HoldingRegisters, 10, Float32, OrderCDAB, 23.5))
+	must(slave.SetValue(HoldingRegisters, 12, Float32, OrderCDAB, -1.25))
+	must(slave.SetRegisters(InputRegisters, 0, 1, 0xFFFF, 300))
+	must(slave.SetValue(InputRegisters, 20, Int32, OrderABCD, 123456))
+	must(slave.SetBits(Coils, 5, true, false, true))
+	must(slave.SetBits(DiscreteInputs, 0, false, true))
+	address := serveSlave(t, slave)
+
+	tests := []struct {
+		name   string
+		query  Query
+		raw    []uint16
+		values []float64
+	}{
+		{
+			name:   "float32 CD AB",
+			query:  Query{Unit: 1, DataType: HoldingRegisters, Address: 10, Count: 2, Format: Float32, Order: OrderCDAB},
+			raw:    []uint16{0x0000, 0x41BC, 0x0000, 0xBFA0},
+			values: []float64{23.5, -1.25},
+		},
+		{
+			name:   "int16 input registers",
+			query:  Query{Unit: 1, DataType: InputRegisters, Address: 0, Count: 3, Format: Int16},
+			raw:    []uint16{1, 0xFFFF, 300},
+			values: []float64{1, -1, 300},
+		},
+		{
+			name:   "int32 AB CD",
+			query:  Query{Unit: 1, DataType: InputRegisters, Address: 20, Count: 1, Format: Int32},
+			raw:    []uint16{0x0001, 0xE240},
+			values: []float64{123456},
+		},
+		{
+			name:   "coils",
+			query:  Query{Unit: 1, DataType: Coils, Address: 5, Count: 3},
+			raw:    []uint16{1, 0, 1},
+			values: []float64{1, 0, 1},
+		},
+		{
+			name:   "discrete inputs",
+			query:  Query{Unit: 1, DataType: DiscreteInputs, Address: 0, Count: 2},
+			raw:    []uint16{0, 1},
+			values: []float64{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		result, err := ReadTCP(address, tt.query, time.Second)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(result.Raw, tt.raw) {
+			t.Errorf("%s: raw = %04X, cần %04X", tt.name, result.Raw, tt.raw)
+		}
+		var values []float64
+		for _, v := range result.Values {
+			values = append(values, v.Value)
+		}
+		if !reflect.DeepEqual(values, tt.values) {
+			t.Errorf("%s: giá trị = %v, cần %v", tt.name, values, tt.values)
+		}
+	}
+}
+
+func TestReadTCPErrors(t *testing.T) {
+	address := serveSlave(t, NewSlave(1, 100))
+
+	tests := []struct {
+		name      string
+		query     Query
+		exception byte // 0 nếu lỗi không phải exception của slave
+	}{
+		{"ngoài vùng thanh ghi", Query{Unit: 1, DataType: HoldingRegisters, Address: 99, Count: 1, Format: Float32}, 2},
+		{"unit khác", Query{Unit: 2, DataType: HoldingRegisters, Address: 0, Count: 1, Format: Int16}, 11},
+		{"số giá trị bằng 0", Query{Unit: 1, DataType: HoldingRegisters, Count: 0, Format: Int16}, 0},
+		{"vượt 125 thanh ghi", Query{Unit: 1, DataType: HoldingRegisters, Count: 32, Format: Int64}, 0},
+		{"d_t không hợp lệ", Query{Unit: 1, DataType: 5, Count: 1, Format: Int16}, 0},
+	}
+	for _, tt := range tests {
+		_, err := ReadTCP(address, tt.query, time.Second)
+		if err == nil {
+			t.Errorf("%s: không báo lỗi", tt.name)
+			continue
+		}
+		var exception *Exception
+		if errors.As(err, &exception) != (tt.exception != 0) || (exception != nil && exception.Code != tt.exception) {
+			t.Errorf("%s: lỗi %v, cần exception %d", tt.name, err, tt.exception)
+		}
+	}
+}
+
+func TestReadTCPTimeout(t *testing.T) {
+	// Slave nhận kết nối nhưng không bao giờ trả lời
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	query := Query{Unit: 1, DataType: HoldingRegisters, Count: 1, Format: Int16}
+	_, err = ReadTCP(listener.Addr().String(), query, 100*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("lỗi %v, cần ErrTimeout", err)
+	}
+}
@@ -0,0 +1,191 @@
+package script
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Node là một nút của cây cú pháp
+type Node interface {
+	Pos() Pos
+	String() string
+}
+
+// Expr là một biểu thức
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// NumberLit là hằng số, ví dụ 4, 3.5
+type NumberLit struct {
+	At    Pos
+	Text  string
+	Value float64
+}
+
+// Ident là một tên đứng riêng; ngôn ngữ không có biến nên chỉ xuất hiện khi code sai
+type Ident struct {
+	At   Pos
+	Name string
+}
+
+// Call là lời gọi hàm, ví dụ set(105, get(0))
+type Call struct {
+	At   Pos
+	Name string
+	Args []Expr
+}
+
+// Unary là biểu thức một ngôi: -x, +x, !x
+type Unary struct {
+	At Pos
+	Op Kind
+	X  Expr
+}
+
+// Binary là biểu thức hai ngôi: x + y, x < y, x && y, ...
+type Binary struct {
+	X     Expr
+	Op    Kind
+	OpPos Pos
+	Y     Expr
+}
+
+// Ternary là biểu thức điều kiện cond ? then : else
+type Ternary struct {
+	Cond     Expr
+	Then     Expr
+	Else     Expr
+	Question Pos
+	Colon    Pos
+}
+
+// Paren là biểu thức trong ngoặc, giữ lại để in lại đúng code gốc
+type Paren struct {
+	At Pos
+	X  Expr
+}
+
+// BadExpr đánh dấu chỗ code lỗi cú pháp
+type BadExpr struct {
+	At Pos
+}
+
+func (*NumberLit) exprNode() {}
+func (*Ident) exprNode()     {}
+func (*Call) exprNode()      {}
+func (*Unary) exprNode()     {}
+func (*Binary) exprNode()    {}
+func (*Ternary) exprNode()   {}
+func (*Paren) exprNode()     {}
+func (*BadExpr) exprNode()   {}
+
+func (x *NumberLit) Pos() Pos { return x.At }
+func (x *Ident) Pos() Pos     { return x.At }
+func (x *Call) Pos() Pos      { return x.At }
+func (x *Unary) Pos() Pos     { return x.At }
+func (x *Binary) Pos() Pos    { return x.X.Pos() }
+func (x *Ternary) Pos() Pos   { return x.Cond.Pos() }
+func (x *Paren) Pos() Pos     { return x.At }
+func (x *BadExpr) Pos() Pos   { return x.At }
+
+func (x *NumberLit) String() string { return x.Text }
+func (x *Ident) String() string     { return x.Name }
+func (x *BadExpr) String() string   { return "<lỗi>" }
+func (x *Paren) String() string     { return "(" + x.X.String() + ")" }
+
+func (x *Call) String() string {
+	args := make([]string, len(x.Args))
+	for i, arg := range x.Args {
+		args[i] = arg.String()
+	}
+	return x.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (x *Unary) String() string {
+	return strings.Trim(x.Op.String(), "'") + x.X.String()
+}
+
+func (x *Binary) String() string {
+	return x.X.String() + " " + strings.Trim(x.Op.String(), "'") + " " + x.Y.String()
+}
+
+func (x *Ternary) String() string {
+	return x.Cond.String() + " ? " + x.Then.String() + " : " + x.Else.String()
+}
+
+// Stmt là một câu lệnh: biểu thức kết thúc bằng ';'
+type Stmt struct {
+	X    Expr
+	Semi Pos
+}
+
+func (s *Stmt) Pos() Pos       { return s.X.Pos() }
+func (s *Stmt) String() string { return s.X.String() + ";" }
+
+// Program là toàn bộ một đoạn code prog/timers
+type Program struct {
+	Stmts    []*Stmt
+	Comments []Comment
+}
+
+func (p *Program) Pos() Pos {
+	if len(p.Stmts) > 0 {
+		return p.Stmts[0].Pos()
+	}
+	return Pos{Line: 1, Column: 1}
+}
+
+func (p *Program) String() string {
+	lines := make([]string, len(p.Stmts))
+	for i, stmt := range p.Stmts {
+		lines[i] = stmt.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Walk duyệt cây theo thứ tự trước, fn trả về false để bỏ qua các nút con
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *Program:
+		for _, stmt := range n.Stmts {
+			Walk(stmt, fn)
+		}
+	case *Stmt:
+		Walk(n.X, fn)
+	case *Call:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	case *Unary:
+		Walk(n.X, fn)
+	case *Binary:
+		Walk(n.X, fn)
+		Walk(n.Y, fn)
+	case *Ternary:
+		Walk(n.Cond, fn)
+		Walk(n.Then, fn)
+		Walk(n.Else, fn)
+	case *Paren:
+		Walk(n.X, fn)
+	}
+}
+
+// Unparen bỏ các lớp ngoặc bao ngoài biểu thức
+func Unparen(x Expr) Expr {
+	for {
+		p, ok := x.(*Paren)
+		if !ok {
+			return x
+		}
+		x = p.X
+	}
+}
+
+func parseNumber(text string) (float64, error) {
+	return strconv.ParseFloat(text, 64)
+}
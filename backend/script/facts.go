@@ -0,0 +1,245 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// fact là một điều kiện đã biết trên đường tới một nhánh ba ngôi,
+// dạng "subject op value", ví dụ getint(41) == 2 hoặc get(0) < 3.5
+type fact struct {
+	subject string
+	op      Kind
+	value   float64
+	text    string
+}
+
+// facts là các điều kiện đã biết, dùng để tìm nhánh không bao giờ chạy trong chuỗi
+// cond1 ? a : cond2 ? b : c. Chỉ xét biểu thức không có tác dụng phụ (get, getint, tofloat32),
+// vì giá trị của chúng không đổi trong một câu lệnh.
+type facts []fact
+
+// with trả về facts cộng thêm điều kiện cond có giá trị truth
+func (f facts) with(cond Expr, truth bool) facts {
+	cond = Unparen(cond)
+	switch n := cond.(type) {
+	case *Unary:
+		if n.Op == Not {
+			return f.with(n.X, !truth)
+		}
+	case *Binary:
+		// a && b đúng thì cả hai đúng, a || b sai thì cả hai sai
+		if (n.Op == And && truth) || (n.Op == Or && !truth) {
+			return f.with(n.X, truth).with(n.Y, truth)
+		}
+	}
+
+	subject, op, value, ok := atom(cond)
+	if !ok {
+		return f
+	}
+	if !truth {
+		op = negate(op)
+	}
+	result := make(facts, len(f), len(f)+1)
+	copy(result, f)
+	return append(result, fact{subject: subject, op: op, value: value, text: fmt.Sprintf("%s là %s", cond, truthName(truth))})
+}
+
+// decide cho biết cond luôn đúng hay luôn sai theo các điều kiện đã biết
+func (f facts) decide(cond Expr) (truth bool, reason string, ok bool) {
+	cond = Unparen(cond)
+	switch n := cond.(type) {
+	case *Unary:
+		if n.Op == Not {
+			truth, reason, ok = f.decide(n.X)
+			return !truth, reason, ok
+		}
+	case *Binary:
+		if n.Op == And || n.Op == Or {
+			// a && b sai khi một vế sai, đúng khi cả hai đúng; || ngược lại
+			short := n.Op == Or
+			a, reasonA, okA := f.decide(n.X)
+			b, reasonB, okB := f.decide(n.Y)
+			switch {
+			case okA && a == short:
+				return short, reasonA, true
+			case okB && b == short:
+				return short, reasonB, true
+			case okA && okB:
+				return !short, reasonA + " và " + reasonB, true
+			}
+			return false, "", false
+		}
+	}
+
+	subject, op, value, ok := atom(cond)
+	if !ok {
+		return false, "", false
+	}
+	r := anyRegion()
+	var used []string
+	for _, k := range f {
+		if k.subject == subject {
+			r.restrict(k.op, k.value)
+			used = append(used, k.text)
+		}
+	}
+	if len(used) == 0 {
+		return false, "", false
+	}
+	reason = "đã biết " + strings.Join(used, " và ")
+	if !r.allows(op, value) {
+		return false, reason, true
+	}
+	if !r.allows(negate(op), value) {
+		return true, reason, true
+	}
+	return false, "", false
+}
+
+func truthName(truth bool) string {
+	if truth {
+		return "đúng"
+	}
+	return "sai"
+}
+
+// atom đưa điều kiện về dạng subject op value. Điều kiện không phải phép so sánh với hằng
+// được coi là "cond != 0". ok là false nếu điều kiện có tác dụng phụ.
+func atom(cond Expr) (subject string, op Kind, value float64, ok bool) {
+	cond = Unparen(cond)
+	if b, isBinary := cond.(*Binary); isBinary && isComparison(b.Op) {
+		if c, isConst := Const(b.Y); isConst && pure(b.X) {
+			return Unparen(b.X).String(), b.Op, c, true
+		}
+		if c, isConst := Const(b.X); isConst && pure(b.Y) {
+			return Unparen(b.Y).String(), flip(b.Op), c, true
+		}
+	}
+	if !pure(cond) {
+		return "", 0, 0, false
+	}
+	return cond.String(), Ne, 0, true
+}
+
+// pure cho biết biểu thức chỉ đọc dữ liệu, không có tác dụng phụ và không lỗi
+func pure(x Expr) bool {
+	ok := true
+	Walk(x, func(n Node) bool {
+		switch n := n.(type) {
+		case *Call:
+			if b, known := Builtins[n.Name]; !known || !b.Value {
+				ok = false
+			}
+		case *Ident, *BadExpr:
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+func isComparison(op Kind) bool {
+	switch op {
+	case Lt, Gt, Le, Ge, Eq, Ne:
+		return true
+	}
+	return false
+}
+
+// negate trả về phép so sánh phủ định: !(x < c) là x >= c
+func negate(op Kind) Kind {
+	switch op {
+	case Lt:
+		return Ge
+	case Ge:
+		return Lt
+	case Gt:
+		return Le
+	case Le:
+		return Gt
+	case Eq:
+		return Ne
+	case Ne:
+		return Eq
+	}
+	return op
+}
+
+// flip đổi chiều phép so sánh khi đổi chỗ hai vế: c < x là x > c
+func flip(op Kind) Kind {
+	switch op {
+	case Lt:
+		return Gt
+	case Gt:
+		return Lt
+	case Le:
+		return Ge
+	case Ge:
+		return Le
+	}
+	return op
+}
+
+// region là tập giá trị còn có thể của một subject: một khoảng trừ đi vài điểm
+type region struct {
+	lo, hi     float64
+	loIn, hiIn bool
+	except     []float64
+}
+
+func anyRegion() region {
+	return region{lo: math.Inf(-1), hi: math.Inf(1)}
+}
+
+func (r *region) restrict(op Kind, c float64) {
+	switch op {
+	case Lt:
+		if c < r.hi || (c == r.hi && r.hiIn) {
+			r.hi, r.hiIn = c, false
+		}
+	case Le:
+		if c < r.hi {
+			r.hi, r.hiIn = c, true
+		}
+	case Gt:
+		if c > r.lo || (c == r.lo && r.loIn) {
+			r.lo, r.loIn = c, false
+		}
+	case Ge:
+		if c > r.lo {
+			r.lo, r.loIn = c, true
+		}
+	case Eq:
+		r.restrict(Ge, c)
+		r.restrict(Le, c)
+	case Ne:
+		r.except = append(r.except, c)
+	}
+}
+
+func (r region) empty() bool {
+	if r.lo > r.hi {
+		return true
+	}
+	if r.lo == r.hi {
+		if !r.loIn || !r.hiIn {
+			return true
+		}
+		for _, e := range r.except {
+			if e == r.lo {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allows cho biết còn giá trị nào thỏa "op c" hay không
+func (r region) allows(op Kind, c float64) bool {
+	r.except = append([]float64(nil), r.except...)
+	r.restrict(op, c)
+	return !r.empty()
+}
@@ -0,0 +1,310 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Loại thay đổi khi chạy script
+const (
+	TargetMemory = "memory" // thanh ghi bộ nhớ
+	TargetOutput = "output" // DO
+	TargetTimer  = "timer"  // timer, giá trị 1 là đang chạy, 0 là đã dừng
+)
+
+// RuntimeError là lỗi khi chạy script, kèm vị trí trong code
+type RuntimeError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("dòng %d, cột %d: %s", e.Line, e.Column, e.Message)
+}
+
+func runtimeError(pos Pos, format string, args ...interface{}) *RuntimeError {
+	return &RuntimeError{Line: pos.Line, Column: pos.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+// Event là một lần script ghi bộ nhớ, đặt DO hoặc dừng timer
+type Event struct {
+	Source string  `json:"source,omitempty"` // prog[2], timers[0], ... do nơi gọi điền
+	Target string  `json:"target"`
+	Index  int     `json:"index"`
+	Value  float64 `json:"value"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+// Change là một thanh ghi, DO hoặc timer có giá trị khác sau khi chạy
+type Change struct {
+	Target string  `json:"target"`
+	Index  int     `json:"index"`
+	Old    float64 `json:"old"`
+	New    float64 `json:"new"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s[%d]: %s -> %s", c.Target, c.Index, FormatValue(c.Old), FormatValue(c.New))
+}
+
+// FormatValue in giá trị thanh ghi với độ chính xác float32, ví dụ 10.12 thay vì 10.119999885559082
+func FormatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 32)
+}
+
+// Result là trạng thái sau khi chạy thử và những gì đã thay đổi
+type Result struct {
+	Memory  []float64 `json:"memory"`
+	Outputs []bool    `json:"outputs"`
+	Timers  []bool    `json:"timers"`
+	Changes []Change  `json:"changes"`
+	Events  []Event   `json:"events"`
+}
+
+// Machine là datalogger ảo để chạy script prog/timers không cần thiết bị.
+// Bộ nhớ lưu float32 như firmware: set làm tròn về float32, setint cắt phần thập phân.
+type Machine struct {
+	Memory  []float64
+	Outputs []bool
+	Timers  []bool // timer còn chạy, timerstop đặt về false
+}
+
+// NewMachine tạo máy ảo với bộ nhớ bằng 0, DO tắt và mọi timer đang chạy
+func NewMachine(opts Options) *Machine {
+	m := &Machine{
+		Memory:  make([]float64, opts.MemorySize),
+		Outputs: make([]bool, opts.Outputs),
+		Timers:  make([]bool, opts.Timers),
+	}
+	for i := range m.Timers {
+		m.Timers[i] = true
+	}
+	return m
+}
+
+// Clone sao chép trạng thái, dùng để so sánh trước và sau khi chạy
+func (m *Machine) Clone() *Machine {
+	return &Machine{
+		Memory:  append([]float64(nil), m.Memory...),
+		Outputs: append([]bool(nil), m.Outputs...),
+		Timers:  append([]bool(nil), m.Timers...),
+	}
+}
+
+// Result so sánh trạng thái hiện tại với before
+func (m *Machine) Result(before *Machine, events []Event) *Result {
+	r := &Result{
+		Memory:  append([]float64(nil), m.Memory...),
+		Outputs: append([]bool(nil), m.Outputs...),
+		Timers:  append([]bool(nil), m.Timers...),
+		Changes: []Change{},
+		Events:  events,
+	}
+	if r.Events == nil {
+		r.Events = []Event{}
+	}
+	for i, v := range m.Memory {
+		if i < len(before.Memory) && v != before.Memory[i] {
+			r.Changes = append(r.Changes, Change{Target: TargetMemory, Index: i, Old: before.Memory[i], New: v})
+		}
+	}
+	for i, v := range m.Outputs {
+		if i < len(before.Outputs) && v != before.Outputs[i] {
+			r.Changes = append(r.Changes, Change{Target: TargetOutput, Index: i, Old: boolValue(before.Outputs[i]), New: boolValue(v)})
+		}
+	}
+	for i, v := range m.Timers {
+		if i < len(before.Timers) && v != before.Timers[i] {
+			r.Changes = append(r.Changes, Change{Target: TargetTimer, Index: i, Old: boolValue(before.Timers[i]), New: boolValue(v)})
+		}
+	}
+	return r
+}
+
+// Run phân tích và chạy code. Code lỗi cú pháp không được chạy.
+func (m *Machine) Run(src string) ([]Event, error) {
+	prog, diags := Parse(src)
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return nil, &RuntimeError{Line: d.Line, Column: d.Column, Message: d.Message}
+		}
+	}
+	return m.Exec(prog)
+}
+
+// Exec chạy lần lượt các câu lệnh một lần, như một vòng quét của firmware.
+// Lỗi khi chạy dừng ở câu lệnh gây lỗi; các thay đổi trước đó vẫn giữ.
+func (m *Machine) Exec(prog *Program) ([]Event, error) {
+	e := &evaluator{m: m, events: []Event{}}
+	for _, stmt := range prog.Stmts {
+		if _, err := e.eval(stmt.X); err != nil {
+			return e.events, err
+		}
+	}
+	return e.events, nil
+}
+
+type evaluator struct {
+	m      *Machine
+	events []Event
+}
+
+func (e *evaluator) eval(x Expr) (float64, error) {
+	switch n := x.(type) {
+	case *NumberLit:
+		return n.Value, nil
+	case *Paren:
+		return e.eval(n.X)
+	case *Unary:
+		v, err := e.eval(n.X)
+		if err != nil {
+			return 0, err
+		}
+		return unaryOp(n.Op, v), nil
+	case *Binary:
+		a, err := e.eval(n.X)
+		if err != nil {
+			return 0, err
+		}
+		// && và || rút gọn như C, vế phải có thể không được chạy
+		if n.Op == And && a == 0 {
+			return 0, nil
+		}
+		if n.Op == Or && a != 0 {
+			return 1, nil
+		}
+		b, err := e.eval(n.Y)
+		if err != nil {
+			return 0, err
+		}
+		v, ok := binaryOp(n.Op, a, b)
+		if !ok {
+			return 0, runtimeError(n.OpPos, "chia cho 0 trong %s", n)
+		}
+		return v, nil
+	case *Ternary:
+		cond, err := e.eval(n.Cond)
+		if err != nil {
+			return 0, err
+		}
+		if cond != 0 {
+			return e.eval(n.Then)
+		}
+		return e.eval(n.Else)
+	case *Call:
+		return e.call(n)
+	case *Ident:
+		return 0, runtimeError(n.At, "tên %q không được định nghĩa", n.Name)
+	}
+	return 0, runtimeError(x.Pos(), "biểu thức không hợp lệ")
+}
+
+func (e *evaluator) call(c *Call) (float64, error) {
+	builtin, ok := Builtins[c.Name]
+	if !ok {
+		return 0, runtimeError(c.At, "hàm %s không tồn tại", c.Name)
+	}
+	if len(c.Args) != builtin.Args {
+		return 0, runtimeError(c.At, "%s cần %d tham số nhưng có %d", c.Name, builtin.Args, len(c.Args))
+	}
+	args := make([]float64, len(c.Args))
+	for i, arg := range c.Args {
+		v, err := e.eval(arg)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+
+	var index int
+	if builtin.Index != IndexNone {
+		i, err := e.index(c, builtin.Index, args[0])
+		if err != nil {
+			return 0, err
+		}
+		index = i
+	}
+
+	switch c.Name {
+	case "get":
+		return e.m.Memory[index], nil
+	case "getint":
+		return math.Trunc(e.m.Memory[index]), nil
+	case "set", "setint":
+		value := args[1]
+		if math.IsNaN(value) || math.IsInf(value, 0) || math.Abs(value) > math.MaxFloat32 {
+			return 0, runtimeError(c.At, "giá trị %g ghi vào thanh ghi %d vượt quá float32", value, index)
+		}
+		if c.Name == "setint" {
+			value = math.Trunc(value)
+		}
+		value = float64(float32(value))
+		e.m.Memory[index] = value
+		e.emit(c, TargetMemory, index, value)
+	case "setdo":
+		e.m.Outputs[index] = args[1] != 0
+		e.emit(c, TargetOutput, index, boolValue(args[1] != 0))
+	case "timerstop":
+		e.m.Timers[index] = false
+		e.emit(c, TargetTimer, index, 0)
+	case "tofloat32":
+		return float64(float32(args[0])), nil
+	}
+	return 0, nil
+}
+
+// index kiểm tra chỉ số thanh ghi/DO/timer là số nguyên trong phạm vi của máy ảo
+func (e *evaluator) index(c *Call, kind IndexKind, value float64) (int, error) {
+	n, what := len(e.m.Memory), "thanh ghi"
+	switch kind {
+	case IndexOutput:
+		n, what = len(e.m.Outputs), "DO"
+	case IndexTimer:
+		n, what = len(e.m.Timers), "timer"
+	}
+	if value != math.Trunc(value) {
+		return 0, runtimeError(c.Args[0].Pos(), "chỉ số %s %g của %s không phải số nguyên", what, value, c.Name)
+	}
+	if value < 0 || value >= float64(n) {
+		return 0, runtimeError(c.Args[0].Pos(), "%s %g của %s ngoài khoảng 0..%d", what, value, c.Name, n-1)
+	}
+	return int(value), nil
+}
+
+func (e *evaluator) emit(c *Call, target string, index int, value float64) {
+	e.events = append(e.events, Event{Target: target, Index: index, Value: value, Line: c.At.Line, Column: c.At.Column})
+}
+
+// Simulate chạy code một lần trên máy ảo có bộ nhớ đặt sẵn theo memory
+func Simulate(src string, memory map[int]float64, opts Options) (*Result, error) {
+	m := NewMachine(opts)
+	if err := m.Preset(memory); err != nil {
+		return nil, err
+	}
+	before := m.Clone()
+	events, err := m.Run(src)
+	if err != nil {
+		return nil, err
+	}
+	return m.Result(before, events), nil
+}
+
+// Preset đặt giá trị ban đầu cho các thanh ghi, lưu dạng float32 như set
+func (m *Machine) Preset(memory map[int]float64) error {
+	indexes := make([]int, 0, len(memory))
+	for index := range memory {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		if index < 0 || index >= len(m.Memory) {
+			return fmt.Errorf("thanh ghi %d ngoài khoảng 0..%d", index, len(m.Memory)-1)
+		}
+		m.Memory[index] = float64(float32(memory[index]))
+	}
+	return nil
+}
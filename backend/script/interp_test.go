@@ -0,0 +1,157 @@
+package script
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		memory  map[int]float64
+		changes []Change
+		events  int
+	}{
+		{
+			name:    "set",
+			src:     "set(0, 1.5);",
+			changes: []Change{{TargetMemory, 0, 0, 1.5}},
+			events:  1,
+		},
+		{
+			name:    "set làm tròn về float32",
+			src:     "set(1, 0.1);",
+			changes: []Change{{TargetMemory, 1, 0, float64(float32(0.1))}},
+			events:  1,
+		},
+		{
+			name:    "setint cắt phần thập phân",
+			src:     "setint(2, -3.7);",
+			changes: []Change{{TargetMemory, 2, 0, -3}},
+			events:  1,
+		},
+		{
+			name:    "get và getint",
+			src:     "set(6, get(5) * 2); set(7, getint(5));",
+			memory:  map[int]float64{5: 2.75},
+			changes: []Change{{TargetMemory, 6, 0, 5.5}, {TargetMemory, 7, 0, 2}},
+			events:  2,
+		},
+		{
+			name:    "câu lệnh sau thấy giá trị câu trước ghi",
+			src:     "set(0, 1);\nset(0, get(0) + 1);",
+			changes: []Change{{TargetMemory, 0, 0, 2}},
+			events:  2,
+		},
+		{
+			name:    "ba ngôi chỉ chạy một nhánh",
+			src:     "get(0) > 5 ? setdo(1, 1) : setdo(2, 1);",
+			memory:  map[int]float64{0: 10},
+			changes: []Change{{TargetOutput, 1, 0, 1}},
+			events:  1,
+		},
+		{
+			name:   "&& và || rút gọn",
+			src:    "0 && set(0, 1); 1 || set(1, 1);",
+			events: 0,
+		},
+		{
+			name:    "độ ưu tiên toán tử",
+			src:     "set(0, 2 + 3 * 4 % 5); set(1, !0 + -2); set(2, 1 < 2 == 1); set(3, (2 + 3) * 4);",
+			changes: []Change{{TargetMemory, 0, 0, 4}, {TargetMemory, 1, 0, -1}, {TargetMemory, 2, 0, 1}, {TargetMemory, 3, 0, 20}},
+			events:  4,
+		},
+		{
+			name:    "tofloat32",
+			src:     "set(0, tofloat32(16777217) == 16777216);",
+			changes: []Change{{TargetMemory, 0, 0, 1}},
+			events:  1,
+		},
+		{
+			name:    "timerstop",
+			src:     "timerstop(3);",
+			changes: []Change{{TargetTimer, 3, 1, 0}},
+			events:  1,
+		},
+		{
+			name:    "ghi lại giá trị cũ không phải thay đổi",
+			src:     "set(4, 7); setdo(0, 0);",
+			memory:  map[int]float64{4: 7},
+			changes: []Change{},
+			events:  2,
+		},
+	}
+	for _, tt := range tests {
+		result, err := Simulate(tt.src, tt.memory, DefaultOptions())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if tt.changes == nil {
+			tt.changes = []Change{}
+		}
+		if !reflect.DeepEqual(result.Changes, tt.changes) {
+			t.Errorf("%s: thay đổi = %v, cần %v", tt.name, result.Changes, tt.changes)
+		}
+		if len(result.Events) != tt.events {
+			t.Errorf("%s: %d sự kiện, cần %d", tt.name, len(result.Events), tt.events)
+		}
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		line   int
+		column int
+	}{
+		{"chia cho 0", "set(0, 1 / 0);", 1, 10},
+		{"chia lấy dư cho 0", "set(0,\n  5 % get(1));", 2, 5},
+		{"thanh ghi ngoài khoảng", "set(256, 1);", 1, 5},
+		{"chỉ số không nguyên", "get(1.5);", 1, 5},
+		{"DO ngoài khoảng", "setdo(8, 1);", 1, 7},
+		{"timer ngoài khoảng", "timerstop(-1);", 1, 11},
+		{"vượt float32", "set(0, 1e39);", 1, 1},
+		{"hàm không tồn tại", "foo(1);", 1, 1},
+		{"sai số tham số", "set(1);", 1, 1},
+		{"lỗi cú pháp", "set(0, 1;", 1, 9},
+	}
+	for _, tt := range tests {
+		_, err := Simulate(tt.src, nil, DefaultOptions())
+		var runtime *RuntimeError
+		if !errors.As(err, &runtime) {
+			t.Errorf("%s: lỗi %v, cần RuntimeError", tt.name, err)
+			continue
+		}
+		if runtime.Line != tt.line || runtime.Column != tt.column {
+			t.Errorf("%s: lỗi ở %d:%d, cần %d:%d (%v)", tt.name, runtime.Line, runtime.Column, tt.line, tt.column, err)
+		}
+	}
+}
+
+func TestRunKeepsChangesBeforeError(t *testing.T) {
+	m := NewMachine(DefaultOptions())
+	events, err := m.Run("set(0, 1);\nset(1, get(0) / get(2));\nset(3, 1);")
+	var runtime *RuntimeError
+	if !errors.As(err, &runtime) || runtime.Line != 2 {
+		t.Fatalf("lỗi %v, cần lỗi chia cho 0 ở dòng 2", err)
+	}
+	if len(events) != 1 || m.Memory[0] != 1 || m.Memory[3] != 0 {
+		t.Errorf("sự kiện %v, bộ nhớ %v: cần giữ câu lệnh 1 và dừng trước câu lệnh 3", events, m.Memory[:4])
+	}
+}
+
+func TestPreset(t *testing.T) {
+	if _, err := Simulate("", map[int]float64{256: 1}, DefaultOptions()); err == nil {
+		t.Error("đặt sẵn thanh ghi 256 không báo lỗi")
+	}
+	m := NewMachine(DefaultOptions())
+	if err := m.Preset(map[int]float64{0: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Memory[0] != float64(float32(0.1)) {
+		t.Errorf("thanh ghi 0 = %v, cần lưu dạng float32", m.Memory[0])
+	}
+}
@@ -0,0 +1,209 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Pos là vị trí trong mã nguồn, dòng và cột tính từ 1 (cột theo ký tự)
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Kind là loại token
+type Kind int
+
+const (
+	EOF Kind = iota
+	Illegal
+	Number
+	Name
+	LParen    // (
+	RParen    // )
+	Comma     // ,
+	Semicolon // ;
+	Question  // ?
+	Colon     // :
+	Plus      // +
+	Minus     // -
+	Star      // *
+	Slash     // /
+	Percent   // %
+	Lt        // <
+	Gt        // >
+	Le        // <=
+	Ge        // >=
+	Eq        // ==
+	Ne        // !=
+	And       // &&
+	Or        // ||
+	Not       // !
+	Assign    // = (chỉ dùng trong const của cú pháp có cấu trúc)
+	LBrace    // {
+	RBrace    // }
+)
+
+var kindNames = map[Kind]string{
+	EOF: "hết code", Illegal: "ký tự không hợp lệ", Number: "số", Name: "tên",
+	LParen: "'('", RParen: "')'", Comma: "','", Semicolon: "';'", Question: "'?'", Colon: "':'",
+	Plus: "'+'", Minus: "'-'", Star: "'*'", Slash: "'/'", Percent: "'%'",
+	Lt: "'<'", Gt: "'>'", Le: "'<='", Ge: "'>='", Eq: "'=='", Ne: "'!='",
+	And: "'&&'", Or: "'||'", Not: "'!'", Assign: "'='", LBrace: "'{'", RBrace: "'}'",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Token là một đơn vị từ vựng của script
+type Token struct {
+	Kind   Kind
+	Text   string
+	Pos    Pos
+	Offset int // vị trí theo ký tự (rune) trong code
+}
+
+// Comment là một comment // trong code
+type Comment struct {
+	Pos  Pos    `json:"pos"`
+	Text string `json:"text"`
+}
+
+// lexer tách code thành token, bỏ qua khoảng trắng và comment //
+type lexer struct {
+	src      []rune
+	offset   int
+	line     int
+	column   int
+	comments []Comment
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src), line: 1, column: 1}
+}
+
+func (l *lexer) peek(n int) rune {
+	if l.offset+n < len(l.src) {
+		return l.src[l.offset+n]
+	}
+	return 0
+}
+
+func (l *lexer) advance() rune {
+	r := l.src[l.offset]
+	l.offset++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+// operators là các toán tử, toán tử hai ký tự đứng trước để khớp dài nhất
+var operators = []struct {
+	text string
+	kind Kind
+}{
+	{"<=", Le}, {">=", Ge}, {"==", Eq}, {"!=", Ne}, {"&&", And}, {"||", Or},
+	{"(", LParen}, {")", RParen}, {",", Comma}, {";", Semicolon}, {"?", Question}, {":", Colon},
+	{"+", Plus}, {"-", Minus}, {"*", Star}, {"/", Slash}, {"%", Percent},
+	{"<", Lt}, {">", Gt}, {"!", Not}, {"=", Assign}, {"{", LBrace}, {"}", RBrace},
+}
+
+func (l *lexer) next() Token {
+	for l.offset < len(l.src) {
+		r := l.peek(0)
+		if unicode.IsSpace(r) {
+			l.advance()
+			continue
+		}
+		if r == '/' && l.peek(1) == '/' {
+			pos := Pos{l.line, l.column}
+			start := l.offset
+			for l.offset < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+			l.comments = append(l.comments, Comment{Pos: pos, Text: strings.TrimRight(string(l.src[start:l.offset]), "\r")})
+			continue
+		}
+		break
+	}
+
+	pos := Pos{l.line, l.column}
+	if l.offset >= len(l.src) {
+		return Token{Kind: EOF, Pos: pos, Offset: l.offset}
+	}
+
+	start := l.offset
+	r := l.peek(0)
+	switch {
+	case isDigit(r) || (r == '.' && isDigit(l.peek(1))):
+		for isDigit(l.peek(0)) {
+			l.advance()
+		}
+		if l.peek(0) == '.' && isDigit(l.peek(1)) {
+			l.advance()
+			for isDigit(l.peek(0)) {
+				l.advance()
+			}
+		}
+		if (l.peek(0) == 'e' || l.peek(0) == 'E') && (isDigit(l.peek(1)) || ((l.peek(1) == '+' || l.peek(1) == '-') && isDigit(l.peek(2)))) {
+			l.advance()
+			if l.peek(0) == '+' || l.peek(0) == '-' {
+				l.advance()
+			}
+			for isDigit(l.peek(0)) {
+				l.advance()
+			}
+		}
+		return Token{Kind: Number, Text: string(l.src[start:l.offset]), Pos: pos, Offset: start}
+
+	case r == '_' || unicode.IsLetter(r):
+		for r := l.peek(0); r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r); r = l.peek(0) {
+			l.advance()
+		}
+		return Token{Kind: Name, Text: string(l.src[start:l.offset]), Pos: pos, Offset: start}
+	}
+
+	for _, op := range operators {
+		if l.hasPrefix(op.text) {
+			for range op.text {
+				l.advance()
+			}
+			return Token{Kind: op.kind, Text: op.text, Pos: pos, Offset: start}
+		}
+	}
+	l.advance()
+	return Token{Kind: Illegal, Text: string(r), Pos: pos, Offset: start}
+}
+
+func (l *lexer) hasPrefix(s string) bool {
+	for i, r := range []rune(s) {
+		if l.peek(i) != r {
+			return false
+		}
+	}
+	return true
+}
+
+// tokens tách toàn bộ code thành token, không gồm EOF
+func tokens(src string) []Token {
+	l := newLexer(src)
+	var result []Token
+	for tok := l.next(); tok.Kind != EOF; tok = l.next() {
+		result = append(result, tok)
+	}
+	return result
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
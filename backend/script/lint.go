@@ -0,0 +1,355 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Options là giới hạn tài nguyên của datalogger dùng khi lint
+type Options struct {
+	MemorySize int `json:"memorySize"` // số thanh ghi, chỉ số hợp lệ 0..MemorySize-1
+	Outputs    int `json:"outputs"`    // số DO cho setdo, 0..Outputs-1
+	Timers     int `json:"timers"`     // số timer cho timerstop, 0..Timers-1
+}
+
+// DefaultOptions là giới hạn của firmware hiện tại
+func DefaultOptions() Options {
+	return Options{MemorySize: 256, Outputs: 8, Timers: 10}
+}
+
+// IndexKind là loại chỉ số của tham số đầu tiên trong hàm dựng sẵn
+type IndexKind int
+
+const (
+	IndexNone   IndexKind = iota
+	IndexMemory           // thanh ghi bộ nhớ
+	IndexOutput           // DO
+	IndexTimer            // timer
+)
+
+// Builtin mô tả một hàm firmware hỗ trợ trong script
+type Builtin struct {
+	Name   string
+	Args   int
+	Value  bool // trả về giá trị dùng được trong biểu thức
+	Effect bool // thay đổi trạng thái thiết bị (bộ nhớ, DO, timer)
+	Index  IndexKind
+}
+
+// Builtins là các hàm firmware hỗ trợ
+var Builtins = map[string]Builtin{
+	"get":       {Name: "get", Args: 1, Value: true, Index: IndexMemory},
+	"getint":    {Name: "getint", Args: 1, Value: true, Index: IndexMemory},
+	"set":       {Name: "set", Args: 2, Effect: true, Index: IndexMemory},
+	"setint":    {Name: "setint", Args: 2, Effect: true, Index: IndexMemory},
+	"setdo":     {Name: "setdo", Args: 2, Effect: true, Index: IndexOutput},
+	"timerstop": {Name: "timerstop", Args: 1, Effect: true, Index: IndexTimer},
+	"tofloat32": {Name: "tofloat32", Args: 1, Value: true},
+}
+
+// Lint phân tích cú pháp rồi kiểm tra code prog/timers: hàm không tồn tại, sai số tham số,
+// chỉ số bộ nhớ/DO/timer ngoài phạm vi, nhánh ba ngôi không bao giờ chạy và câu lệnh vô tác dụng.
+// Kết quả sắp theo vị trí trong code.
+func Lint(src string, opts Options) []Diagnostic {
+	prog, diags := Parse(src)
+	l := &linter{opts: opts, diags: diags}
+	for _, stmt := range prog.Stmts {
+		l.statement(stmt)
+	}
+	sort.SliceStable(l.diags, func(i, j int) bool {
+		a, b := l.diags[i], l.diags[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	if l.diags == nil {
+		return []Diagnostic{}
+	}
+	return l.diags
+}
+
+type linter struct {
+	opts  Options
+	diags []Diagnostic
+}
+
+func (l *linter) errorf(pos Pos, code, format string, args ...interface{}) {
+	l.diags = append(l.diags, newDiagnostic(pos, SeverityError, code, format, args...))
+}
+
+func (l *linter) warnf(pos Pos, code, format string, args ...interface{}) {
+	l.diags = append(l.diags, newDiagnostic(pos, SeverityWarning, code, format, args...))
+}
+
+func (l *linter) statement(stmt *Stmt) {
+	if !hasEffect(stmt.X) {
+		l.warnf(stmt.Pos(), CodeNoEffect, "câu lệnh không có tác dụng (không gọi set, setint, setdo hay timerstop)")
+	}
+	l.branch(stmt.X, facts{}, true)
+}
+
+// branch kiểm tra một biểu thức nằm ở vị trí câu lệnh hoặc nhánh của ba ngôi cấp câu lệnh,
+// nơi được phép gọi hàm không trả về giá trị. known là các điều kiện đã biết trên đường tới đây.
+func (l *linter) branch(x Expr, known facts, stmt bool) {
+	switch n := Unparen(x).(type) {
+	case *Ternary:
+		l.expr(n.Cond)
+		l.ternary(n, known, stmt)
+	case *Call:
+		l.call(n, stmt)
+	default:
+		l.expr(x)
+	}
+}
+
+// ternary báo nhánh không bao giờ chạy do điều kiện hằng hoặc mâu thuẫn với điều kiện trước
+func (l *linter) ternary(t *Ternary, known facts, stmt bool) {
+	thenOK, elseOK := true, true
+	var reason string
+
+	if value, ok := Const(t.Cond); ok {
+		if value != 0 {
+			elseOK, reason = false, fmt.Sprintf("điều kiện %s luôn đúng", t.Cond)
+		} else {
+			thenOK, reason = false, fmt.Sprintf("điều kiện %s luôn sai", t.Cond)
+		}
+	} else if truth, prev, ok := known.decide(t.Cond); ok {
+		if truth {
+			elseOK, reason = false, fmt.Sprintf("điều kiện %s luôn đúng do %s", t.Cond, prev)
+		} else {
+			thenOK, reason = false, fmt.Sprintf("điều kiện %s luôn sai do %s", t.Cond, prev)
+		}
+	}
+
+	if !thenOK {
+		l.warnf(t.Then.Pos(), CodeUnreachable, "nhánh '?' không bao giờ chạy: %s", reason)
+	}
+	if !elseOK {
+		l.warnf(t.Else.Pos(), CodeUnreachable, "nhánh ':' không bao giờ chạy: %s", reason)
+	}
+	l.branch(t.Then, known.with(t.Cond, true), stmt)
+	l.branch(t.Else, known.with(t.Cond, false), stmt)
+}
+
+// expr kiểm tra biểu thức dùng làm giá trị
+func (l *linter) expr(x Expr) {
+	switch n := x.(type) {
+	case *Call:
+		l.call(n, false)
+	case *Ternary:
+		l.expr(n.Cond)
+		l.ternary(n, facts{}, false)
+	case *Unary:
+		l.expr(n.X)
+	case *Binary:
+		l.expr(n.X)
+		l.expr(n.Y)
+	case *Paren:
+		l.expr(n.X)
+	case *Ident:
+		if _, ok := Builtins[n.Name]; ok {
+			l.errorf(n.At, CodeSyntax, "thiếu '(' khi gọi hàm %s", n.Name)
+		} else {
+			l.errorf(n.At, CodeSyntax, "tên %q không được định nghĩa, script không hỗ trợ biến (tên thanh ghi cần Compile theo bảng ký hiệu)", n.Name)
+		}
+	}
+}
+
+func (l *linter) call(c *Call, stmt bool) {
+	for _, arg := range c.Args {
+		l.expr(arg)
+	}
+
+	builtin, ok := Builtins[c.Name]
+	if !ok {
+		l.errorf(c.At, CodeUnknownFunction, "hàm %s không tồn tại%s", c.Name, suggest(c.Name))
+		return
+	}
+	if len(c.Args) != builtin.Args {
+		l.errorf(c.At, CodeArgumentCount, "%s cần %d tham số nhưng có %d", c.Name, builtin.Args, len(c.Args))
+		return
+	}
+	if !builtin.Value && !stmt {
+		l.warnf(c.At, CodeVoidValue, "%s không trả về giá trị nhưng được dùng trong biểu thức", c.Name)
+	}
+
+	var limit int
+	var code, what string
+	switch builtin.Index {
+	case IndexMemory:
+		limit, code, what = l.opts.MemorySize, CodeMemoryRange, "thanh ghi"
+	case IndexOutput:
+		limit, code, what = l.opts.Outputs, CodeOutputRange, "DO"
+	case IndexTimer:
+		limit, code, what = l.opts.Timers, CodeTimerRange, "timer"
+	default:
+		return
+	}
+	index, ok := Const(c.Args[0])
+	if !ok {
+		return
+	}
+	arg := c.Args[0].Pos()
+	if index != math.Trunc(index) {
+		l.errorf(arg, CodeIndexNotInteger, "chỉ số %s của %s phải là số nguyên", c.Args[0], c.Name)
+		return
+	}
+	if limit > 0 && (index < 0 || index >= float64(limit)) {
+		l.errorf(arg, code, "%s %s của %s ngoài khoảng 0..%d", what, c.Args[0], c.Name, limit-1)
+	}
+}
+
+// suggest gợi ý tên hàm gần đúng khi gõ sai
+func suggest(name string) string {
+	best, distance := "", 3
+	for candidate := range Builtins {
+		if d := editDistance(name, candidate); d < distance || (d == distance && best != "" && candidate < best) {
+			best, distance = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", có phải %s?", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// hasEffect cho biết biểu thức có gọi hàm thay đổi trạng thái hay không
+func hasEffect(x Expr) bool {
+	effect := false
+	Walk(x, func(n Node) bool {
+		if c, ok := n.(*Call); ok {
+			if b, ok := Builtins[c.Name]; !ok || b.Effect {
+				// Hàm không biết đã được báo lỗi riêng, không cảnh báo thêm
+				effect = true
+			}
+		}
+		return !effect
+	})
+	return effect
+}
+
+// Const tính giá trị biểu thức hằng (không đọc bộ nhớ), ok là false nếu không phải hằng
+func Const(x Expr) (value float64, ok bool) {
+	switch n := x.(type) {
+	case *NumberLit:
+		return n.Value, true
+	case *Paren:
+		return Const(n.X)
+	case *Unary:
+		v, ok := Const(n.X)
+		if !ok {
+			return 0, false
+		}
+		return unaryOp(n.Op, v), true
+	case *Binary:
+		a, ok := Const(n.X)
+		if !ok {
+			return 0, false
+		}
+		// && và || rút gọn như C: vế trái đã quyết định kết quả thì vế phải không cần là hằng
+		if n.Op == And && a == 0 {
+			return 0, true
+		}
+		if n.Op == Or && a != 0 {
+			return 1, true
+		}
+		b, ok := Const(n.Y)
+		if !ok {
+			return 0, false
+		}
+		return binaryOp(n.Op, a, b)
+	case *Ternary:
+		cond, ok := Const(n.Cond)
+		if !ok {
+			return 0, false
+		}
+		if cond != 0 {
+			return Const(n.Then)
+		}
+		return Const(n.Else)
+	case *Call:
+		if n.Name == "tofloat32" && len(n.Args) == 1 {
+			v, ok := Const(n.Args[0])
+			return float64(float32(v)), ok
+		}
+	}
+	return 0, false
+}
+
+func unaryOp(op Kind, v float64) float64 {
+	switch op {
+	case Minus:
+		return -v
+	case Not:
+		return boolValue(v == 0)
+	}
+	return v
+}
+
+// binaryOp tính phép toán hai ngôi; ok là false khi chia cho 0
+func binaryOp(op Kind, a, b float64) (float64, bool) {
+	switch op {
+	case Plus:
+		return a + b, true
+	case Minus:
+		return a - b, true
+	case Star:
+		return a * b, true
+	case Slash:
+		if b == 0 {
+			return 0, false
+		}
+		return a / b, true
+	case Percent:
+		if b == 0 {
+			return 0, false
+		}
+		return math.Mod(a, b), true
+	case Lt:
+		return boolValue(a < b), true
+	case Gt:
+		return boolValue(a > b), true
+	case Le:
+		return boolValue(a <= b), true
+	case Ge:
+		return boolValue(a >= b), true
+	case Eq:
+		return boolValue(a == b), true
+	case Ne:
+		return boolValue(a != b), true
+	case And:
+		return boolValue(a != 0 && b != 0), true
+	case Or:
+		return boolValue(a != 0 || b != 0), true
+	}
+	return 0, false
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,216 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// brief rút gọn chẩn đoán thành "dòng:cột mức mã" để so sánh trong bảng
+func brief(diags []Diagnostic) []string {
+	var result []string
+	for _, d := range diags {
+		result = append(result, fmt.Sprintf("%d:%d %s %s", d.Line, d.Column, d.Severity, d.Code))
+	}
+	return result
+}
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string // "dòng:cột loại text"
+	}{
+		{
+			src:  "set(0, 1.5e3);",
+			want: []string{"1:1 tên set", "1:4 '(' (", "1:5 số 0", "1:6 ',' ,", "1:8 số 1.5e3", "1:13 ')' )", "1:14 ';' ;"},
+		},
+		{
+			// Cột tính theo ký tự, chú thích không thành token
+			src:  "// nhiệt độ\r\nget(.5)>=1e-2",
+			want: []string{"2:1 tên get", "2:4 '(' (", "2:5 số .5", "2:7 ')' )", "2:8 '>=' >=", "2:10 số 1e-2"},
+		},
+		{
+			// 1e không có số mũ thì e là tên riêng
+			src:  "1e+x",
+			want: []string{"1:1 số 1", "1:2 tên e", "1:3 '+' +", "1:4 tên x"},
+		},
+		{
+			src:  "a @ b\n  # ?:",
+			want: []string{"1:1 tên a", "1:3 ký tự không hợp lệ @", "1:5 tên b", "2:3 ký tự không hợp lệ #", "2:5 '?' ?", "2:6 ':' :"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, tok := range tokens(tt.src) {
+			got = append(got, fmt.Sprintf("%s %s %s", tok.Pos, tok.Kind, tok.Text))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: token\n%s\ncần\n%s", tt.src, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestLintDiagnostics(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []string // "dòng:cột mức mã"
+		message string   // một phần thông báo của chẩn đoán đầu tiên
+	}{
+		{
+			name: "code đúng",
+			src:  "set(0, get(1) > 2 ? 1 : 0);\nsetdo(7, 1);",
+		},
+		{
+			name:    "ký tự không hợp lệ",
+			src:     "set(0, 1 @ 2);",
+			want:    []string{"1:10 error syntax"},
+			message: `cần ')' để đóng set( nhưng gặp ký tự không hợp lệ "@"`,
+		},
+		{
+			name:    "ký tự không hợp lệ đầu câu lệnh, câu sau vẫn được kiểm tra",
+			src:     "# set(0, 1);\nset(300, 1);",
+			want:    []string{"1:1 error syntax", "2:5 error memory_range"},
+			message: `cần biểu thức nhưng gặp ký tự không hợp lệ "#"`,
+		},
+		{
+			name:    "thiếu dấu chấm phẩy",
+			src:     "set(0, 1)\nset(1, 2);",
+			want:    []string{"1:10 error syntax"},
+			message: "thiếu ';' sau set(0, 1)",
+		},
+		{
+			name:    "phép gán",
+			src:     "x = 1;",
+			want:    []string{"1:3 error syntax"},
+			message: assignMessage,
+		},
+		{
+			name:    "thiếu ':'",
+			src:     "get(0) ? set(1, 1);",
+			want:    []string{"1:19 error unbalanced_ternary"},
+			message: "'?' ở dòng 1, cột 8 thiếu ':'",
+		},
+		{
+			name:    "gợi ý hàm gần đúng",
+			src:     "sett(0, 1);",
+			want:    []string{"1:1 error unknown_function"},
+			message: "hàm sett không tồn tại, có phải set?",
+		},
+		{
+			name:    "gợi ý theo thứ tự tên khi cách đều",
+			src:     "set(0, gett(1));",
+			want:    []string{"1:8 error unknown_function"},
+			message: "hàm gett không tồn tại, có phải get?",
+		},
+		{
+			name:    "không gợi ý khi quá khác",
+			src:     "print(0);",
+			want:    []string{"1:1 error unknown_function"},
+			message: "hàm print không tồn tại",
+		},
+		{
+			name: "sai số tham số và chỉ số",
+			src:  "set(1);\nsetdo(8, 1);\ntimerstop(1.5);\nset(0, set(1, 2));",
+			want: []string{"1:1 error argument_count", "2:7 error output_range", "3:11 error index_not_integer", "4:8 warning void_value"},
+		},
+		{
+			name:    "chỉ số là biểu thức hằng",
+			src:     "set(250 + 3 * 2, 1);",
+			want:    []string{"1:5 error memory_range"},
+			message: "thanh ghi 250 + 3 * 2 của set ngoài khoảng 0..255",
+		},
+		{
+			name:    "câu lệnh vô tác dụng",
+			src:     "get(0) + 1;",
+			want:    []string{"1:1 warning no_effect"},
+			message: "câu lệnh không có tác dụng",
+		},
+		{
+			name:    "điều kiện hằng",
+			src:     "1 > 2 ? set(0, 1) : set(0, 2);",
+			want:    []string{"1:9 warning unreachable"},
+			message: "nhánh '?' không bao giờ chạy: điều kiện 1 > 2 luôn sai",
+		},
+		{
+			name:    "điều kiện mâu thuẫn với nhánh trước",
+			src:     "getint(41) == 2 ? set(0, 1) : getint(41) == 2 ? set(0, 2) : set(0, 3);",
+			want:    []string{"1:49 warning unreachable"},
+			message: "nhánh '?' không bao giờ chạy: điều kiện getint(41) == 2 luôn sai do đã biết getint(41) == 2 là sai",
+		},
+		{
+			name:    "khoảng giá trị đã loại hết",
+			src:     "get(0) < 3 ? set(1, 1) : get(0) > 5 ? set(1, 2) : get(0) >= 3 ? set(1, 3) : set(1, 4);",
+			want:    []string{"1:77 warning unreachable"},
+			message: "nhánh ':' không bao giờ chạy: điều kiện get(0) >= 3 luôn đúng do đã biết get(0) < 3 là sai và get(0) > 5 là sai",
+		},
+		{
+			name:    "&& đúng thì cả hai vế đúng",
+			src:     "get(0) > 1 && get(1) == 0 ? (get(1) != 0 ? set(2, 1) : set(2, 2)) : set(2, 3);",
+			want:    []string{"1:44 warning unreachable"},
+			message: "nhánh '?' không bao giờ chạy: điều kiện get(1) != 0 luôn sai do đã biết get(1) == 0 là đúng",
+		},
+		{
+			name: "&& sai thì không biết vế nào sai",
+			src:  "get(0) > 1 && get(1) == 0 ? set(2, 1) : get(1) == 0 ? set(2, 2) : set(2, 3);",
+		},
+		{
+			name:    "|| sai thì cả hai vế sai",
+			src:     "get(0) == 1 || get(0) == 2 ? set(2, 1) : get(0) == 2 && get(3) > 0 ? set(2, 2) : set(2, 3);",
+			want:    []string{"1:70 warning unreachable"},
+			message: "điều kiện get(0) == 2 && get(3) > 0 luôn sai do đã biết get(0) == 1 là sai và get(0) == 2 là sai",
+		},
+		{
+			name: "điều kiện có tác dụng phụ không được suy luận",
+			src:  "set(0, 1) ? set(1, 1) : set(0, 1) ? set(1, 2) : set(1, 3);",
+			want: []string{"1:1 warning void_value", "1:25 warning void_value"},
+		},
+	}
+	for _, tt := range tests {
+		diags := Lint(tt.src, DefaultOptions())
+		if got := brief(diags); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: chẩn đoán %v, cần %v (%v)", tt.name, got, tt.want, diags)
+			continue
+		}
+		if tt.message != "" && !strings.Contains(diags[0].Message, tt.message) {
+			t.Errorf("%s: thông báo %q, cần chứa %q", tt.name, diags[0].Message, tt.message)
+		}
+	}
+}
+
+func TestConst(t *testing.T) {
+	tests := []struct {
+		src   string
+		value float64
+		ok    bool
+	}{
+		{src: "1 + 2 * 3", value: 7, ok: true},
+		{src: "-(4 - 6) / 4", value: 0.5, ok: true},
+		{src: "7 % 4", value: 3, ok: true},
+		{src: "!0 + !5", value: 1, ok: true},
+		{src: "2 >= 2 && 1 != 1", value: 0, ok: true},
+		{src: "1 < 2 ? 10 : 20", value: 10, ok: true},
+		{src: "0 ? get(0) : 3", value: 3, ok: true},
+		{src: "tofloat32(0.1)", value: float64(float32(0.1)), ok: true},
+		// Rút gọn như C: vế phải không cần là hằng
+		{src: "0 && get(0)", value: 0, ok: true},
+		{src: "1 || get(0)", value: 1, ok: true},
+		{src: "1 && get(0)"},
+		{src: "get(0) * 0"},
+		{src: "1 ? get(0) : 3"},
+		{src: "1 / 0"},
+		{src: "5 % (2 - 2)"},
+	}
+	for _, tt := range tests {
+		x, diags := ParseExpr(tt.src)
+		if len(diags) > 0 {
+			t.Fatalf("%q: %v", tt.src, diags)
+		}
+		value, ok := Const(x)
+		if ok != tt.ok || (ok && math.Abs(value-tt.value) > 1e-12) {
+			t.Errorf("Const(%q) = %v, %v, cần %v, %v", tt.src, value, ok, tt.value, tt.ok)
+		}
+	}
+}
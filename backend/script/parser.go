@@ -0,0 +1,296 @@
+package script
+
+import (
+	"fmt"
+)
+
+// Severity là mức độ của một chẩn đoán
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Mã chẩn đoán
+const (
+	CodeSyntax            = "syntax"
+	CodeUnbalancedTernary = "unbalanced_ternary"
+	CodeUnknownFunction   = "unknown_function"
+	CodeArgumentCount     = "argument_count"
+	CodeMemoryRange       = "memory_range"
+	CodeOutputRange       = "output_range"
+	CodeTimerRange        = "timer_range"
+	CodeIndexNotInteger   = "index_not_integer"
+	CodeVoidValue         = "void_value"
+	CodeUnreachable       = "unreachable"
+	CodeNoEffect          = "no_effect"
+	CodeUnknownSymbol     = "unknown_symbol"
+)
+
+// Diagnostic là một lỗi hoặc cảnh báo trong code, kèm dòng và cột
+type Diagnostic struct {
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("dòng %d, cột %d: %s", d.Line, d.Column, d.Message)
+}
+
+func newDiagnostic(pos Pos, severity Severity, code, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{Line: pos.Line, Column: pos.Column, Severity: severity, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// HasErrors cho biết có chẩn đoán mức error hay không
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+const assignMessage = "không hỗ trợ phép gán '=', dùng set()/setint() hoặc '==' để so sánh"
+
+// bailout dừng phân tích câu lệnh hiện tại sau lỗi cú pháp đầu tiên
+type bailout struct{}
+
+type parser struct {
+	lex   *lexer
+	tok   Token
+	prev  Token
+	diags []Diagnostic
+}
+
+// Parse phân tích code thành cây cú pháp. Câu lệnh lỗi cú pháp bị bỏ qua (mỗi câu lệnh
+// báo tối đa một lỗi) nên Program vẫn chứa các câu lệnh đúng còn lại.
+func Parse(src string) (*Program, []Diagnostic) {
+	p := &parser{lex: newLexer(src)}
+	p.next()
+
+	prog := &Program{}
+	for p.tok.Kind != EOF {
+		if stmt := p.statement(); stmt != nil {
+			prog.Stmts = append(prog.Stmts, stmt)
+		}
+	}
+	prog.Comments = p.lex.comments
+	return prog, p.diags
+}
+
+// ParseExpr phân tích một biểu thức đơn (không có ';')
+func ParseExpr(src string) (expr Expr, diags []Diagnostic) {
+	p := &parser{lex: newLexer(src)}
+	p.next()
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			expr, diags = &BadExpr{At: p.tok.Pos}, p.diags
+		}
+	}()
+	expr = p.expr()
+	if p.tok.Kind != EOF {
+		p.fail(p.tok.Pos, CodeSyntax, "thừa %s sau biểu thức", describe(p.tok))
+	}
+	return expr, p.diags
+}
+
+func (p *parser) next() {
+	p.prev = p.tok
+	p.tok = p.lex.next()
+}
+
+func (p *parser) fail(pos Pos, code, format string, args ...interface{}) {
+	p.diags = append(p.diags, newDiagnostic(pos, SeverityError, code, format, args...))
+	panic(bailout{})
+}
+
+func describe(tok Token) string {
+	switch tok.Kind {
+	case EOF:
+		return "hết code"
+	case Number, Name:
+		return fmt.Sprintf("%q", tok.Text)
+	case Illegal:
+		return fmt.Sprintf("ký tự không hợp lệ %q", tok.Text)
+	}
+	return tok.Kind.String()
+}
+
+// statement phân tích "expr ;". Khi lỗi, bỏ qua tới sau ';' kế tiếp.
+func (p *parser) statement() (stmt *Stmt) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			stmt = nil
+			p.sync()
+		}
+	}()
+
+	if p.tok.Kind == Semicolon {
+		// Câu lệnh rỗng ";" hợp lệ, bỏ qua
+		p.next()
+		return nil
+	}
+
+	x := p.expr()
+	if p.tok.Kind != Semicolon {
+		switch p.tok.Kind {
+		case Colon:
+			p.fail(p.tok.Pos, CodeUnbalancedTernary, "':' không có '?' tương ứng")
+		case Assign:
+			p.fail(p.tok.Pos, CodeSyntax, assignMessage)
+		}
+		if p.tok.Kind == EOF || p.tok.Pos.Line > p.prev.Pos.Line {
+			p.fail(afterToken(p.prev), CodeSyntax, "thiếu ';' sau %s", x)
+		}
+		p.fail(p.tok.Pos, CodeSyntax, "cần ';' nhưng gặp %s", describe(p.tok))
+	}
+	semi := p.tok.Pos
+	p.next()
+	return &Stmt{X: x, Semi: semi}
+}
+
+// afterToken là vị trí ngay sau token, dùng để báo thiếu ký tự
+func afterToken(tok Token) Pos {
+	return Pos{Line: tok.Pos.Line, Column: tok.Pos.Column + len([]rune(tok.Text))}
+}
+
+// sync bỏ qua token cho tới hết câu lệnh lỗi
+func (p *parser) sync() {
+	for p.tok.Kind != EOF {
+		kind := p.tok.Kind
+		p.next()
+		if kind == Semicolon {
+			return
+		}
+	}
+}
+
+func (p *parser) expect(kind Kind, context string) Token {
+	if p.tok.Kind != kind {
+		p.fail(p.tok.Pos, CodeSyntax, "cần %s %s nhưng gặp %s", kind, context, describe(p.tok))
+	}
+	tok := p.tok
+	p.next()
+	return tok
+}
+
+func (p *parser) expr() Expr {
+	return p.ternary()
+}
+
+// ternary: cond ? then : else, kết hợp phải như C
+func (p *parser) ternary() Expr {
+	cond := p.binary(1)
+	if p.tok.Kind != Question {
+		return cond
+	}
+	question := p.tok.Pos
+	p.next()
+	then := p.ternary()
+	if p.tok.Kind != Colon {
+		p.fail(p.tok.Pos, CodeUnbalancedTernary, "'?' ở dòng %d, cột %d thiếu ':' (gặp %s)", question.Line, question.Column, describe(p.tok))
+	}
+	colon := p.tok.Pos
+	p.next()
+	els := p.ternary()
+	return &Ternary{Cond: cond, Then: then, Else: els, Question: question, Colon: colon}
+}
+
+// precedence là độ ưu tiên của toán tử hai ngôi, 0 nếu không phải
+func precedence(kind Kind) int {
+	switch kind {
+	case Or:
+		return 1
+	case And:
+		return 2
+	case Eq, Ne:
+		return 3
+	case Lt, Gt, Le, Ge:
+		return 4
+	case Plus, Minus:
+		return 5
+	case Star, Slash, Percent:
+		return 6
+	}
+	return 0
+}
+
+func (p *parser) binary(min int) Expr {
+	x := p.unary()
+	for {
+		prec := precedence(p.tok.Kind)
+		if prec < min || prec == 0 {
+			return x
+		}
+		op := p.tok
+		p.next()
+		y := p.binary(prec + 1)
+		x = &Binary{X: x, Op: op.Kind, OpPos: op.Pos, Y: y}
+	}
+}
+
+func (p *parser) unary() Expr {
+	switch p.tok.Kind {
+	case Minus, Plus, Not:
+		op := p.tok
+		p.next()
+		return &Unary{At: op.Pos, Op: op.Kind, X: p.unary()}
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() Expr {
+	tok := p.tok
+	switch tok.Kind {
+	case Number:
+		p.next()
+		value, err := parseNumber(tok.Text)
+		if err != nil {
+			p.fail(tok.Pos, CodeSyntax, "số không hợp lệ %q", tok.Text)
+		}
+		return &NumberLit{At: tok.Pos, Text: tok.Text, Value: value}
+
+	case Name:
+		p.next()
+		if p.tok.Kind != LParen {
+			return &Ident{At: tok.Pos, Name: tok.Text}
+		}
+		p.next()
+		call := &Call{At: tok.Pos, Name: tok.Text}
+		if p.tok.Kind != RParen {
+			for {
+				call.Args = append(call.Args, p.expr())
+				if p.tok.Kind != Comma {
+					break
+				}
+				p.next()
+			}
+		}
+		p.expect(RParen, "để đóng "+tok.Text+"(")
+		return call
+
+	case LParen:
+		p.next()
+		x := p.expr()
+		p.expect(RParen, "để đóng '(' ở dòng "+fmt.Sprint(tok.Pos.Line))
+		return &Paren{At: tok.Pos, X: x}
+
+	case Colon:
+		p.fail(tok.Pos, CodeUnbalancedTernary, "':' không có '?' tương ứng")
+	case Assign:
+		p.fail(tok.Pos, CodeSyntax, assignMessage)
+	}
+	p.fail(tok.Pos, CodeSyntax, "cần biểu thức nhưng gặp %s", describe(tok))
+	return nil
+}
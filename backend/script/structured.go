@@ -0,0 +1,750 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Cú pháp có cấu trúc cho prog/timers, biên dịch về code ba ngôi của thiết bị:
+//
+//	const LOW = 4;
+//	if (get(CO_RAW) < LOW) {
+//	    setint(CO_STATUS, 1);
+//	} else if (get(CO_RAW) > 20) {
+//	    setint(CO_STATUS, 2);
+//	} else {
+//	    setint(CO_STATUS, 0);
+//	    set(CO, (get(CO_RAW) - LOW) * 125);
+//	}
+//	switch (getint(MODE)) {
+//	case 1, 3: set(CO, 0);
+//	default: set(CO, 1);
+//	}
+//
+// Code thiết bị thông thường cũng là code có cấu trúc hợp lệ.
+
+// CodeVerify là mã lỗi khi code biên dịch chạy khác code nguồn
+const CodeVerify = "verify_mismatch"
+
+// verifySamples là số trạng thái bộ nhớ dùng để chạy so sánh code nguồn với code biên dịch
+const verifySamples = 256
+
+// keywords là các từ khóa của cú pháp có cấu trúc, không dùng làm tên thanh ghi hay hằng
+var keywords = map[string]bool{
+	"if": true, "else": true, "switch": true, "case": true, "default": true, "const": true, "break": true,
+}
+
+// StructuredResult là code ba ngôi sau khi biên dịch, kèm lỗi/cảnh báo theo vị trí trong code nguồn
+type StructuredResult struct {
+	Code        string       `json:"code"` // rỗng khi có lỗi
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Verified    int          `json:"verified"` // số trạng thái bộ nhớ đã chạy thử cho cùng kết quả
+}
+
+// structStmt là một câu lệnh có cấu trúc: biểu thức (x khác nil) hoặc chuỗi if/else if/else.
+// switch được đổi thành chuỗi if khi phân tích.
+type structStmt struct {
+	at     Pos
+	x      Expr
+	conds  []Expr
+	bodies [][]*structStmt
+	els    []*structStmt
+}
+
+// topLevel là một câu lệnh cấp ngoài cùng, dùng để giữ comment giữa các câu lệnh
+type topLevel struct {
+	start, end int // dòng đầu và dòng cuối
+	stmts      []*structStmt
+}
+
+type structParser struct {
+	*parser
+	table  *SymbolTable
+	consts map[string]float64
+}
+
+// CompileStructured biên dịch code có cấu trúc (if/else if/else, switch, const) thành code ba ngôi
+// của thiết bị. Tên trong biểu thức là hằng const hoặc tên trong bảng ký hiệu (table có thể nil).
+// Code biên dịch được chạy thử trên máy ảo cùng với code nguồn và phải cho cùng kết quả.
+func CompileStructured(src string, table *SymbolTable, opts Options) *StructuredResult {
+	result := &StructuredResult{Diagnostics: []Diagnostic{}}
+	defer func() {
+		sort.SliceStable(result.Diagnostics, func(i, j int) bool {
+			a, b := result.Diagnostics[i], result.Diagnostics[j]
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Column < b.Column
+		})
+	}()
+
+	p := &structParser{parser: &parser{lex: newLexer(src)}, table: table, consts: make(map[string]float64)}
+	tops := p.program()
+	result.Diagnostics = append(result.Diagnostics, p.diags...)
+	if HasErrors(result.Diagnostics) {
+		return result
+	}
+
+	l := &linter{opts: opts}
+	for _, top := range tops {
+		l.structured(top.stmts)
+	}
+	result.Diagnostics = append(result.Diagnostics, l.diags...)
+	if HasErrors(result.Diagnostics) {
+		return result
+	}
+
+	c := &lowerer{}
+	code := c.program(tops, p.lex.comments)
+	result.Diagnostics = append(result.Diagnostics, c.diags...)
+	if HasErrors(result.Diagnostics) {
+		return result
+	}
+
+	var source []*structStmt
+	for _, top := range tops {
+		source = append(source, top.stmts...)
+	}
+	verified, diag := verifyStructured(source, code, opts)
+	if diag != nil {
+		result.Diagnostics = append(result.Diagnostics, *diag)
+		return result
+	}
+	result.Code, result.Verified = code, verified
+	return result
+}
+
+// program phân tích toàn bộ code; cú pháp có khối lồng nhau nên dừng ở lỗi cú pháp đầu tiên
+func (p *structParser) program() (tops []topLevel) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+		}
+	}()
+	p.next()
+	for p.tok.Kind != EOF {
+		start := p.tok.Pos.Line
+		stmts := p.statement()
+		tops = append(tops, topLevel{start: start, end: p.prev.Pos.Line, stmts: stmts})
+	}
+	return tops
+}
+
+func (p *structParser) keyword(word string) bool {
+	return p.tok.Kind == Name && p.tok.Text == word
+}
+
+func (p *structParser) errorf(pos Pos, code, format string, args ...interface{}) {
+	p.diags = append(p.diags, newDiagnostic(pos, SeverityError, code, format, args...))
+}
+
+// statement phân tích một câu lệnh; const không sinh câu lệnh, khối { } trả về các câu lệnh bên trong
+func (p *structParser) statement() []*structStmt {
+	switch {
+	case p.tok.Kind == Semicolon:
+		p.next()
+		return nil
+	case p.tok.Kind == LBrace:
+		return p.block()
+	case p.keyword("const"):
+		p.constDecl()
+		return nil
+	case p.keyword("if"):
+		return []*structStmt{p.ifStmt()}
+	case p.keyword("switch"):
+		return []*structStmt{p.switchStmt()}
+	case p.keyword("else"):
+		p.fail(p.tok.Pos, CodeSyntax, "else không có if tương ứng")
+	case p.keyword("case"), p.keyword("default"), p.keyword("break"):
+		p.fail(p.tok.Pos, CodeSyntax, "%s chỉ dùng trong switch", p.tok.Text)
+	case p.tok.Kind == RBrace:
+		p.fail(p.tok.Pos, CodeSyntax, "'}' không có '{' tương ứng")
+	}
+
+	at := p.tok.Pos
+	x := p.value()
+	if p.tok.Kind != Semicolon {
+		switch p.tok.Kind {
+		case Colon:
+			p.fail(p.tok.Pos, CodeUnbalancedTernary, "':' không có '?' tương ứng")
+		case Assign:
+			p.fail(p.tok.Pos, CodeSyntax, assignMessage)
+		}
+		if p.tok.Kind == EOF || p.tok.Pos.Line > p.prev.Pos.Line {
+			p.fail(afterToken(p.prev), CodeSyntax, "thiếu ';' sau %s", x)
+		}
+		p.fail(p.tok.Pos, CodeSyntax, "cần ';' nhưng gặp %s", describe(p.tok))
+	}
+	p.next()
+	return []*structStmt{{at: at, x: x}}
+}
+
+// block phân tích { câu lệnh... }
+func (p *structParser) block() []*structStmt {
+	open := p.expect(LBrace, "để mở khối")
+	var stmts []*structStmt
+	for p.tok.Kind != RBrace {
+		if p.tok.Kind == EOF {
+			p.fail(p.tok.Pos, CodeSyntax, "thiếu '}' để đóng '{' ở dòng %d", open.Pos.Line)
+		}
+		stmts = append(stmts, p.statement()...)
+	}
+	p.next()
+	return stmts
+}
+
+// body là thân của if/else: một khối { } hoặc một câu lệnh
+func (p *structParser) body() []*structStmt {
+	if p.tok.Kind == LBrace {
+		return p.block()
+	}
+	return p.statement()
+}
+
+// condition phân tích "( expr )" sau if/switch. Điều kiện có thể được tính nhiều lần
+// trong code biên dịch nên không được gọi hàm thay đổi trạng thái.
+func (p *structParser) condition(what string) Expr {
+	p.expect(LParen, "sau "+what)
+	x := p.value()
+	p.expect(RParen, "để đóng điều kiện của "+what)
+	if c := effectCall(x); c != nil {
+		p.errorf(c.At, CodeSyntax, "điều kiện của %s không được gọi %s", what, c.Name)
+	}
+	return x
+}
+
+// value phân tích một biểu thức và thay tên bằng giá trị
+func (p *structParser) value() Expr {
+	return p.resolve(p.expr())
+}
+
+// resolve thay tên hằng const và tên thanh ghi trong bảng ký hiệu bằng số
+func (p *structParser) resolve(x Expr) Expr {
+	switch n := x.(type) {
+	case *Ident:
+		if v, ok := p.consts[n.Name]; ok {
+			return number(n.At, v)
+		}
+		if index, ok := p.table.Lookup(n.Name); ok {
+			return number(n.At, float64(index))
+		}
+		switch {
+		case keywords[n.Name]:
+			p.errorf(n.At, CodeSyntax, "%s là từ khóa, không dùng trong biểu thức", n.Name)
+		case Builtins[n.Name].Name != "":
+			p.errorf(n.At, CodeSyntax, "thiếu '(' khi gọi hàm %s", n.Name)
+		default:
+			p.errorf(n.At, CodeUnknownSymbol, "tên %q không phải hằng const hay tên trong bảng ký hiệu", n.Name)
+		}
+	case *Call:
+		for i, arg := range n.Args {
+			n.Args[i] = p.resolve(arg)
+		}
+	case *Unary:
+		n.X = p.resolve(n.X)
+	case *Binary:
+		n.X, n.Y = p.resolve(n.X), p.resolve(n.Y)
+	case *Ternary:
+		n.Cond, n.Then, n.Else = p.resolve(n.Cond), p.resolve(n.Then), p.resolve(n.Else)
+	case *Paren:
+		n.X = p.resolve(n.X)
+	}
+	return x
+}
+
+// number tạo hằng số trong code biên dịch, số âm được đặt trong ngoặc
+func number(at Pos, v float64) Expr {
+	if v < 0 || (v == 0 && math.Signbit(v)) {
+		return &Paren{At: at, X: &Unary{At: at, Op: Minus, X: number(at, -v)}}
+	}
+	return &NumberLit{At: at, Text: strconv.FormatFloat(v, 'g', -1, 64), Value: v}
+}
+
+// constDecl phân tích "const NAME = expr;", hằng có hiệu lực từ chỗ khai báo tới hết code
+func (p *structParser) constDecl() {
+	p.next()
+	name := p.expect(Name, "sau const")
+	switch {
+	case keywords[name.Text]:
+		p.errorf(name.Pos, CodeSyntax, "%s là từ khóa, không dùng làm tên hằng", name.Text)
+	case Builtins[name.Text].Name != "":
+		p.errorf(name.Pos, CodeSyntax, "tên hằng %s trùng với hàm dựng sẵn", name.Text)
+	}
+	if _, ok := p.consts[name.Text]; ok {
+		p.errorf(name.Pos, CodeSyntax, "hằng %s đã được khai báo", name.Text)
+	}
+	if _, ok := p.table.Lookup(name.Text); ok {
+		p.errorf(name.Pos, CodeSyntax, "tên hằng %s trùng với tên trong bảng ký hiệu", name.Text)
+	}
+	p.expect(Assign, "sau tên hằng "+name.Text)
+	x := p.value()
+	v, ok := Const(x)
+	if !ok {
+		p.fail(x.Pos(), CodeSyntax, "giá trị của hằng %s phải tính được khi biên dịch (không đọc bộ nhớ)", name.Text)
+	}
+	p.expect(Semicolon, "sau khai báo const")
+	p.consts[name.Text] = v
+}
+
+// ifStmt phân tích if (c) ... else if (c) ... else ...
+func (p *structParser) ifStmt() *structStmt {
+	s := &structStmt{at: p.tok.Pos}
+	p.next()
+	for {
+		s.conds = append(s.conds, p.condition("if"))
+		s.bodies = append(s.bodies, p.body())
+		if !p.keyword("else") {
+			return s
+		}
+		p.next()
+		if !p.keyword("if") {
+			s.els = p.body()
+			return s
+		}
+		p.next()
+	}
+}
+
+// switchStmt đổi switch (x) { case a, b: ... default: ... } thành if (x == a || x == b) ... else ...
+// Mỗi case là một khối riêng, không rơi xuống case sau; break ở cuối case được bỏ qua.
+func (p *structParser) switchStmt() *structStmt {
+	s := &structStmt{at: p.tok.Pos}
+	p.next()
+	subject := p.condition("switch")
+	switch subject.(type) {
+	case *Binary, *Ternary:
+		subject = &Paren{At: subject.Pos(), X: subject}
+	}
+	p.expect(LBrace, "để mở switch")
+
+	seen := make(map[float64]bool)
+	hasDefault := false
+	for p.tok.Kind != RBrace {
+		switch {
+		case p.keyword("case"):
+			if hasDefault {
+				p.fail(p.tok.Pos, CodeSyntax, "default phải là nhánh cuối của switch")
+			}
+			p.next()
+			var cond Expr
+			for {
+				x := p.value()
+				v, ok := Const(x)
+				if !ok {
+					p.fail(x.Pos(), CodeSyntax, "giá trị case phải là hằng số, gặp %s", x)
+				}
+				if seen[v] {
+					p.errorf(x.Pos(), CodeSyntax, "case %s bị trùng", FormatValue(v))
+				}
+				seen[v] = true
+				eq := &Binary{X: subject, Op: Eq, OpPos: x.Pos(), Y: number(x.Pos(), v)}
+				if cond == nil {
+					cond = eq
+				} else {
+					cond = &Binary{X: cond, Op: Or, OpPos: x.Pos(), Y: eq}
+				}
+				if p.tok.Kind != Comma {
+					break
+				}
+				p.next()
+			}
+			p.expect(Colon, "sau giá trị case")
+			s.conds = append(s.conds, cond)
+			s.bodies = append(s.bodies, p.caseBody())
+		case p.keyword("default"):
+			if hasDefault {
+				p.fail(p.tok.Pos, CodeSyntax, "switch có nhiều hơn một default")
+			}
+			hasDefault = true
+			p.next()
+			p.expect(Colon, "sau default")
+			s.els = p.caseBody()
+		case p.tok.Kind == EOF:
+			p.fail(p.tok.Pos, CodeSyntax, "thiếu '}' để đóng switch ở dòng %d", s.at.Line)
+		default:
+			p.fail(p.tok.Pos, CodeSyntax, "cần case hoặc default trong switch nhưng gặp %s", describe(p.tok))
+		}
+	}
+	p.next()
+	return s
+}
+
+// caseBody phân tích các câu lệnh của một case tới case/default/'}' kế tiếp
+func (p *structParser) caseBody() []*structStmt {
+	var stmts []*structStmt
+	for !p.keyword("case") && !p.keyword("default") && p.tok.Kind != RBrace && p.tok.Kind != EOF {
+		if p.keyword("break") {
+			p.next()
+			p.expect(Semicolon, "sau break")
+			if !p.keyword("case") && !p.keyword("default") && p.tok.Kind != RBrace {
+				p.fail(p.tok.Pos, CodeSyntax, "break chỉ được đặt ở cuối case")
+			}
+			break
+		}
+		stmts = append(stmts, p.statement()...)
+	}
+	return stmts
+}
+
+// effectCall trả về lời gọi hàm thay đổi trạng thái đầu tiên trong biểu thức
+func effectCall(x Expr) *Call {
+	var found *Call
+	Walk(x, func(n Node) bool {
+		if c, ok := n.(*Call); ok && Builtins[c.Name].Effect {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+// structured lint các biểu thức của code có cấu trúc theo vị trí trong code nguồn
+func (l *linter) structured(stmts []*structStmt) {
+	for _, s := range stmts {
+		if s.x != nil {
+			l.statement(&Stmt{X: s.x})
+			continue
+		}
+		for i, cond := range s.conds {
+			l.expr(cond)
+			l.structured(s.bodies[i])
+		}
+		l.structured(s.els)
+	}
+}
+
+// lowerer đổi câu lệnh có cấu trúc thành câu lệnh ba ngôi
+type lowerer struct {
+	diags []Diagnostic
+}
+
+// program sinh code thiết bị, giữ comment nằm giữa các câu lệnh cấp ngoài cùng
+func (c *lowerer) program(tops []topLevel, comments []Comment) string {
+	var lines []string
+	next := 0
+	prevEnd := 0
+	emitted := -1 // dòng cuối của câu lệnh biểu thức vừa sinh, để gắn comment cùng dòng
+	for _, top := range tops {
+		for ; next < len(comments) && comments[next].Pos.Line < top.start; next++ {
+			switch line := comments[next].Pos.Line; {
+			case line == prevEnd && emitted >= 0:
+				lines[emitted] += " " + comments[next].Text
+			case line > prevEnd:
+				lines = append(lines, comments[next].Text)
+			}
+			emitted = -1
+		}
+		exprs := c.block(top.stmts)
+		for _, x := range exprs {
+			lines = append(lines, x.String()+";")
+		}
+		emitted = -1
+		if len(exprs) == 1 && top.start == top.end {
+			emitted = len(lines) - 1
+		}
+		prevEnd = top.end
+	}
+	for ; next < len(comments); next++ {
+		switch line := comments[next].Pos.Line; {
+		case line == prevEnd && emitted >= 0:
+			lines[emitted] += " " + comments[next].Text
+		case line > prevEnd:
+			lines = append(lines, comments[next].Text)
+		}
+		emitted = -1
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *lowerer) block(stmts []*structStmt) []Expr {
+	var out []Expr
+	for _, s := range stmts {
+		out = append(out, c.stmt(s)...)
+	}
+	return out
+}
+
+// stmt đổi một chuỗi if thành các câu lệnh ba ngôi. Khi các nhánh có nhiều câu lệnh, câu lệnh thứ j
+// của mọi nhánh được gộp thành một ba ngôi (nhánh ngắn hơn được căn về cuối) và điều kiện được
+// tính lại cho từng câu lệnh.
+func (c *lowerer) stmt(s *structStmt) []Expr {
+	if s.x != nil {
+		return []Expr{s.x}
+	}
+	bodies := make([][]Expr, len(s.conds))
+	n := 0
+	for i, body := range s.bodies {
+		bodies[i] = c.block(body)
+		n = max(n, len(bodies[i]))
+	}
+	els := c.block(s.els)
+	n = max(n, len(els))
+	if len(s.conds) == 0 {
+		return els
+	}
+	if n > 1 {
+		c.checkRepeated(s, append(bodies, els), n)
+	}
+
+	var out []Expr
+	for j := 0; j < n; j++ {
+		acc := slot(els, j, n)
+		for i := len(s.conds) - 1; i >= 0; i-- {
+			then := slot(bodies[i], j, n)
+			if then == nil && acc == nil {
+				continue
+			}
+			t := &Ternary{Cond: s.conds[i], Then: zeroIfNil(then), Else: zeroIfNil(acc), Question: s.at, Colon: s.at}
+			if _, ok := t.Cond.(*Ternary); ok {
+				t.Cond = &Paren{At: t.Cond.Pos(), X: t.Cond}
+			}
+			if _, ok := t.Then.(*Ternary); ok {
+				t.Then = &Paren{At: t.Then.Pos(), X: t.Then}
+			}
+			acc = t
+		}
+		if acc != nil {
+			out = append(out, acc)
+		}
+	}
+	return out
+}
+
+// slot là câu lệnh thứ j khi căn các nhánh về cuối, nil nếu nhánh không có câu lệnh ở vị trí đó
+func slot(body []Expr, j, n int) Expr {
+	k := j - (n - len(body))
+	if k < 0 {
+		return nil
+	}
+	return body[k]
+}
+
+func zeroIfNil(x Expr) Expr {
+	if x == nil {
+		return &NumberLit{Text: "0"}
+	}
+	return x
+}
+
+// checkRepeated báo lỗi khi một câu lệnh không nằm cuối nhánh ghi thanh ghi mà điều kiện đọc:
+// điều kiện được tính lại cho câu lệnh sau nên có thể chọn nhánh khác với code nguồn
+func (c *lowerer) checkRepeated(s *structStmt, branches [][]Expr, n int) {
+	reads := make(map[float64]bool)
+	dynamic := false
+	for _, cond := range s.conds {
+		Walk(cond, func(node Node) bool {
+			if call, ok := node.(*Call); ok && (call.Name == "get" || call.Name == "getint") && len(call.Args) == 1 {
+				if index, ok := Const(call.Args[0]); ok {
+					reads[index] = true
+				} else {
+					dynamic = true
+				}
+			}
+			return true
+		})
+	}
+	if len(reads) == 0 && !dynamic {
+		return
+	}
+	for _, body := range branches {
+		for j := 0; j < n-1; j++ {
+			x := slot(body, j, n)
+			if x == nil {
+				continue
+			}
+			Walk(x, func(node Node) bool {
+				call, ok := node.(*Call)
+				if !ok || (call.Name != "set" && call.Name != "setint") || len(call.Args) != 2 {
+					return true
+				}
+				index, ok := Const(call.Args[0])
+				switch {
+				case ok && reads[index]:
+					c.diags = append(c.diags, newDiagnostic(call.At, SeverityError, CodeSyntax,
+						"%s ghi thanh ghi %s mà điều kiện ở dòng %d đọc; điều kiện được tính lại cho từng câu lệnh của khối nên lệnh ghi này phải đặt cuối khối",
+						call.Name, FormatValue(index), s.at.Line))
+				case !ok || dynamic:
+					c.diags = append(c.diags, newDiagnostic(call.At, SeverityError, CodeSyntax,
+						"không xác định được %s có ghi thanh ghi mà điều kiện ở dòng %d đọc hay không; đặt lệnh ghi này ở cuối khối",
+						call.Name, s.at.Line))
+				}
+				return true
+			})
+		}
+	}
+}
+
+// exec chạy code có cấu trúc trực tiếp, làm chuẩn để so sánh với code biên dịch
+func (e *evaluator) exec(stmts []*structStmt) error {
+	for _, s := range stmts {
+		if s.x != nil {
+			if _, err := e.eval(s.x); err != nil {
+				return err
+			}
+			continue
+		}
+		body := s.els
+		for i, cond := range s.conds {
+			v, err := e.eval(cond)
+			if err != nil {
+				return err
+			}
+			if v != 0 {
+				body = s.bodies[i]
+				break
+			}
+		}
+		if err := e.exec(body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyStructured chạy code nguồn và code biên dịch trên cùng các trạng thái bộ nhớ: mọi thanh ghi
+// được đọc nhận giá trị quanh các hằng so sánh trong code. Trả về số trạng thái đã chạy.
+func verifyStructured(source []*structStmt, code string, opts Options) (int, *Diagnostic) {
+	compiled, diags := Parse(code)
+	if HasErrors(diags) {
+		d := newDiagnostic(Pos{Line: 1, Column: 1}, SeverityError, CodeVerify, "code biên dịch bị lỗi cú pháp: %s", diags[0])
+		return 0, &d
+	}
+
+	reads := make(map[int]bool)
+	values := map[float64]bool{0: true, 1: true, -1: true}
+	var visit func(stmts []*structStmt)
+	inspect := func(x Expr) {
+		Walk(x, func(node Node) bool {
+			switch n := node.(type) {
+			case *Call:
+				if Builtins[n.Name].Index == IndexMemory && len(n.Args) > 0 {
+					if index, ok := Const(n.Args[0]); ok && index == math.Trunc(index) && index >= 0 && index < float64(opts.MemorySize) {
+						reads[int(index)] = true
+					}
+				}
+			case *Binary:
+				if !isComparison(n.Op) {
+					break
+				}
+				for _, side := range []Expr{n.X, n.Y} {
+					if v, ok := Const(side); ok {
+						for _, d := range []float64{0, -1, 1, -0.5, 0.5} {
+							values[v+d] = true
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	visit = func(stmts []*structStmt) {
+		for _, s := range stmts {
+			if s.x != nil {
+				inspect(s.x)
+				continue
+			}
+			for i, cond := range s.conds {
+				inspect(cond)
+				visit(s.bodies[i])
+			}
+			visit(s.els)
+		}
+	}
+	visit(source)
+
+	indexes := sortedInts(reads)
+	candidates := make([]float64, 0, len(values))
+	for v := range values {
+		candidates = append(candidates, v)
+	}
+	sort.Float64s(candidates)
+
+	samples := verifySamples
+	if len(indexes) == 0 {
+		samples = 1
+	}
+	rng := rand.New(rand.NewSource(1))
+	for k := 0; k < samples; k++ {
+		memory := make(map[int]float64, len(indexes))
+		for _, index := range indexes {
+			memory[index] = 0
+			if k > 0 {
+				memory[index] = candidates[rng.Intn(len(candidates))]
+			}
+		}
+		want := NewMachine(opts)
+		if err := want.Preset(memory); err != nil {
+			d := newDiagnostic(Pos{Line: 1, Column: 1}, SeverityError, CodeVerify, "%s", err)
+			return k, &d
+		}
+		got := want.Clone()
+		wantErr := (&evaluator{m: want}).exec(source)
+		_, gotErr := got.Exec(compiled)
+
+		var diff string
+		switch {
+		case (wantErr == nil) != (gotErr == nil):
+			diff = fmt.Sprintf("code nguồn: %v, code biên dịch: %v", errorText(wantErr), errorText(gotErr))
+		case wantErr == nil:
+			diff = stateDiff(want, got)
+		}
+		if diff != "" {
+			d := newDiagnostic(Pos{Line: 1, Column: 1}, SeverityError, CodeVerify, "code biên dịch chạy khác code nguồn với bộ nhớ %s: %s", formatMemory(indexes, memory), diff)
+			return k, &d
+		}
+	}
+	return samples, nil
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return "chạy xong"
+	}
+	return "lỗi " + err.Error()
+}
+
+// stateDiff mô tả khác biệt đầu tiên giữa trạng thái của code nguồn (want) và code biên dịch (got)
+func stateDiff(want, got *Machine) string {
+	for i := range want.Memory {
+		if want.Memory[i] != got.Memory[i] {
+			return fmt.Sprintf("thanh ghi %d: nguồn %s, biên dịch %s", i, FormatValue(want.Memory[i]), FormatValue(got.Memory[i]))
+		}
+	}
+	for i := range want.Outputs {
+		if want.Outputs[i] != got.Outputs[i] {
+			return fmt.Sprintf("DO %d: nguồn %v, biên dịch %v", i, want.Outputs[i], got.Outputs[i])
+		}
+	}
+	for i := range want.Timers {
+		if want.Timers[i] != got.Timers[i] {
+			return fmt.Sprintf("timer %d: nguồn %v, biên dịch %v", i, want.Timers[i], got.Timers[i])
+		}
+	}
+	return ""
+}
+
+func formatMemory(indexes []int, memory map[int]float64) string {
+	if len(indexes) == 0 {
+		return "bằng 0"
+	}
+	parts := make([]string, len(indexes))
+	for i, index := range indexes {
+		parts[i] = fmt.Sprintf("%d=%s", index, FormatValue(memory[index]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func sortedInts(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+)
+
+// coTable là bảng ký hiệu của ví dụ trong README
+var coTable = &SymbolTable{Symbols: []Symbol{
+	{Name: "CO_RAW", Index: 10},
+	{Name: "CO", Index: 11},
+	{Name: "MODE", Index: 12},
+	{Name: "CO_STATUS", Index: 216},
+}}
+
+const readmeExample = `const LOW = 4;
+if (get(CO_RAW) < 3.5) {
+    setint(CO_STATUS, 2);
+} else {
+    set(CO, (get(CO_RAW) - LOW) * 4000 / 16);
+    setint(CO_STATUS, 0);
+}
+switch (getint(MODE)) {
+case 1, 3: setint(CO_STATUS, 2);
+default: setint(CO_STATUS, 0);
+}`
+
+func TestCompileStructured(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		code string
+	}{
+		{
+			name: "ví dụ README",
+			src:  readmeExample,
+			code: "get(10) < 3.5 ? 0 : set(11, (get(10) - 4) * 4000 / 16);\n" +
+				"get(10) < 3.5 ? setint(216, 2) : setint(216, 0);\n" +
+				"getint(12) == 1 || getint(12) == 3 ? setint(216, 2) : setint(216, 0);",
+		},
+		{
+			name: "else if",
+			src: `const LOW = 4;
+if (get(CO_RAW) < LOW) {
+    setint(CO_STATUS, 1);
+} else if (get(CO_RAW) > 20) {
+    setint(CO_STATUS, 2);
+} else {
+    setint(CO_STATUS, 0);
+    set(CO, (get(CO_RAW) - LOW) * 125);
+}`,
+			code: "get(10) < 4 ? 0 : get(10) > 20 ? 0 : setint(216, 0);\n" +
+				"get(10) < 4 ? setint(216, 1) : get(10) > 20 ? setint(216, 2) : set(11, (get(10) - 4) * 125);",
+		},
+		{
+			name: "if không có else và switch có break",
+			src:  "if (get(0) > 1) set(1, 1);\nswitch (get(2)) { case 1: set(3, 1); break; case 2: { set(3, 2); } }",
+			code: "get(0) > 1 ? set(1, 1) : 0;\nget(2) == 1 ? set(3, 1) : get(2) == 2 ? set(3, 2) : 0;",
+		},
+		{
+			name: "code thiết bị giữ nguyên",
+			src:  "get(0) > 1 ? set(1, 1) : 0;",
+			code: "get(0) > 1 ? set(1, 1) : 0;",
+		},
+	}
+	for _, tt := range tests {
+		result := CompileStructured(tt.src, coTable, DefaultOptions())
+		if HasErrors(result.Diagnostics) {
+			t.Fatalf("%s: %v", tt.name, result.Diagnostics)
+		}
+		if result.Code != tt.code {
+			t.Errorf("%s: code =\n%s\ncần\n%s", tt.name, result.Code, tt.code)
+		}
+		if result.Verified != verifySamples {
+			t.Errorf("%s: chạy thử %d trạng thái, cần %d", tt.name, result.Verified, verifySamples)
+		}
+	}
+}
+
+func TestCompileStructuredRuns(t *testing.T) {
+	// Chạy code biên dịch của README trên máy ảo như firmware
+	code := CompileStructured(readmeExample, coTable, DefaultOptions()).Code
+	tests := []struct {
+		raw, mode float64
+		co        float64
+		status    float64
+	}{
+		{raw: 2, mode: 0, co: 0, status: 0},
+		{raw: 2, mode: 3, co: 0, status: 2},
+		{raw: 12, mode: 0, co: 2000, status: 0},
+		{raw: 12, mode: 1, co: 2000, status: 2},
+	}
+	for _, tt := range tests {
+		result, err := Simulate(code, map[int]float64{10: tt.raw, 12: tt.mode}, DefaultOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []float64{result.Memory[11], result.Memory[216]}
+		if want := []float64{tt.co, tt.status}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CO_RAW %g, MODE %g: CO, CO_STATUS = %v, cần %v", tt.raw, tt.mode, got, want)
+		}
+	}
+}
+
+func TestCompileStructuredErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		line   int
+		column int
+		code   string
+	}{
+		{"ghi thanh ghi điều kiện đọc chưa ở cuối khối", "if (get(0) > 1) { set(0, 0); set(1, 1); }", 1, 19, ""},
+		{"điều kiện gọi set", "if (set(0, 1)) set(1, 1);", 1, 5, ""},
+		{"else không có if", "else set(0, 1);", 1, 1, CodeSyntax},
+		{"hằng khai báo hai lần", "const A = 1; const A = 2;", 1, 20, ""},
+		{"tên không có trong bảng", "set(X, 1);", 1, 5, ""},
+		{"thiếu '}'", "if (get(0)) {\n set(1, 1);", 2, 12, CodeSyntax},
+		{"case ngoài switch", "case 1: set(0, 1);", 1, 1, CodeSyntax},
+		{"thanh ghi ngoài khoảng", "if (get(0)) set(300, 1);", 1, 17, CodeMemoryRange},
+	}
+	for _, tt := range tests {
+		result := CompileStructured(tt.src, coTable, DefaultOptions())
+		if result.Code != "" || !HasErrors(result.Diagnostics) {
+			t.Errorf("%s: không báo lỗi, code %q", tt.name, result.Code)
+			continue
+		}
+		d := result.Diagnostics[0]
+		if d.Line != tt.line || d.Column != tt.column || (tt.code != "" && d.Code != tt.code) {
+			t.Errorf("%s: %s (%s), cần dòng %d cột %d %s", tt.name, d, d.Code, tt.line, tt.column, tt.code)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Symbol là tên đặt cho một thanh ghi, ví dụ CO_STATUS = 216
+type Symbol struct {
+	Name  string `json:"name"`
+	Index int    `json:"index"`
+	Desc  string `json:"desc,omitempty"`
+}
+
+// SymbolTable là bảng tên thanh ghi của một project. Mỗi tên và mỗi thanh ghi chỉ xuất hiện
+// một lần để Compile và Decompile là hai chiều của nhau.
+type SymbolTable struct {
+	Symbols []Symbol `json:"symbols"`
+}
+
+// CompileResult là code dạng số sau khi thay tên thanh ghi, kèm lỗi tên không có trong bảng
+type CompileResult struct {
+	Code        string       `json:"code"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+var symbolName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Check kiểm tra tên hợp lệ, không trùng hàm dựng sẵn hay từ khóa, không trùng nhau và thanh ghi trong 0..memorySize-1
+func (t *SymbolTable) Check(memorySize int) error {
+	var problems []string
+	names := make(map[string]bool)
+	indexes := make(map[int]string)
+	for i, s := range t.Symbols {
+		switch {
+		case !symbolName.MatchString(s.Name):
+			problems = append(problems, fmt.Sprintf("symbols[%d]: tên %q không hợp lệ (chữ, số, '_' và không bắt đầu bằng số)", i, s.Name))
+		case Builtins[s.Name].Name != "":
+			problems = append(problems, fmt.Sprintf("symbols[%d]: tên %q trùng với hàm dựng sẵn", i, s.Name))
+		case keywords[s.Name]:
+			problems = append(problems, fmt.Sprintf("symbols[%d]: tên %q là từ khóa của cú pháp có cấu trúc", i, s.Name))
+		case names[s.Name]:
+			problems = append(problems, fmt.Sprintf("symbols[%d]: tên %q bị trùng", i, s.Name))
+		}
+		names[s.Name] = true
+
+		if s.Index < 0 || s.Index >= memorySize {
+			problems = append(problems, fmt.Sprintf("symbols[%d]: thanh ghi %d ngoài khoảng 0..%d", i, s.Index, memorySize-1))
+		} else if other, ok := indexes[s.Index]; ok {
+			problems = append(problems, fmt.Sprintf("symbols[%d]: thanh ghi %d đã có tên %s", i, s.Index, other))
+		} else {
+			indexes[s.Index] = s.Name
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("bảng ký hiệu không hợp lệ: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Lookup trả về thanh ghi của một tên
+func (t *SymbolTable) Lookup(name string) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
+	for _, s := range t.Symbols {
+		if s.Name == name {
+			return s.Index, true
+		}
+	}
+	return 0, false
+}
+
+// NameOf trả về tên của một thanh ghi
+func (t *SymbolTable) NameOf(index int) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	for _, s := range t.Symbols {
+		if s.Index == index {
+			return s.Name, true
+		}
+	}
+	return "", false
+}
+
+// edit thay đoạn [offset, offset+length) của code bằng text
+type edit struct {
+	offset, length int
+	text           string
+}
+
+func applyEdits(src string, edits []edit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].offset < edits[j].offset })
+	runes := []rune(src)
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(string(runes[last:e.offset]))
+		b.WriteString(e.text)
+		last = e.offset + e.length
+	}
+	b.WriteString(string(runes[last:]))
+	return b.String()
+}
+
+// Compile thay tên thanh ghi trong code bằng chỉ số theo bảng ký hiệu, ví dụ
+// setint(CO_STATUS, 1) thành setint(216, 1). Tên được dùng ở bất kỳ chỗ nào cần một số;
+// comment và định dạng giữ nguyên. Tên không có trong bảng được giữ lại và báo lỗi.
+func Compile(src string, table *SymbolTable) *CompileResult {
+	result := &CompileResult{Diagnostics: []Diagnostic{}}
+	toks := tokens(src)
+	var edits []edit
+	for i, tok := range toks {
+		if tok.Kind != Name || (i+1 < len(toks) && toks[i+1].Kind == LParen) {
+			continue
+		}
+		index, ok := table.Lookup(tok.Text)
+		if !ok {
+			result.Diagnostics = append(result.Diagnostics, newDiagnostic(tok.Pos, SeverityError, CodeUnknownSymbol, "tên %q không có trong bảng ký hiệu", tok.Text))
+			continue
+		}
+		edits = append(edits, edit{offset: tok.Offset, length: len([]rune(tok.Text)), text: fmt.Sprint(index)})
+	}
+	result.Code = applyEdits(src, edits)
+	return result
+}
+
+// Decompile thay chỉ số hằng của get/getint/set/setint bằng tên trong bảng ký hiệu,
+// ví dụ setint(216, 1) thành setint(CO_STATUS, 1). Số ở vị trí khác (giá trị, hằng so sánh) và chỉ số
+// viết khác dạng số nguyên thông thường (216.0, 0216, 2.16e2) giữ nguyên để Compile trả lại đúng code ban đầu.
+func Decompile(src string, table *SymbolTable) string {
+	toks := tokens(src)
+	var edits []edit
+	for i := 0; i+3 < len(toks); i++ {
+		fn, open, number, next := toks[i], toks[i+1], toks[i+2], toks[i+3]
+		if fn.Kind != Name || Builtins[fn.Text].Index != IndexMemory || open.Kind != LParen || number.Kind != Number {
+			continue
+		}
+		if next.Kind != Comma && next.Kind != RParen {
+			continue
+		}
+		index, err := strconv.Atoi(number.Text)
+		if err != nil || strconv.Itoa(index) != number.Text {
+			continue
+		}
+		if name, ok := table.NameOf(index); ok {
+			edits = append(edits, edit{offset: number.Offset, length: len([]rune(number.Text)), text: name})
+		}
+	}
+	return applyEdits(src, edits)
+}
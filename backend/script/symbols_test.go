@@ -0,0 +1,178 @@
+package script
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testSymbols = &SymbolTable{Symbols: []Symbol{
+	{Name: "CO_STATUS", Index: 216, Desc: "trạng thái CO"},
+	{Name: "CO_VALUE", Index: 16},
+	{Name: "nhiệt_độ", Index: 3},
+	{Name: "FLAG", Index: 0},
+}}
+
+func TestSymbolTableCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols []Symbol
+		want    []string // các lỗi, rỗng nếu hợp lệ
+	}{
+		{
+			name:    "hợp lệ",
+			symbols: []Symbol{{Name: "CO_STATUS", Index: 216}, {Name: "_x1", Index: 0}, {Name: "LAST", Index: 255}},
+		},
+		{
+			name:    "tên không hợp lệ",
+			symbols: []Symbol{{Name: "1ABC", Index: 1}, {Name: "A-B", Index: 2}, {Name: "", Index: 3}},
+			want: []string{
+				`symbols[0]: tên "1ABC" không hợp lệ`,
+				`symbols[1]: tên "A-B" không hợp lệ`,
+				`symbols[2]: tên "" không hợp lệ`,
+			},
+		},
+		{
+			name:    "trùng hàm dựng sẵn",
+			symbols: []Symbol{{Name: "getint", Index: 1}, {Name: "tofloat32", Index: 2}},
+			want: []string{
+				`symbols[0]: tên "getint" trùng với hàm dựng sẵn`,
+				`symbols[1]: tên "tofloat32" trùng với hàm dựng sẵn`,
+			},
+		},
+		{
+			name:    "trùng từ khóa",
+			symbols: []Symbol{{Name: "if", Index: 1}, {Name: "const", Index: 2}},
+			want: []string{
+				`symbols[0]: tên "if" là từ khóa của cú pháp có cấu trúc`,
+				`symbols[1]: tên "const" là từ khóa của cú pháp có cấu trúc`,
+			},
+		},
+		{
+			name:    "trùng tên",
+			symbols: []Symbol{{Name: "A", Index: 1}, {Name: "A", Index: 2}},
+			want:    []string{`symbols[1]: tên "A" bị trùng`},
+		},
+		{
+			name:    "trùng thanh ghi",
+			symbols: []Symbol{{Name: "A", Index: 7}, {Name: "B", Index: 7}},
+			want:    []string{"symbols[1]: thanh ghi 7 đã có tên A"},
+		},
+		{
+			name:    "thanh ghi ngoài bộ nhớ",
+			symbols: []Symbol{{Name: "A", Index: -1}, {Name: "B", Index: 256}},
+			want: []string{
+				"symbols[0]: thanh ghi -1 ngoài khoảng 0..255",
+				"symbols[1]: thanh ghi 256 ngoài khoảng 0..255",
+			},
+		},
+	}
+	for _, tt := range tests {
+		err := (&SymbolTable{Symbols: tt.symbols}).Check(256)
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: không báo lỗi", tt.name)
+			continue
+		}
+		problems := strings.Split(strings.TrimPrefix(err.Error(), "bảng ký hiệu không hợp lệ: "), "; ")
+		if len(problems) != len(tt.want) {
+			t.Errorf("%s: lỗi %q, cần %q", tt.name, problems, tt.want)
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.HasPrefix(problems[i], want) {
+				t.Errorf("%s: lỗi %q, cần %q", tt.name, problems[i], want)
+			}
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		code  string
+		diags []string // "dòng:cột mức mã"
+	}{
+		{
+			name: "tên ở mọi chỗ cần số, giữ comment và định dạng",
+			src:  "// cập nhật CO_STATUS\r\nsetint( CO_STATUS ,\tget(CO_VALUE) > 5 ? 1 : 0 );  // CO_VALUE\nset(nhiệt_độ, FLAG + 1);",
+			code: "// cập nhật CO_STATUS\r\nsetint( 216 ,\tget(16) > 5 ? 1 : 0 );  // CO_VALUE\nset(3, 0 + 1);",
+		},
+		{
+			name: "tên hàm không bị thay",
+			src:  "FLAG(0);",
+			code: "FLAG(0);",
+		},
+		{
+			name:  "tên không có trong bảng giữ nguyên và báo lỗi",
+			src:   "set(CO_STATUS, 1);\n  set(SO2, NO2);",
+			code:  "set(216, 1);\n  set(SO2, NO2);",
+			diags: []string{"2:7 error unknown_symbol", "2:12 error unknown_symbol"},
+		},
+	}
+	for _, tt := range tests {
+		result := Compile(tt.src, testSymbols)
+		if result.Code != tt.code {
+			t.Errorf("%s: code\n%s\ncần\n%s", tt.name, result.Code, tt.code)
+		}
+		if got := brief(result.Diagnostics); !reflect.DeepEqual(got, tt.diags) {
+			t.Errorf("%s: chẩn đoán %v, cần %v", tt.name, got, tt.diags)
+		}
+	}
+}
+
+func TestDecompile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "chỉ số của get/getint/set/setint",
+			src:  "setint(216, getint(16) > 5 ? 1 : 0); set(3,get(0));",
+			want: "setint(CO_STATUS, getint(CO_VALUE) > 5 ? 1 : 0); set(nhiệt_độ,get(FLAG));",
+		},
+		{
+			name: "giữ comment và định dạng",
+			src:  "// set(216, 1)\r\n\tset( 216 , 1 );   // xong\n",
+			want: "// set(216, 1)\r\n\tset( CO_STATUS , 1 );   // xong\n",
+		},
+		{
+			name: "số ở vị trí khác giữ nguyên",
+			src:  "set(1, 216); setdo(3, 1); timerstop(0); set(0 + 3, 1); get(16) == 16 ? set(1, 3) : 0;",
+			want: "set(1, 216); setdo(3, 1); timerstop(0); set(0 + 3, 1); get(CO_VALUE) == 16 ? set(1, 3) : 0;",
+		},
+		{
+			name: "thanh ghi không có tên hoặc viết khác dạng số nguyên giữ nguyên",
+			src:  "set(5, 1); set(3.5, 1); set(216.0, 1); set(1e1, 1); set(016, 1);",
+			want: "set(5, 1); set(3.5, 1); set(216.0, 1); set(1e1, 1); set(016, 1);",
+		},
+	}
+	for _, tt := range tests {
+		if got := Decompile(tt.src, testSymbols); got != tt.want {
+			t.Errorf("%s: code\n%s\ncần\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecompileCompileRoundTrip(t *testing.T) {
+	sources := []string{
+		"setint(216, getint(16) > 5 ? 1 : 0);\r\n// giữ comment 216\nset(3, get(0) + 216);",
+		"set(216.0, 1); set(0016, get(3)); set(1e1, 2);",
+		"getint(0) == 1 ? (get(3) < 2.5 ? setdo(0, 1) : timerstop(3)) : set(5, tofloat32(get(216)));",
+		"",
+	}
+	for _, src := range sources {
+		decompiled := Decompile(src, testSymbols)
+		result := Compile(decompiled, testSymbols)
+		if result.Code != src || len(result.Diagnostics) > 0 {
+			t.Errorf("Compile(Decompile(%q)) = %q %v", src, result.Code, result.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// loginFree là các lệnh không cần đăng nhập
+var loginFree = map[string]bool{
+	"login":            true,
+	"logout":           true,
+	"read_system_info": true,
+	"read_analog":      true,
+	"read_tag_view":    true,
+	"read_memory_view": true,
+	"get_rtc":          true,
+	"get_measure_mode": true,
+	"get_gps":          true,
+	"read_sim_info":    true,
+	"read_sdcard_info": true,
+	"network":          true,
+	"ping":             true,
+}
+
+// streamTypes là các lệnh bật/tắt luồng dữ liệu liên tục, thiết bị không trả lời riêng
+var streamTypes = map[string]bool{
+	"read_analog":      true,
+	"read_tag_view":    true,
+	"read_memory_view": true,
+}
+
+// reply là phản hồi gửi lại cho ứng dụng
+type reply map[string]interface{}
+
+func success(msgType string) reply {
+	return reply{"type": msgType, "status": "success"}
+}
+
+func failure(msgType, status, message string) reply {
+	return reply{"type": msgType, "status": status, "message": message}
+}
+
+// handle xử lý một dòng lệnh của session, trả về phản hồi (nil nếu lệnh không có phản hồi)
+func (s *Simulator) handle(sess *session, line string) reply {
+	var message map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		return failure("", "error", "lệnh không phải JSON hợp lệ")
+	}
+	msgType, _ := message["type"].(string)
+	if msgType == "" {
+		return failure("", "error", "thiếu trường type")
+	}
+
+	if s.options.RequireLogin && !loginFree[msgType] && !sess.loggedIn() {
+		return failure(msgType, "unauthorized", "chưa đăng nhập")
+	}
+
+	if streamTypes[msgType] {
+		mode, _ := message["data"].(string)
+		switch mode {
+		case "enable":
+			sess.setStream(msgType, true)
+		case "disable":
+			sess.setStream(msgType, false)
+		default:
+			return failure(msgType, "error", "data phải là enable hoặc disable")
+		}
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msgType {
+	case "login":
+		username, _ := message["username"].(string)
+		password, _ := message["password"].(string)
+		if expected, ok := s.users[username]; !ok || expected != password {
+			return failure(msgType, "fail", "sai tên đăng nhập hoặc mật khẩu")
+		}
+		role := "user"
+		if username == "admin" {
+			role = "admin"
+		}
+		sess.login(username)
+		result := success(msgType)
+		result["role"] = role
+		return result
+
+	case "logout":
+		sess.logout()
+		return success(msgType)
+
+	case "change_password":
+		oldPassword, _ := message["old_password"].(string)
+		newPassword, _ := message["new_password"].(string)
+		user := sess.username()
+		if s.users[user] != oldPassword {
+			return failure(msgType, "fail", "mật khẩu cũ không đúng")
+		}
+		if newPassword == "" {
+			return failure(msgType, "fail", "mật khẩu mới không được trống")
+		}
+		s.users[user] = newPassword
+		return success(msgType)
+
+	case "add_user":
+		username, _ := message["username"].(string)
+		password, _ := message["password"].(string)
+		if username == "" {
+			return failure(msgType, "fail", "tên đăng nhập không được trống")
+		}
+		if _, exists := s.users[username]; exists {
+			return failure(msgType, "fail", "tài khoản đã tồn tại")
+		}
+		s.users[username] = password
+		return success(msgType)
+
+	case "remove_user":
+		username, _ := message["username"].(string)
+		if username == "admin" {
+			return failure(msgType, "fail", "không thể xóa tài khoản admin")
+		}
+		if _, exists := s.users[username]; !exists {
+			return failure(msgType, "fail", "tài khoản không tồn tại")
+		}
+		delete(s.users, username)
+		return success(msgType)
+
+	case "download_config":
+		// Thiết bị trả về cấu hình kèm type, không có status
+		result := reply(cloneMap(s.config))
+		result["type"] = msgType
+		return result
+
+	case "upload_config":
+		delete(message, "type")
+		if len(message) == 0 {
+			return failure(msgType, "fail", "cấu hình trống")
+		}
+		s.config = message
+		return success(msgType)
+
+	case "download_section":
+		value, err := s.section(message)
+		if err != nil {
+			return failure(msgType, "fail", err.Error())
+		}
+		result := success(msgType)
+		result["section"] = message["section"]
+		if index, ok := message["index"]; ok {
+			result["index"] = index
+		}
+		result["data"] = cloneValue(value)
+		return result
+
+	case "upload_section":
+		if _, err := s.section(message); err != nil {
+			return failure(msgType, "fail", err.Error())
+		}
+		data, ok := message["data"]
+		if !ok || data == nil {
+			return failure(msgType, "fail", "thiếu data")
+		}
+		name := message["section"].(string)
+		if index, ok := message["index"].(float64); ok {
+			s.config[name].([]interface{})[int(index)] = data
+		} else {
+			s.config[name] = data
+		}
+		return success(msgType)
+
+	case "transfer_begin", "transfer_chunk", "transfer_end":
+		return s.handleTransfer(msgType, message)
+
+	case "reset_configuration":
+		s.config = cloneMap(s.seed)
+		return success(msgType)
+
+	case "network":
+		result := reply(cloneMap(s.network))
+		result["type"] = msgType
+		return result
+
+	case "network_setting":
+		for key, value := range message {
+			if key != "type" {
+				s.network[key] = value
+			}
+		}
+		return success(msgType)
+
+	case "get_rtc":
+		result := success(msgType)
+		result["ts"] = s.now().Unix()
+		return result
+
+	case "set_rtc":
+		mode, _ := message["mode"].(string)
+		switch mode {
+		case "manual":
+			ts, ok := message["ts"].(float64)
+			if !ok {
+				return failure(msgType, "fail", "thiếu ts")
+			}
+			s.rtcOffset = time.Until(time.Unix(int64(ts), 0))
+		case "internet":
+			s.rtcOffset = 0
+		default:
+			return failure(msgType, "fail", "mode phải là manual hoặc internet")
+		}
+		return success(msgType)
+
+	case "set_time":
+		return success(msgType)
+
+	case "get_measure_mode":
+		result := success(msgType)
+		result["mode"] = s.measureMode
+		return result
+
+	case "set_measure_mode":
+		mode, _ := message["mode"].(string)
+		if mode != "current" && mode != "voltage" {
+			return failure(msgType, "fail", "mode phải là current hoặc voltage")
+		}
+		s.measureMode = mode
+		return success(msgType)
+
+	case "calib_4ma", "calib_16ma", "ping", "reboot":
+		return success(msgType)
+
+	case "set_digital_output":
+		states, ok := message["data"].([]interface{})
+		if !ok || len(states) != len(s.outputs) {
+			return failure(msgType, "fail", fmt.Sprintf("data phải có đúng %d phần tử", len(s.outputs)))
+		}
+		for i, state := range states {
+			value, _ := state.(float64)
+			s.outputs[i] = 0
+			if value != 0 {
+				s.outputs[i] = 1
+			}
+		}
+		s.tick()
+		return success(msgType)
+
+	case "read_system_info":
+		result := success(msgType)
+		result["data"] = strings.Join([]string{
+			"model:DataLogger Simulator",
+			"firmware:sim-1.0",
+			"serial:" + s.options.Serial,
+			"mac:" + s.options.Mac,
+			fmt.Sprintf("uptime:%ds", int(time.Since(s.started).Seconds())),
+		}, ",")
+		return result
+
+	case "read_sim_info":
+		result := success(msgType)
+		result["data"] = "operator:SIMULATOR,signal:-67dBm,status:registered"
+		return result
+
+	case "read_sdcard_info":
+		result := success(msgType)
+		result["data"] = "total:7580MB,free:7012MB,status:mounted"
+		return result
+
+	case "get_gps":
+		result := success(msgType)
+		result["data"] = "lat:21.028511,lon:105.804817,fix:3D"
+		return result
+
+	case "write_serial_number":
+		serial, _ := message["data"].(string)
+		if serial == "" {
+			return failure(msgType, "fail", "số serial không được trống")
+		}
+		s.options.Serial = serial
+		return success(msgType)
+
+	case "write_mac":
+		mac, _ := message["data"].(string)
+		if mac == "" {
+			return failure(msgType, "fail", "địa chỉ MAC không được trống")
+		}
+		s.options.Mac = mac
+		return success(msgType)
+	}
+
+	return failure(msgType, "unsupported", "lệnh không được hỗ trợ")
+}
+
+// streamData tạo dữ liệu cho một luồng đang bật
+func (s *Simulator) streamData(msgType string) reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msgType {
+	case "read_analog":
+		return reply{"type": msgType, "data": s.analog()}
+	case "read_tag_view":
+		return reply{"type": msgType, "data": s.tagView()}
+	case "read_memory_view":
+		return reply{"type": msgType, "data": append([]float64(nil), s.memory[:]...)}
+	}
+	return nil
+}
+
+// section trả về giá trị section (hoặc một phần tử nếu có index) mà lệnh chỉ tới
+func (s *Simulator) section(message map[string]interface{}) (interface{}, error) {
+	name, _ := message["section"].(string)
+	value, ok := s.config[name]
+	if name == "" || !ok {
+		return nil, fmt.Errorf("không có section %q", name)
+	}
+	rawIndex, ok := message["index"]
+	if !ok {
+		return value, nil
+	}
+	index, ok := rawIndex.(float64)
+	list, isList := value.([]interface{})
+	if !ok || !isList || index < 0 || int(index) >= len(list) || index != float64(int(index)) {
+		return nil, fmt.Errorf("index %v không hợp lệ cho section %s", rawIndex, name)
+	}
+	return list[int(index)], nil
+}
+
+// cloneValue sao chép sâu một giá trị JSON
+func cloneValue(value interface{}) interface{} {
+	data, _ := json.Marshal(value)
+	var copied interface{}
+	json.Unmarshal(data, &copied)
+	return copied
+}
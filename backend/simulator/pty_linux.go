@@ -0,0 +1,83 @@
+package simulator
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// PTY là cặp pseudo-terminal: bộ mô phỏng dùng đầu master,
+// ứng dụng mở Path (ví dụ /dev/pts/3) như một cổng COM bình thường
+type PTY struct {
+	Path   string
+	master *os.File
+	slave  *os.File
+}
+
+// OpenPTY tạo một pseudo-terminal mới ở chế độ raw
+func OpenPTY() (*PTY, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("không thể mở /dev/ptmx: %w", err)
+	}
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("không thể mở khóa pty: %w", err)
+	}
+	number, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, fmt.Errorf("không đọc được số pty: %w", err)
+	}
+
+	path := fmt.Sprintf("/dev/pts/%d", number)
+	// Giữ đầu slave mở để master không báo EIO khi ứng dụng đóng/mở lại cổng
+	slave, err := os.OpenFile(path, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, fmt.Errorf("không thể mở %s: %w", path, err)
+	}
+	if err := makeRaw(int(slave.Fd())); err != nil {
+		slave.Close()
+		master.Close()
+		return nil, err
+	}
+
+	return &PTY{Path: path, master: master, slave: slave}, nil
+}
+
+// makeRaw tắt echo và chế độ dòng để dữ liệu đi qua nguyên vẹn
+func makeRaw(fd int) error {
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return fmt.Errorf("không đọc được termios: %w", err)
+	}
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, termios); err != nil {
+		return fmt.Errorf("không đặt được chế độ raw: %w", err)
+	}
+	return nil
+}
+
+func (p *PTY) Read(data []byte) (int, error) {
+	return p.master.Read(data)
+}
+
+func (p *PTY) Write(data []byte) (int, error) {
+	return p.master.Write(data)
+}
+
+// Close đóng cả hai đầu pty
+func (p *PTY) Close() error {
+	p.slave.Close()
+	return p.master.Close()
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package simulator
+
+import (
+	"errors"
+	"io"
+)
+
+// PTY chỉ hỗ trợ trên Linux
+type PTY struct {
+	Path string
+	io.ReadWriteCloser
+}
+
+// OpenPTY chưa hỗ trợ trên hệ điều hành này, hãy dùng TCP
+func OpenPTY() (*PTY, error) {
+	return nil, errors.New("pseudo-terminal chỉ hỗ trợ trên Linux, hãy dùng TCP")
+}
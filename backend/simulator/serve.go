@@ -0,0 +1,196 @@
+package simulator
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxLine là độ dài tối đa một dòng lệnh (upload_config có thể rất dài)
+const maxLine = 4 << 20
+
+// streamOrder là thứ tự gửi các luồng dữ liệu trong mỗi chu kỳ
+var streamOrder = []string{"read_analog", "read_tag_view", "read_memory_view"}
+
+// session là trạng thái của một kết nối tới bộ mô phỏng
+type session struct {
+	writer  io.Writer
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	user    string
+	streams map[string]bool
+}
+
+func (sess *session) loggedIn() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.user != ""
+}
+
+func (sess *session) username() string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.user
+}
+
+func (sess *session) login(user string) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.user = user
+}
+
+// logout cũng tắt mọi luồng dữ liệu như thiết bị thật
+func (sess *session) logout() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.user = ""
+	sess.streams = map[string]bool{}
+}
+
+func (sess *session) setStream(msgType string, enabled bool) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.streams[msgType] = enabled
+}
+
+func (sess *session) activeStreams() []string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	var active []string
+	for _, msgType := range streamOrder {
+		if sess.streams[msgType] {
+			active = append(active, msgType)
+		}
+	}
+	return active
+}
+
+// write gửi một phản hồi JSON kết thúc bằng xuống dòng
+func (sess *session) write(r reply) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	sess.writeMu.Lock()
+	defer sess.writeMu.Unlock()
+	_, err = sess.writer.Write(append(data, '\n'))
+	return err
+}
+
+// Serve phục vụ một kết nối (socket hoặc pty) cho tới khi kết nối bị đóng
+func (s *Simulator) Serve(conn io.ReadWriteCloser) error {
+	defer conn.Close()
+
+	sess := &session{writer: conn, streams: map[string]bool{}}
+	done := make(chan struct{})
+	defer close(done)
+	go s.stream(sess, done)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxLine)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 || line == "\r" {
+			continue
+		}
+
+		r := s.handle(sess, line)
+		if r == nil {
+			continue
+		}
+		if err := sess.write(r); err != nil {
+			return err
+		}
+		if r["type"] == "reboot" {
+			// Thiết bị khởi động lại: ngắt kết nối mạng, cổng COM thì chỉ xóa session
+			if _, ok := conn.(net.Conn); ok {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}
+			sess.logout()
+		}
+	}
+	return scanner.Err()
+}
+
+// stream gửi dữ liệu các luồng đang bật theo chu kỳ StreamPeriod
+func (s *Simulator) stream(sess *session, done chan struct{}) {
+	ticker := time.NewTicker(s.options.StreamPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		s.tick()
+		s.mu.Unlock()
+
+		for _, msgType := range sess.activeStreams() {
+			if err := sess.write(s.streamData(msgType)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ServeTCP nhận kết nối trên listener, mỗi kết nối là một session riêng
+func (s *Simulator) ServeTCP(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			log.Printf("Simulator: kết nối mới từ %s", conn.RemoteAddr())
+			if err := s.Serve(conn); err != nil {
+				log.Printf("Simulator: kết nối %s kết thúc: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// ServeDiscovery trả lời gói UDP {"type":"discover"} để ứng dụng tìm thấy bộ mô phỏng trong LAN
+func (s *Simulator) ServeDiscovery(conn net.PacketConn) error {
+	buffer := make([]byte, 2048)
+	for {
+		n, from, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		var message struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(buffer[:n], &message) != nil || message.Type != "discover" {
+			continue
+		}
+
+		s.mu.Lock()
+		data, _ := json.Marshal(reply{
+			"type": "discover",
+			"data": map[string]interface{}{
+				"serial":    s.options.Serial,
+				"mac":       s.options.Mac,
+				"conf_port": intField(s.config, "common", "conf_port"),
+			},
+		})
+		s.mu.Unlock()
+		conn.WriteTo(append(data, '\n'), from)
+	}
+}
@@ -0,0 +1,233 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemorySize là số thanh ghi trong bộ nhớ của datalogger (Reg0..Reg255)
+const MemorySize = 256
+
+// Options là tham số của bộ mô phỏng
+type Options struct {
+	Serial       string            // số serial trả về trong read_system_info
+	Mac          string            // địa chỉ MAC trả về trong read_system_info
+	Users        map[string]string // tài khoản đăng nhập, nil để dùng admin/admin
+	RequireLogin bool              // bắt đăng nhập trước các lệnh ghi và đọc cấu hình
+	StreamPeriod time.Duration     // chu kỳ gửi read_analog/read_tag_view/read_memory_view
+	ChunkLoss    float64           // tỉ lệ transfer_chunk bị bỏ qua (0..1) để thử gửi lại
+}
+
+// DefaultOptions là cấu hình mặc định của bộ mô phỏng
+func DefaultOptions() Options {
+	return Options{
+		Serial:       "SIM-000001",
+		Mac:          "02:00:00:00:00:01",
+		RequireLogin: true,
+		StreamPeriod: time.Second,
+	}
+}
+
+// Simulator mô phỏng datalogger: cùng giao thức JSON theo dòng, cấu hình
+// và bộ nhớ giữ trong RAM, khởi tạo từ file cấu hình mẫu (test.json)
+type Simulator struct {
+	mu          sync.Mutex
+	options     Options
+	seed        map[string]interface{}
+	config      map[string]interface{}
+	memory      [MemorySize]float64
+	users       map[string]string
+	outputs     [8]int
+	measureMode string
+	rtcOffset   time.Duration
+	network     map[string]interface{}
+	transfers   map[string]*transfer
+	started     time.Time
+}
+
+// New tạo bộ mô phỏng với cấu hình ban đầu seed (nội dung test.json)
+func New(seed []byte, options Options) (*Simulator, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(seed, &config); err != nil {
+		return nil, fmt.Errorf("cấu hình mẫu không phải JSON hợp lệ: %w", err)
+	}
+	if options.StreamPeriod <= 0 {
+		options.StreamPeriod = DefaultOptions().StreamPeriod
+	}
+
+	users := map[string]string{"admin": "admin"}
+	if options.Users != nil {
+		users = make(map[string]string, len(options.Users))
+		for name, password := range options.Users {
+			users[name] = password
+		}
+	}
+
+	s := &Simulator{
+		options:     options,
+		seed:        config,
+		config:      cloneMap(config),
+		users:       users,
+		measureMode: "current",
+		network: map[string]interface{}{
+			"dhcp":         false,
+			"ip":           "192.168.1.100",
+			"netmask":      "255.255.255.0",
+			"gateway":      "192.168.1.1",
+			"dns":          "8.8.8.8",
+			"proxy":        "",
+			"secondary_ip": "",
+			"global":       "",
+		},
+		transfers: make(map[string]*transfer),
+		started:   time.Now(),
+	}
+	return s, nil
+}
+
+// Memory trả về bản sao bộ nhớ hiện tại
+func (s *Simulator) Memory() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.memory[:]...)
+}
+
+// SetMemory ghi một thanh ghi (dùng khi kiểm thử)
+func (s *Simulator) SetMemory(index int, value float64) error {
+	if index < 0 || index >= MemorySize {
+		return fmt.Errorf("thanh ghi ngoài phạm vi: %d", index)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.memory[index] = value
+	return nil
+}
+
+// Config trả về bản sao cấu hình hiện tại
+func (s *Simulator) Config() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneMap(s.config)
+}
+
+// now là giờ của đồng hồ thiết bị mô phỏng
+func (s *Simulator) now() time.Time {
+	return time.Now().Add(s.rtcOffset)
+}
+
+// tick cập nhật bộ nhớ: các kênh AI dao động quanh 4-20mA, DO phản ánh set_digital_output.
+// Gọi khi đang giữ s.mu.
+func (s *Simulator) tick() {
+	elapsed := time.Since(s.started).Seconds()
+
+	aiLoc := s.aiLoc()
+	for i := range sectionList(s.config, "ais") {
+		value := 12 + 6*math.Sin(elapsed/10+float64(i))
+		if s.measureMode == "voltage" {
+			value = value / 4
+		}
+		s.setRegister(aiLoc+i, math.Round(value*100)/100)
+	}
+
+	for i, item := range sectionList(s.config, "dos") {
+		if i < len(s.outputs) {
+			s.setRegister(itemInt(item, "memory"), float64(s.outputs[i]))
+		}
+	}
+}
+
+// aiLoc là thanh ghi đầu tiên của các kênh AI (common.ai_loc). Gọi khi đang giữ s.mu.
+func (s *Simulator) aiLoc() int {
+	if loc := intField(s.config, "common", "ai_loc"); loc > 0 {
+		return loc
+	}
+	return 0
+}
+
+// setRegister ghi thanh ghi nếu trong phạm vi. Gọi khi đang giữ s.mu.
+func (s *Simulator) setRegister(index int, value float64) {
+	if index >= 0 && index < MemorySize {
+		s.memory[index] = value
+	}
+}
+
+// analog tạo dữ liệu read_analog. Gọi khi đang giữ s.mu.
+func (s *Simulator) analog() []map[string]interface{} {
+	unit := "mA"
+	if s.measureMode == "voltage" {
+		unit = "V"
+	}
+
+	aiLoc := s.aiLoc()
+	var data []map[string]interface{}
+	for i := range sectionList(s.config, "ais") {
+		value := 0.0
+		if aiLoc+i < MemorySize {
+			value = s.memory[aiLoc+i]
+		}
+		data = append(data, map[string]interface{}{"id": i + 1, "value": value, "unit": unit})
+	}
+	return data
+}
+
+// tagView tạo dữ liệu read_tag_view từ section tags. Gọi khi đang giữ s.mu.
+func (s *Simulator) tagView() []map[string]interface{} {
+	var data []map[string]interface{}
+	for i, item := range sectionList(s.config, "tags") {
+		tag, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, status := 0.0, 0
+		if index := itemInt(tag, "val_idx"); index >= 0 && index < MemorySize {
+			value = s.memory[index]
+		}
+		if index := itemInt(tag, "stat_idx"); index >= 0 && index < MemorySize {
+			status = int(s.memory[index])
+		}
+		data = append(data, map[string]interface{}{
+			"id":     i + 1,
+			"name":   tag["name"],
+			"value":  value,
+			"unit":   tag["unit"],
+			"status": status,
+		})
+	}
+	return data
+}
+
+// sectionList trả về section dạng mảng của cấu hình
+func sectionList(config map[string]interface{}, name string) []interface{} {
+	list, _ := config[name].([]interface{})
+	return list
+}
+
+// intField đọc config[section][key] dạng số nguyên
+func intField(config map[string]interface{}, section, key string) int {
+	object, _ := config[section].(map[string]interface{})
+	return itemInt(object, key)
+}
+
+// itemInt đọc trường số nguyên của một phần tử cấu hình, -1 nếu không có
+func itemInt(item interface{}, key string) int {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	value, ok := object[key].(float64)
+	if !ok {
+		return -1
+	}
+	return int(value)
+}
+
+// cloneMap sao chép sâu một object JSON
+func cloneMap(source map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(source)
+	var copied map[string]interface{}
+	json.Unmarshal(data, &copied)
+	return copied
+}
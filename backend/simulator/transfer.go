@@ -0,0 +1,128 @@
+package simulator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"math/rand"
+	"sort"
+)
+
+// transfer là một lần nhận dữ liệu theo chunk. Trạng thái giữ ở cấp Simulator
+// (không theo session) để ứng dụng kết nối lại vẫn tiếp tục được.
+type transfer struct {
+	target string
+	size   int
+	chunks int
+	crc    uint32
+	data   map[int][]byte
+}
+
+func (t *transfer) have() []int {
+	seqs := make([]int, 0, len(t.data))
+	for seq := range t.data {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs
+}
+
+func (t *transfer) missing() []int {
+	var seqs []int
+	for seq := 0; seq < t.chunks; seq++ {
+		if _, ok := t.data[seq]; !ok {
+			seqs = append(seqs, seq)
+		}
+	}
+	return seqs
+}
+
+// handleTransfer xử lý transfer_begin, transfer_chunk, transfer_end (s.mu đã khóa)
+func (s *Simulator) handleTransfer(msgType string, message map[string]interface{}) reply {
+	id, _ := message["id"].(string)
+	if id == "" {
+		return failure(msgType, "fail", "thiếu id")
+	}
+
+	switch msgType {
+	case "transfer_begin":
+		target, _ := message["target"].(string)
+		if target != "upload_config" {
+			return failure(msgType, "fail", "không hỗ trợ target "+target)
+		}
+		size, _ := message["size"].(float64)
+		chunks, _ := message["chunks"].(float64)
+		crc, _ := message["crc"].(float64)
+
+		t, ok := s.transfers[id]
+		if !ok || t.size != int(size) || t.chunks != int(chunks) || t.crc != uint32(crc) {
+			t = &transfer{target: target, size: int(size), chunks: int(chunks), crc: uint32(crc), data: make(map[int][]byte)}
+			s.transfers[id] = t
+		}
+		result := success(msgType)
+		result["id"] = id
+		result["have"] = t.have()
+		return result
+
+	case "transfer_chunk":
+		t, ok := s.transfers[id]
+		if !ok {
+			return failure(msgType, "fail", "chưa bắt đầu truyền "+id)
+		}
+		// Giả lập đường truyền mất gói: không xác nhận để ứng dụng gửi lại
+		if s.options.ChunkLoss > 0 && rand.Float64() < s.options.ChunkLoss {
+			return nil
+		}
+		seq, ok := message["seq"].(float64)
+		if !ok || int(seq) < 0 || int(seq) >= t.chunks {
+			return failure(msgType, "fail", "seq không hợp lệ")
+		}
+		result := reply{"type": msgType, "id": id, "seq": int(seq)}
+		encoded, _ := message["data"].(string)
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		crc, _ := message["crc"].(float64)
+		if err != nil || crc32.ChecksumIEEE(data) != uint32(crc) {
+			result["status"] = "crc_error"
+			result["message"] = "CRC chunk không khớp"
+			return result
+		}
+		t.data[int(seq)] = data
+		result["status"] = "success"
+		return result
+
+	case "transfer_end":
+		t, ok := s.transfers[id]
+		if !ok {
+			return failure(msgType, "fail", "chưa bắt đầu truyền "+id)
+		}
+		result := reply{"type": msgType, "id": id}
+		if missing := t.missing(); len(missing) > 0 {
+			result["status"] = "missing"
+			result["message"] = "còn thiếu chunk"
+			result["missing"] = missing
+			return result
+		}
+
+		var payload []byte
+		for seq := 0; seq < t.chunks; seq++ {
+			payload = append(payload, t.data[seq]...)
+		}
+		delete(s.transfers, id)
+		if len(payload) != t.size || crc32.ChecksumIEEE(payload) != t.crc {
+			result["status"] = "crc_error"
+			result["message"] = "CRC toàn bộ dữ liệu không khớp"
+			return result
+		}
+
+		var config map[string]interface{}
+		if err := json.Unmarshal(payload, &config); err != nil || len(config) == 0 {
+			result["status"] = "fail"
+			result["message"] = "cấu hình không hợp lệ"
+			return result
+		}
+		s.config = config
+		result["status"] = "success"
+		return result
+	}
+	return nil
+}
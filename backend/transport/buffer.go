@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SocketOverflowEvent là tên sự kiện Wails phát khi bộ đệm nhận của socket bị tràn
+const SocketOverflowEvent = "socket:overflow"
+
+// overflowInterval giới hạn tần suất phát SocketOverflowEvent cho mỗi kết nối
+const overflowInterval = time.Second
+
+// SocketOverflow là nội dung của SocketOverflowEvent
+type SocketOverflow struct {
+	Key   string      `json:"key"`
+	Stats BufferStats `json:"stats"`
+}
+
+// reportOverflow ghi log và phát SocketOverflowEvent, tối đa một lần mỗi overflowInterval
+func (sm *SocketManager) reportOverflow(connectionKey string, socketConn *SocketConnection) {
+	socketConn.mutex.Lock()
+	if time.Since(socketConn.overflowAt) < overflowInterval {
+		socketConn.mutex.Unlock()
+		return
+	}
+	socketConn.overflowAt = time.Now()
+	socketConn.mutex.Unlock()
+
+	stats := socketConn.rx.Stats()
+	log.Printf("Bộ đệm socket %s bị tràn: đã bỏ %d dòng, %d frame quá dài", connectionKey, stats.Dropped, stats.Oversize)
+
+	sm.mutex.RLock()
+	ctx := sm.ctx
+	sm.mutex.RUnlock()
+	if ctx != nil {
+		runtime.EventsEmit(ctx, SocketOverflowEvent, SocketOverflow{Key: connectionKey, Stats: stats})
+	}
+}
+
+// BufferStats trả về thống kê bộ đệm nhận của kết nối socket address:port
+func (sm *SocketManager) BufferStats(address string, port string) (BufferStats, error) {
+	socketConn, err := sm.lookup(ConnectionKey(address, port))
+	if err != nil {
+		return BufferStats{}, err
+	}
+	return socketConn.rx.Stats(), nil
+}
+
+// SetBufferCapacity đổi số dòng tối đa của bộ đệm nhận cho các kết nối mở sau đó
+func (sm *SocketManager) SetBufferCapacity(lines int) error {
+	if lines <= 0 {
+		return fmt.Errorf("dung lượng bộ đệm phải lớn hơn 0: %d", lines)
+	}
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.capacity = lines
+	return nil
+}
+
+// BufferCapacity trả về số dòng tối đa của bộ đệm nhận cho kết nối mới
+func (sm *SocketManager) BufferCapacity() int {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return sm.capacity
+}
@@ -0,0 +1,255 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Chiều của frame trong file capture
+const (
+	DirectionTX = "tx"
+	DirectionRX = "rx"
+)
+
+// Giới hạn mặc định của file capture
+const (
+	DefaultCaptureMaxBytes = 10 << 20
+	DefaultCaptureMaxFiles = 5
+)
+
+// secretField khớp giá trị chuỗi của các trường mật khẩu: password, old_password, new_password
+// trong lệnh login, change_password, add_user và passwd, passwd2... trong cấu hình (control, ftp)
+var secretField = regexp.MustCompile(`("(?:password|old_password|new_password|passwd\w*)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// chunkData khớp payload base64 của lệnh transfer_chunk. Mật khẩu trong cấu hình có thể
+// bị cắt qua hai chunk nên không giải mã để che được, cả payload bị bỏ.
+var chunkData = regexp.MustCompile(`("data"\s*:\s*)"[A-Za-z0-9+/=]*"`)
+
+// Redact thay mật khẩu trong một dòng lệnh hoặc phản hồi bằng "***", giữ nguyên phần còn lại.
+// Dùng trước khi ghi dòng ra file hoặc log; Session vẫn giữ dòng gốc trong bộ nhớ để gửi lại.
+func Redact(line string) string {
+	line = secretField.ReplaceAllString(line, `$1"***"`)
+	if strings.Contains(line, `"transfer_chunk"`) {
+		line = chunkData.ReplaceAllString(line, `$1"***"`)
+	}
+	return line
+}
+
+// CaptureEntry là một dòng trong file capture JSONL
+type CaptureEntry struct {
+	Time      time.Time `json:"ts"`
+	Direction string    `json:"dir"`
+	Transport string    `json:"transport"`
+	Endpoint  string    `json:"endpoint"`
+	Data      string    `json:"data"`
+}
+
+// CaptureStatus mô tả trạng thái ghi capture
+type CaptureStatus struct {
+	Active    bool   `json:"active"`
+	Path      string `json:"path"`
+	MaxBytes  int64  `json:"maxBytes"`
+	MaxFiles  int    `json:"maxFiles"`
+	Bytes     int64  `json:"bytes"`     // dung lượng file hiện tại
+	Frames    uint64 `json:"frames"`    // số frame đã ghi từ khi bắt đầu
+	Rotations int    `json:"rotations"` // số lần đã xoay file
+	StartedAt string `json:"startedAt,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Recorder ghi mọi frame gửi/nhận của các đường truyền ra file JSONL.
+// Khi file vượt MaxBytes thì đổi tên thành path.1, path.2, ... và mở file mới.
+// Recorder nil hoặc chưa Start thì Record không làm gì.
+type Recorder struct {
+	mu        sync.Mutex
+	file      *os.File
+	path      string
+	maxBytes  int64
+	maxFiles  int
+	size      int64
+	frames    uint64
+	rotations int
+	startedAt time.Time
+	lastError string
+}
+
+// NewRecorder tạo Recorder chưa ghi
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start bắt đầu ghi vào path (tạo mới hoặc ghi tiếp), maxBytes/maxFiles <= 0 dùng mặc định
+func (r *Recorder) Start(path string, maxBytes int64, maxFiles int) error {
+	if path == "" {
+		return fmt.Errorf("chưa chọn file capture")
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultCaptureMaxBytes
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultCaptureMaxFiles
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != nil {
+		return fmt.Errorf("đang ghi capture vào %s", r.path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("không thể tạo thư mục capture: %w", err)
+	}
+	file, size, err := openCapture(path)
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+	r.path = path
+	r.maxBytes = maxBytes
+	r.maxFiles = maxFiles
+	r.size = size
+	r.frames = 0
+	r.rotations = 0
+	r.startedAt = time.Now()
+	r.lastError = ""
+	return nil
+}
+
+// Stop dừng ghi và đóng file
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return fmt.Errorf("chưa bắt đầu ghi capture")
+	}
+	err := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return fmt.Errorf("không thể đóng file capture: %w", err)
+	}
+	return nil
+}
+
+// Status trả về trạng thái ghi hiện tại
+func (r *Recorder) Status() CaptureStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := CaptureStatus{
+		Active:    r.file != nil,
+		Path:      r.path,
+		MaxBytes:  r.maxBytes,
+		MaxFiles:  r.maxFiles,
+		Bytes:     r.size,
+		Frames:    r.frames,
+		Rotations: r.rotations,
+		LastError: r.lastError,
+	}
+	if !r.startedAt.IsZero() {
+		status.StartedAt = r.startedAt.Format(time.RFC3339)
+	}
+	return status
+}
+
+// Record ghi một frame theo chiều direction (DirectionTX hoặc DirectionRX), mật khẩu được thay bằng "***"
+func (r *Recorder) Record(direction string, frame Frame) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+	if frame.Time.IsZero() {
+		frame.Time = time.Now()
+	}
+
+	line, err := json.Marshal(CaptureEntry{
+		Time:      frame.Time,
+		Direction: direction,
+		Transport: frame.Kind,
+		Endpoint:  frame.Endpoint,
+		Data:      Redact(frame.Data),
+	})
+	if err != nil {
+		r.lastError = err.Error()
+		return
+	}
+	line = append(line, '\n')
+
+	if r.size > 0 && r.size+int64(len(line)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			r.lastError = err.Error()
+			return
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		r.lastError = fmt.Sprintf("không thể ghi file capture: %v", err)
+		return
+	}
+	r.frames++
+}
+
+// Attach ghi lại mọi frame nhận được từ hub, trả về hàm hủy
+func (r *Recorder) Attach(hub *Hub) func() {
+	return hub.Subscribe(func(frame Frame) {
+		r.Record(DirectionRX, frame)
+	})
+}
+
+// rotate đổi path -> path.1 -> path.2 ... (bỏ file cũ nhất) rồi mở file mới. Gọi khi đang giữ r.mu.
+func (r *Recorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("không thể đóng file capture: %w", err)
+	}
+	r.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles-1))
+	for i := r.maxFiles - 2; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.maxFiles > 1 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return fmt.Errorf("không thể xoay file capture: %w", err)
+		}
+	} else {
+		os.Remove(r.path)
+	}
+
+	file, size, err := openCapture(r.path)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.size = size
+	r.rotations++
+	return nil
+}
+
+// openCapture mở file capture để ghi tiếp, trả về dung lượng hiện có
+func openCapture(path string) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("không thể mở file capture: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("không thể đọc file capture: %w", err)
+	}
+	return file, info.Size(), nil
+}
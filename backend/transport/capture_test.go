@@ -0,0 +1,57 @@
+package transport
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "login",
+			line: `{"password":"admin","type":"login","username":"admin"}`,
+			want: `{"password":"***","type":"login","username":"admin"}`,
+		},
+		{
+			name: "change_password",
+			line: `{"new_password":"b\"c","old_password":"a","type":"change_password"}`,
+			want: `{"new_password":"***","old_password":"***","type":"change_password"}`,
+		},
+		{
+			name: "upload_config",
+			line: `{"control":{"passwd":"12341234","passwd2":"43214321","user":"op"},"ftp":[{"passwd": "x y","server":"ftp.local"}],"type":"upload_config"}`,
+			want: `{"control":{"passwd":"***","passwd2":"***","user":"op"},"ftp":[{"passwd": "***","server":"ftp.local"}],"type":"upload_config"}`,
+		},
+		{
+			name: "download_section",
+			line: `{"data":{"passwd":"12341234"},"section":"control","status":"success","type":"download_section"}`,
+			want: `{"data":{"passwd":"***"},"section":"control","status":"success","type":"download_section"}`,
+		},
+		{
+			name: "transfer_chunk",
+			line: `{"crc":123,"data":"eyJwYXNzd2QiOiIxMjM0MTIzNCJ9","id":"ab","seq":0,"type":"transfer_chunk"}`,
+			want: `{"crc":123,"data":"***","id":"ab","seq":0,"type":"transfer_chunk"}`,
+		},
+		{
+			name: "data của lệnh khác giữ nguyên",
+			line: `{"data":"8.8.8.8","type":"ping"}`,
+			want: `{"data":"8.8.8.8","type":"ping"}`,
+		},
+		{
+			name: "trường không phải mật khẩu",
+			line: `{"passwd_len":8,"user":"passwd","type":"read_tag_view"}`,
+			want: `{"passwd_len":8,"user":"passwd","type":"read_tag_view"}`,
+		},
+		{
+			name: "không phải JSON",
+			line: `OK password`,
+			want: `OK password`,
+		},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.line); got != tt.want {
+			t.Errorf("%s: Redact =\n%s\ncần\n%s", tt.name, got, tt.want)
+		}
+	}
+}
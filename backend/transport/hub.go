@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"sync"
+	"time"
+)
+
+// Frame là một dòng dữ liệu (không gồm ký tự xuống dòng) nhận từ thiết bị
+type Frame struct {
+	Kind     string    `json:"kind"`
+	Endpoint string    `json:"endpoint"`
+	Data     string    `json:"data"`
+	Time     time.Time `json:"time"`
+}
+
+// Hub phát các frame nhận được tới mọi handler đã đăng ký.
+// Giá trị zero của Hub dùng được ngay.
+type Hub struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Frame)
+}
+
+// Subscribe đăng ký handler, trả về hàm hủy đăng ký.
+// Handler được gọi trên goroutine đọc nên không được block lâu.
+func (h *Hub) Subscribe(handler func(Frame)) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.handlers == nil {
+		h.handlers = make(map[int]func(Frame))
+	}
+	id := h.nextID
+	h.nextID++
+	h.handlers[id] = handler
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.handlers, id)
+	}
+}
+
+// Publish gửi frame tới tất cả handler
+func (h *Hub) Publish(frame Frame) {
+	h.mu.RLock()
+	handlers := make([]func(Frame), 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler)
+	}
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(frame)
+	}
+}
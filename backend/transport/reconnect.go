@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Trạng thái của một kết nối socket
+const (
+	SocketConnected    = "connected"
+	SocketReconnecting = "reconnecting"
+	SocketClosed       = "closed"
+)
+
+// SocketStateEvent là tên sự kiện Wails phát khi trạng thái kết nối socket thay đổi
+const SocketStateEvent = "socket:state"
+
+// ReconnectPolicy cấu hình keepalive và tự động kết nối lại cho socket
+type ReconnectPolicy struct {
+	Enabled          bool    `json:"enabled"`
+	KeepAliveSeconds int     `json:"keepAliveSeconds"` // chu kỳ TCP keepalive, 0 để tắt
+	InitialDelayMs   int     `json:"initialDelayMs"`   // thời gian chờ trước lần thử đầu tiên
+	MaxDelayMs       int     `json:"maxDelayMs"`       // thời gian chờ tối đa giữa hai lần thử
+	Multiplier       float64 `json:"multiplier"`       // hệ số tăng thời gian chờ sau mỗi lần thất bại
+	MaxAttempts      int     `json:"maxAttempts"`      // 0 là thử mãi
+}
+
+// DefaultReconnectPolicy phù hợp với đường truyền 3G/4G tới trạm quan trắc
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		Enabled:          true,
+		KeepAliveSeconds: 15,
+		InitialDelayMs:   1000,
+		MaxDelayMs:       30000,
+		Multiplier:       2,
+		MaxAttempts:      0,
+	}
+}
+
+// SocketStatus mô tả sức khỏe của một kết nối socket
+type SocketStatus struct {
+	Key         string      `json:"key"`
+	State       string      `json:"state"`
+	Reconnects  int         `json:"reconnects"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"lastError,omitempty"`
+	ConnectedAt string      `json:"connectedAt,omitempty"`
+	LastRxAt    string      `json:"lastRxAt,omitempty"`
+	LoggedIn    bool        `json:"loggedIn"`
+	Streams     []string    `json:"streams"`
+	Buffer      BufferStats `json:"buffer"`
+}
+
+// SetContext lưu context của Wails để phát sự kiện trạng thái socket
+func (sm *SocketManager) SetContext(ctx context.Context) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.ctx = ctx
+}
+
+// SetReconnectPolicy đổi chính sách kết nối lại cho các lần kết nối sau
+func (sm *SocketManager) SetReconnectPolicy(policy ReconnectPolicy) error {
+	if policy.KeepAliveSeconds < 0 || policy.InitialDelayMs < 0 || policy.MaxDelayMs < 0 || policy.MaxAttempts < 0 {
+		return fmt.Errorf("chính sách kết nối lại không được có giá trị âm")
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 1
+	}
+	if policy.MaxDelayMs < policy.InitialDelayMs {
+		policy.MaxDelayMs = policy.InitialDelayMs
+	}
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.policy = policy
+	return nil
+}
+
+// ReconnectPolicy trả về chính sách kết nối lại hiện tại
+func (sm *SocketManager) ReconnectPolicy() ReconnectPolicy {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	return sm.policy
+}
+
+// dial mở kết nối TCP có bật keepalive theo policy
+func dial(connectionKey string, policy ReconnectPolicy) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second, KeepAlive: -1}
+	if policy.KeepAliveSeconds > 0 {
+		dialer.KeepAlive = time.Duration(policy.KeepAliveSeconds) * time.Second
+	}
+	return dialer.Dial("tcp", connectionKey)
+}
+
+// status tạo SocketStatus. Gọi khi đang giữ socketConn.mutex.
+func (sc *SocketConnection) status(connectionKey string) SocketStatus {
+	status := SocketStatus{
+		Key:        connectionKey,
+		State:      sc.state,
+		Reconnects: sc.reconnects,
+		Attempts:   sc.attempts,
+		LastError:  sc.lastError,
+		LoggedIn:   sc.session.LoggedIn(),
+		Streams:    sc.session.Streams(),
+		Buffer:     sc.rx.Stats(),
+	}
+	if !sc.connectedAt.IsZero() {
+		status.ConnectedAt = sc.connectedAt.Format(time.RFC3339)
+	}
+	if !sc.lastRxAt.IsZero() {
+		status.LastRxAt = sc.lastRxAt.Format(time.RFC3339)
+	}
+	return status
+}
+
+// emitState phát SocketStateEvent cho frontend
+func (sm *SocketManager) emitState(status SocketStatus) {
+	sm.mutex.RLock()
+	ctx := sm.ctx
+	sm.mutex.RUnlock()
+
+	if ctx != nil {
+		runtime.EventsEmit(ctx, SocketStateEvent, status)
+	}
+}
+
+// reconnect thử kết nối lại với thời gian chờ tăng dần theo policy.
+// Trả về false nếu không được phép kết nối lại, người dùng đã ngắt hoặc hết số lần thử.
+func (sm *SocketManager) reconnect(connectionKey string, socketConn *SocketConnection, cause error) bool {
+	policy := sm.ReconnectPolicy()
+	if !policy.Enabled {
+		return false
+	}
+
+	socketConn.mutex.Lock()
+	socketConn.state = SocketReconnecting
+	socketConn.lastError = cause.Error()
+	socketConn.attempts = 0
+	socketConn.conn.Close()
+	status := socketConn.status(connectionKey)
+	socketConn.mutex.Unlock()
+	sm.emitState(status)
+
+	log.Printf("Mất kết nối socket %s: %v, đang kết nối lại", connectionKey, cause)
+
+	delay := time.Duration(policy.InitialDelayMs) * time.Millisecond
+	maxDelay := time.Duration(policy.MaxDelayMs) * time.Millisecond
+
+	for attempt := 1; policy.MaxAttempts == 0 || attempt <= policy.MaxAttempts; attempt++ {
+		select {
+		case <-socketConn.closed:
+			return false
+		case <-time.After(delay):
+		}
+
+		conn, err := dial(connectionKey, policy)
+
+		socketConn.mutex.Lock()
+		socketConn.attempts = attempt
+		if err != nil {
+			socketConn.lastError = err.Error()
+			status := socketConn.status(connectionKey)
+			socketConn.mutex.Unlock()
+			sm.emitState(status)
+
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+			if delay < 100*time.Millisecond {
+				delay = 100 * time.Millisecond
+			}
+			continue
+		}
+
+		select {
+		case <-socketConn.closed:
+			// Người dùng đã ngắt kết nối trong lúc đang kết nối lại
+			socketConn.mutex.Unlock()
+			conn.Close()
+			return false
+		default:
+		}
+
+		socketConn.conn = conn
+		socketConn.state = SocketConnected
+		socketConn.reconnects++
+		socketConn.lastError = ""
+		socketConn.connectedAt = time.Now()
+		replay := socketConn.session.Replay()
+		status := socketConn.status(connectionKey)
+		socketConn.mutex.Unlock()
+		sm.emitState(status)
+
+		log.Printf("Đã kết nối lại socket %s sau %d lần thử", connectionKey, attempt)
+		for _, line := range replay {
+			if err := sm.write(connectionKey, socketConn, line); err != nil {
+				log.Printf("Không thể gửi lại lệnh sau khi kết nối lại %s: %v", connectionKey, err)
+				break
+			}
+		}
+		return true
+	}
+
+	return false
+}
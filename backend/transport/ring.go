@@ -0,0 +1,203 @@
+package transport
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultRingCapacity là số dòng tối đa chờ phát cho subscriber của mỗi kết nối
+const DefaultRingCapacity = 2048
+
+// maxFrameBytes giới hạn độ dài một frame đang ghép (download_config có thể rất dài)
+const maxFrameBytes = 4 << 20
+
+// BufferStats là thống kê bộ đệm nhận của một kết nối
+type BufferStats struct {
+	Capacity  int    `json:"capacity"`
+	Length    int    `json:"length"`    // số dòng đang chờ phát
+	Received  uint64 `json:"received"`  // tổng số dòng đã nhận
+	Dropped   uint64 `json:"dropped"`   // số dòng cũ bị bỏ vì bộ đệm đầy
+	Oversize  uint64 `json:"oversize"`  // số frame bị bỏ vì dài quá maxFrameBytes
+	HighWater int    `json:"highWater"` // số dòng chờ lớn nhất từng có
+}
+
+// LineRing là bộ đệm vòng có giới hạn chứa các frame hoàn chỉnh đang chờ phát cho subscriber.
+// Goroutine đọc socket chỉ Push nên không bao giờ bị subscriber chậm chặn lại;
+// khi đầy chỉ frame cũ nhất bị bỏ và được đếm lại, không xóa cả bộ đệm.
+type LineRing struct {
+	mu     sync.Mutex
+	frames []Frame
+	head   int
+	count  int
+	ready  chan struct{}
+	stats  BufferStats
+}
+
+// NewLineRing tạo bộ đệm vòng chứa tối đa capacity frame
+func NewLineRing(capacity int) *LineRing {
+	if capacity <= 0 {
+		capacity = DefaultRingCapacity
+	}
+	return &LineRing{
+		frames: make([]Frame, capacity),
+		ready:  make(chan struct{}, 1),
+		stats:  BufferStats{Capacity: capacity},
+	}
+}
+
+// Push thêm một frame, trả về true nếu phải bỏ frame cũ nhất
+func (r *LineRing) Push(frame Frame) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Received++
+	dropped := false
+	if r.count == len(r.frames) {
+		r.frames[r.head] = Frame{}
+		r.head = (r.head + 1) % len(r.frames)
+		r.count--
+		r.stats.Dropped++
+		dropped = true
+	}
+
+	r.frames[(r.head+r.count)%len(r.frames)] = frame
+	r.count++
+	if r.count > r.stats.HighWater {
+		r.stats.HighWater = r.count
+	}
+
+	select {
+	case r.ready <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
+// Ready nhận tín hiệu khi có frame mới được Push
+func (r *LineRing) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// Drain lấy toàn bộ các frame đang chờ theo thứ tự nhận
+func (r *LineRing) Drain() []Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	frames := make([]Frame, 0, r.count)
+	for r.count > 0 {
+		frames = append(frames, r.frames[r.head])
+		r.frames[r.head] = Frame{}
+		r.head = (r.head + 1) % len(r.frames)
+		r.count--
+	}
+	return frames
+}
+
+// markOversize đếm một frame bị bỏ vì quá dài
+func (r *LineRing) markOversize() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Oversize++
+}
+
+// Stats trả về thống kê hiện tại
+func (r *LineRing) Stats() BufferStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	stats.Length = r.count
+	return stats
+}
+
+// Framer ghép các mảnh dữ liệu TCP thành từng frame. Frame kết thúc ở ký tự
+// xuống dòng, hoặc khi một object/array JSON ở mức ngoài cùng đóng lại
+// (thiết bị đôi khi gửi liền nhiều object không có xuống dòng).
+type Framer struct {
+	pending  []byte
+	depth    int
+	inString bool
+	escaped  bool
+	oversize bool
+}
+
+// Feed thêm dữ liệu vừa đọc và trả về các frame hoàn chỉnh.
+// overflow là số frame bị bỏ vì dài quá maxFrameBytes.
+func (f *Framer) Feed(data []byte) (frames []string, overflow int) {
+	for _, b := range data {
+		if f.oversize {
+			// Bỏ phần còn lại của frame quá dài tới hết dòng
+			if b == '\n' {
+				f.oversize = false
+				f.reset()
+			}
+			continue
+		}
+
+		if b == '\n' {
+			if frame := f.take(); frame != "" {
+				frames = append(frames, frame)
+			}
+			continue
+		}
+
+		f.pending = append(f.pending, b)
+		if len(f.pending) > maxFrameBytes {
+			f.oversize = true
+			f.reset()
+			overflow++
+			continue
+		}
+
+		if f.inString {
+			switch {
+			case f.escaped:
+				f.escaped = false
+			case b == '\\':
+				f.escaped = true
+			case b == '"':
+				f.inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			if f.depth > 0 {
+				f.inString = true
+			}
+		case '{', '[':
+			f.depth++
+		case '}', ']':
+			if f.depth > 0 {
+				f.depth--
+				if f.depth == 0 {
+					if frame := f.take(); frame != "" {
+						frames = append(frames, frame)
+					}
+				}
+			}
+		}
+	}
+	return frames, overflow
+}
+
+// Reset bỏ phần frame đang ghép dở (sau khi kết nối lại)
+func (f *Framer) Reset() {
+	f.oversize = false
+	f.reset()
+}
+
+func (f *Framer) reset() {
+	f.pending = f.pending[:0]
+	f.depth = 0
+	f.inString = false
+	f.escaped = false
+}
+
+// take trả về frame đang ghép (đã bỏ khoảng trắng) và bắt đầu frame mới
+func (f *Framer) take() string {
+	frame := strings.TrimSpace(string(f.pending))
+	f.reset()
+	return frame
+}
@@ -0,0 +1,179 @@
+package transport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func frameData(frames []Frame) []string {
+	data := make([]string, len(frames))
+	for i, frame := range frames {
+		data[i] = frame.Data
+	}
+	return data
+}
+
+func TestLineRing(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		push     []string
+		drained  []string
+		dropped  []bool // kết quả Push của từng dòng
+		stats    BufferStats
+	}{
+		{
+			name:     "chưa đầy",
+			capacity: 3,
+			push:     []string{"a", "b"},
+			drained:  []string{"a", "b"},
+			dropped:  []bool{false, false},
+			stats:    BufferStats{Capacity: 3, Length: 2, Received: 2, HighWater: 2},
+		},
+		{
+			name:     "vừa đủ",
+			capacity: 3,
+			push:     []string{"a", "b", "c"},
+			drained:  []string{"a", "b", "c"},
+			dropped:  []bool{false, false, false},
+			stats:    BufferStats{Capacity: 3, Length: 3, Received: 3, HighWater: 3},
+		},
+		{
+			name:     "tràn chỉ bỏ dòng cũ nhất",
+			capacity: 3,
+			push:     []string{"a", "b", "c", "d", "e"},
+			drained:  []string{"c", "d", "e"},
+			dropped:  []bool{false, false, false, true, true},
+			stats:    BufferStats{Capacity: 3, Length: 3, Received: 5, Dropped: 2, HighWater: 3},
+		},
+		{
+			name:     "dung lượng mặc định",
+			capacity: 0,
+			push:     []string{"a"},
+			drained:  []string{"a"},
+			dropped:  []bool{false},
+			stats:    BufferStats{Capacity: DefaultRingCapacity, Length: 1, Received: 1, HighWater: 1},
+		},
+	}
+	for _, tt := range tests {
+		ring := NewLineRing(tt.capacity)
+		for i, line := range tt.push {
+			if dropped := ring.Push(Frame{Data: line}); dropped != tt.dropped[i] {
+				t.Errorf("%s: Push(%q) = %v, cần %v", tt.name, line, dropped, tt.dropped[i])
+			}
+		}
+		if stats := ring.Stats(); stats != tt.stats {
+			t.Errorf("%s: stats = %+v, cần %+v", tt.name, stats, tt.stats)
+		}
+		if got := frameData(ring.Drain()); !reflect.DeepEqual(got, tt.drained) {
+			t.Errorf("%s: Drain = %v, cần %v", tt.name, got, tt.drained)
+		}
+		if stats := ring.Stats(); stats.Length != 0 || stats.Dropped != tt.stats.Dropped {
+			t.Errorf("%s: sau Drain stats = %+v", tt.name, stats)
+		}
+	}
+}
+
+func TestLineRingWrapAndReady(t *testing.T) {
+	ring := NewLineRing(2)
+	select {
+	case <-ring.Ready():
+		t.Fatal("Ready báo khi chưa Push")
+	default:
+	}
+
+	// Đọc xen kẽ để head đi vòng qua cuối mảng
+	var got []string
+	for _, line := range []string{"1", "2", "3", "4", "5"} {
+		ring.Push(Frame{Data: line})
+		<-ring.Ready()
+		got = append(got, frameData(ring.Drain())...)
+	}
+	if want := []string{"1", "2", "3", "4", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("đọc được %v, cần %v", got, want)
+	}
+	if stats := ring.Stats(); stats.Dropped != 0 || stats.HighWater != 1 {
+		t.Errorf("stats = %+v, cần không bỏ dòng nào", stats)
+	}
+}
+
+func TestDeliverSlowSubscriber(t *testing.T) {
+	sm := NewSocketManager()
+	socketConn := &SocketConnection{rx: NewLineRing(3), stopped: make(chan struct{})}
+
+	var got []string
+	sm.Subscribe(func(frame Frame) { got = append(got, frame.Data) })
+
+	// Subscriber chưa nhận kịp: goroutine đọc vẫn không bị chặn, dòng cũ bị bỏ và được đếm
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		sm.receive("10.0.0.1:19981", socketConn, line)
+	}
+	close(socketConn.stopped)
+	sm.deliver(socketConn)
+
+	if want := []string{"c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("subscriber nhận %v, cần %v", got, want)
+	}
+	if stats := socketConn.rx.Stats(); stats.Received != 5 || stats.Dropped != 2 || stats.Length != 0 {
+		t.Errorf("stats = %+v, cần nhận 5, bỏ 2", stats)
+	}
+
+	// Không có kết nối thì phát ngay
+	got = nil
+	sm.Inject("10.0.0.2:19981", "x")
+	if want := []string{"x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Inject không có kết nối: subscriber nhận %v, cần %v", got, want)
+	}
+}
+
+func TestFramer(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		frames []string
+	}{
+		{"theo dòng", []string{"abc\r\ndef\n"}, []string{"abc", "def"}},
+		{"JSON bị chia qua nhiều lần đọc", []string{`{"type":"re`, `ad_analog","v":[1,`, `2]}`}, []string{`{"type":"read_analog","v":[1,2]}`}},
+		{"nhiều object liền nhau", []string{`{"a":1}{"b":2}[3]`}, []string{`{"a":1}`, `{"b":2}`, `[3]`}},
+		{"ngoặc trong chuỗi", []string{`{"s":"}{]\"}"}`}, []string{`{"s":"}{]\"}"}`}},
+		{"xuống dòng kết thúc frame", []string{"{\"a\":\n1}\n"}, []string{`{"a":`, `1}`}},
+		{"dòng trống bị bỏ", []string{"\n  \n{}\n"}, []string{`{}`}},
+		{"chưa đủ frame", []string{`{"a":`}, nil},
+	}
+	for _, tt := range tests {
+		var f Framer
+		var frames []string
+		for _, chunk := range tt.chunks {
+			got, overflow := f.Feed([]byte(chunk))
+			if overflow != 0 {
+				t.Errorf("%s: overflow %d", tt.name, overflow)
+			}
+			frames = append(frames, got...)
+		}
+		if !reflect.DeepEqual(frames, tt.frames) {
+			t.Errorf("%s: frame = %q, cần %q", tt.name, frames, tt.frames)
+		}
+	}
+}
+
+func TestFramerOversize(t *testing.T) {
+	var f Framer
+	// Frame quá dài bị bỏ tới hết dòng và được đếm, frame sau vẫn nhận đủ
+	long := "{" + strings.Repeat("x", maxFrameBytes)
+	frames, overflow := f.Feed([]byte(long))
+	if len(frames) != 0 || overflow != 1 {
+		t.Fatalf("frame = %d, overflow = %d, cần 0 và 1", len(frames), overflow)
+	}
+	frames, overflow = f.Feed([]byte("}}}\n{\"ok\":1}"))
+	if !reflect.DeepEqual(frames, []string{`{"ok":1}`}) || overflow != 0 {
+		t.Errorf("frame = %q, overflow = %d, cần {\"ok\":1}", frames, overflow)
+	}
+
+	// Reset bỏ phần đang ghép dở sau khi kết nối lại
+	f.Feed([]byte(`{"a":`))
+	f.Reset()
+	if frames, _ := f.Feed([]byte(`{"b":1}`)); !reflect.DeepEqual(frames, []string{`{"b":1}`}) {
+		t.Errorf("sau Reset frame = %q", frames)
+	}
+}
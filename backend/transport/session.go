@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// streamTypes là các lệnh bật/tắt luồng dữ liệu liên tục
+var streamTypes = map[string]bool{
+	"read_analog":      true,
+	"read_tag_view":    true,
+	"read_memory_view": true,
+}
+
+// Session ghi nhớ các lệnh cần gửi lại sau khi kết nối lại:
+// lệnh login gần nhất và các luồng dữ liệu đang bật.
+// Dòng login chứa mật khẩu nên chỉ nằm trong bộ nhớ, không được ghi ra file hay trả lên frontend.
+type Session struct {
+	mu      sync.Mutex
+	login   string
+	streams map[string]string
+	order   []string
+}
+
+// Record cập nhật session theo một dòng vừa gửi xuống thiết bị
+func (s *Session) Record(line string) {
+	var message struct {
+		Type string `json:"type"`
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case message.Type == "login":
+		s.login = line
+	case message.Type == "logout":
+		s.login = ""
+		s.streams = nil
+		s.order = nil
+	case streamTypes[message.Type] && message.Data == "enable":
+		if s.streams == nil {
+			s.streams = make(map[string]string)
+		}
+		if _, exists := s.streams[message.Type]; !exists {
+			s.order = append(s.order, message.Type)
+		}
+		s.streams[message.Type] = line
+	case streamTypes[message.Type] && message.Data == "disable":
+		delete(s.streams, message.Type)
+		for i, t := range s.order {
+			if t == message.Type {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Replay trả về các dòng cần gửi lại theo thứ tự: login trước, sau đó các luồng
+func (s *Session) Replay() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lines []string
+	if s.login != "" {
+		lines = append(lines, s.login)
+	}
+	for _, t := range s.order {
+		lines = append(lines, s.streams[t])
+	}
+	return lines
+}
+
+// Streams liệt kê các luồng dữ liệu đang bật
+func (s *Session) Streams() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+// LoggedIn cho biết session đã có lệnh login chưa
+func (s *Session) LoggedIn() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.login != ""
+}
+
+// Reset xóa toàn bộ session
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.login = ""
+	s.streams = nil
+	s.order = nil
+}
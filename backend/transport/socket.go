@@ -0,0 +1,419 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SocketConnection quản lý kết nối socket
+type SocketConnection struct {
+	conn        net.Conn
+	state       string
+	mutex       sync.Mutex
+	rx          *LineRing
+	session     Session
+	closed      chan struct{} // đóng khi người dùng ngắt kết nối
+	stopped     chan struct{} // đóng khi goroutine đọc dừng hẳn
+	closeOnce   sync.Once
+	reconnects  int
+	attempts    int
+	lastError   string
+	connectedAt time.Time
+	lastRxAt    time.Time
+	overflowAt  time.Time // lần cuối phát SocketOverflowEvent
+}
+
+// isActive cho biết kết nối đang dùng được. Gọi khi đang giữ mutex.
+func (sc *SocketConnection) isActive() bool {
+	return sc.state == SocketConnected
+}
+
+// close đánh dấu kết nối bị ngắt chủ động
+func (sc *SocketConnection) close() {
+	sc.closeOnce.Do(func() { close(sc.closed) })
+}
+
+// SocketManager quản lý các kết nối socket
+type SocketManager struct {
+	ctx         context.Context
+	connections map[string]*SocketConnection
+	mutex       sync.RWMutex
+	hub         Hub
+	policy      ReconnectPolicy
+	capacity    int
+	recorder    atomic.Pointer[Recorder]
+	detach      func()
+}
+
+// NewSocketManager tạo mới socket manager
+func NewSocketManager() *SocketManager {
+	return &SocketManager{
+		connections: make(map[string]*SocketConnection),
+		policy:      DefaultReconnectPolicy(),
+		capacity:    DefaultRingCapacity,
+	}
+}
+
+// ConnectionKey tạo key duy nhất cho connection từ địa chỉ và port
+func ConnectionKey(address string, port string) string {
+	return net.JoinHostPort(address, port)
+}
+
+// Connect kết nối tới socket server tại địa chỉ và port được chỉ định
+func (sm *SocketManager) Connect(address string, port string) (string, error) {
+	connectionKey := ConnectionKey(address, port)
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	// Kiểm tra xem đã có kết nối nào tồn tại chưa
+	if existing, exists := sm.connections[connectionKey]; exists {
+		existing.mutex.Lock()
+		state := existing.state
+		existing.mutex.Unlock()
+
+		switch state {
+		case SocketConnected:
+			return fmt.Sprintf("Đã có kết nối tới %s", connectionKey), nil
+		case SocketReconnecting:
+			return fmt.Sprintf("Đang kết nối lại tới %s", connectionKey), nil
+		}
+		// Nếu connection cũ không active, xóa nó
+		delete(sm.connections, connectionKey)
+	}
+
+	// Tạo kết nối mới
+	conn, err := dial(connectionKey, sm.policy)
+	if err != nil {
+		return "", fmt.Errorf("không thể kết nối tới %s: %w", connectionKey, err)
+	}
+
+	socketConn := &SocketConnection{
+		conn:        conn,
+		state:       SocketConnected,
+		rx:          NewLineRing(sm.capacity),
+		closed:      make(chan struct{}),
+		stopped:     make(chan struct{}),
+		connectedAt: time.Now(),
+	}
+
+	// Lưu connection
+	sm.connections[connectionKey] = socketConn
+
+	// Bắt đầu goroutine để đọc dữ liệu và goroutine phát dữ liệu cho subscriber
+	go sm.readSocketData(connectionKey, socketConn)
+	go sm.deliver(socketConn)
+
+	fmt.Printf("✅ Đã kết nối thành công tới socket: %s\n", connectionKey)
+	return fmt.Sprintf("Kết nối thành công tới %s", connectionKey), nil
+}
+
+// readSocketData đọc dữ liệu từ socket connection - ULTRA FAST
+func (sm *SocketManager) readSocketData(connectionKey string, socketConn *SocketConnection) {
+	defer func() {
+		socketConn.mutex.Lock()
+		socketConn.state = SocketClosed
+		socketConn.conn.Close()
+		status := socketConn.status(connectionKey)
+		socketConn.mutex.Unlock()
+
+		sm.mutex.Lock()
+		if sm.connections[connectionKey] == socketConn {
+			delete(sm.connections, connectionKey)
+		}
+		sm.mutex.Unlock()
+		close(socketConn.stopped)
+		sm.emitState(status)
+	}()
+
+	buffer := make([]byte, 4096) // Giảm buffer để responsive hơn
+	var framer Framer
+
+	for {
+		select {
+		case <-socketConn.closed:
+			return
+		default:
+		}
+
+		socketConn.mutex.Lock()
+		conn := socketConn.conn
+		socketConn.mutex.Unlock()
+
+		// Timeout cực ngắn để real-time
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+
+		n, err := conn.Read(buffer)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue // Timeout ngắn - tiếp tục ngay
+			}
+			select {
+			case <-socketConn.closed:
+				return
+			default:
+			}
+			// Lỗi khác - thử kết nối lại, nếu không được thì thoát
+			if !sm.reconnect(connectionKey, socketConn, err) {
+				return
+			}
+			framer.Reset()
+			continue
+		}
+
+		if n > 0 {
+			socketConn.mutex.Lock()
+			socketConn.lastRxAt = time.Now()
+			socketConn.mutex.Unlock()
+
+			// Ghép lại thành từng frame hoàn chỉnh, kể cả khi JSON bị chia qua nhiều lần đọc
+			frames, oversize := framer.Feed(buffer[:n])
+			for i := 0; i < oversize; i++ {
+				socketConn.rx.markOversize()
+			}
+			overflowed := oversize > 0
+			for _, line := range frames {
+				if sm.receive(connectionKey, socketConn, line) {
+					overflowed = true
+				}
+			}
+			if overflowed {
+				sm.reportOverflow(connectionKey, socketConn)
+			}
+		}
+	}
+}
+
+// receive đưa một frame vào bộ đệm của kết nối để deliver phát cho subscriber,
+// hoặc phát ngay nếu không có kết nối. Trả về true nếu bộ đệm phải bỏ dòng cũ.
+func (sm *SocketManager) receive(connectionKey string, socketConn *SocketConnection, line string) bool {
+	frame := Frame{Kind: KindTCP, Endpoint: connectionKey, Data: line, Time: time.Now()}
+	if socketConn == nil {
+		sm.hub.Publish(frame)
+		return false
+	}
+	return socketConn.rx.Push(frame)
+}
+
+// deliver phát các frame trong bộ đệm của kết nối cho subscriber theo thứ tự nhận,
+// tới khi goroutine đọc dừng và bộ đệm đã rỗng
+func (sm *SocketManager) deliver(socketConn *SocketConnection) {
+	for {
+		select {
+		case <-socketConn.rx.Ready():
+		case <-socketConn.stopped:
+			for _, frame := range socketConn.rx.Drain() {
+				sm.hub.Publish(frame)
+			}
+			return
+		}
+		for _, frame := range socketConn.rx.Drain() {
+			sm.hub.Publish(frame)
+		}
+	}
+}
+
+// Inject đưa một dòng vào đường nhận như thể đọc được từ socket endpoint (dùng khi phát lại capture).
+// Không cần có kết nối thật; nếu có thì dòng đi qua bộ đệm của kết nối đó.
+func (sm *SocketManager) Inject(endpoint, line string) {
+	sm.mutex.RLock()
+	socketConn := sm.connections[endpoint]
+	sm.mutex.RUnlock()
+
+	if sm.receive(endpoint, socketConn, line) {
+		sm.reportOverflow(endpoint, socketConn)
+	}
+}
+
+// lookup trả về connection theo key
+func (sm *SocketManager) lookup(connectionKey string) (*SocketConnection, error) {
+	sm.mutex.RLock()
+	socketConn, exists := sm.connections[connectionKey]
+	sm.mutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("không có kết nối tới %s", connectionKey)
+	}
+	return socketConn, nil
+}
+
+// Send gửi một dòng dữ liệu tới socket
+func (sm *SocketManager) Send(address string, port string, data string) error {
+	connectionKey := ConnectionKey(address, port)
+
+	socketConn, err := sm.lookup(connectionKey)
+	if err != nil {
+		return err
+	}
+	return sm.write(connectionKey, socketConn, data)
+}
+
+// write ghi một dòng xuống socketConn và ghi nhận vào session
+func (sm *SocketManager) write(connectionKey string, socketConn *SocketConnection, data string) error {
+	socketConn.mutex.Lock()
+	defer socketConn.mutex.Unlock()
+
+	switch socketConn.state {
+	case SocketConnected:
+	case SocketReconnecting:
+		return fmt.Errorf("kết nối tới %s đang được kết nối lại", connectionKey)
+	default:
+		return fmt.Errorf("kết nối tới %s không còn hoạt động", connectionKey)
+	}
+
+	// Set timeout cho việc ghi
+	socketConn.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+
+	// Thêm byte 0x0A (Line Feed) vào cuối dữ liệu
+	finalData := append([]byte(data), 0x0A)
+
+	_, err := socketConn.conn.Write(finalData)
+	if err != nil {
+		return fmt.Errorf("không thể gửi dữ liệu tới socket %s: %w", connectionKey, err)
+	}
+
+	socketConn.session.Record(data)
+	sm.recorder.Load().Record(DirectionTX, Frame{Kind: KindTCP, Endpoint: connectionKey, Data: data, Time: time.Now()})
+	fmt.Printf("📤 Đã gửi dữ liệu tới socket %s: %s\n", connectionKey, Redact(data))
+	return nil
+}
+
+// Disconnect đóng kết nối socket và xóa khỏi danh sách
+func (sm *SocketManager) Disconnect(address string, port string) error {
+	connectionKey := ConnectionKey(address, port)
+
+	sm.mutex.Lock()
+	socketConn, exists := sm.connections[connectionKey]
+	if !exists {
+		sm.mutex.Unlock()
+		return fmt.Errorf("không có kết nối tới %s", connectionKey)
+	}
+
+	socketConn.close()
+	socketConn.mutex.Lock()
+	socketConn.state = SocketClosed
+	socketConn.conn.Close()
+	socketConn.mutex.Unlock()
+
+	delete(sm.connections, connectionKey)
+	sm.mutex.Unlock()
+
+	fmt.Printf("🔌 Đã ngắt kết nối socket: %s\n", connectionKey)
+	return nil
+}
+
+// ListActive liệt kê tất cả kết nối đang hoạt động
+func (sm *SocketManager) ListActive() []string {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	var activeConnections []string
+	for key, conn := range sm.connections {
+		conn.mutex.Lock()
+		if conn.isActive() {
+			activeConnections = append(activeConnections, key)
+		}
+		conn.mutex.Unlock()
+	}
+
+	return activeConnections
+}
+
+// IsActive kiểm tra trạng thái kết nối socket
+func (sm *SocketManager) IsActive(address string, port string) bool {
+	socketConn, err := sm.lookup(ConnectionKey(address, port))
+	if err != nil {
+		return false
+	}
+
+	socketConn.mutex.Lock()
+	defer socketConn.mutex.Unlock()
+	return socketConn.isActive()
+}
+
+// Status trả về sức khỏe của kết nối socket address:port
+func (sm *SocketManager) Status(address string, port string) (SocketStatus, error) {
+	connectionKey := ConnectionKey(address, port)
+
+	socketConn, err := sm.lookup(connectionKey)
+	if err != nil {
+		return SocketStatus{Key: connectionKey, State: SocketClosed}, err
+	}
+
+	socketConn.mutex.Lock()
+	defer socketConn.mutex.Unlock()
+	return socketConn.status(connectionKey), nil
+}
+
+// ListStatus trả về sức khỏe của mọi kết nối socket, kể cả kết nối đang kết nối lại
+func (sm *SocketManager) ListStatus() []SocketStatus {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	statuses := make([]SocketStatus, 0, len(sm.connections))
+	for key, conn := range sm.connections {
+		conn.mutex.Lock()
+		statuses = append(statuses, conn.status(key))
+		conn.mutex.Unlock()
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Key < statuses[j].Key })
+	return statuses
+}
+
+// Subscribe nhận các dòng dữ liệu từ mọi kết nối socket, trả về hàm hủy đăng ký
+func (sm *SocketManager) Subscribe(handler func(Frame)) func() {
+	return sm.hub.Subscribe(handler)
+}
+
+// SetRecorder ghi lại mọi frame gửi/nhận qua socket vào recorder (nil để tắt)
+func (sm *SocketManager) SetRecorder(recorder *Recorder) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.detach != nil {
+		sm.detach()
+		sm.detach = nil
+	}
+	sm.recorder.Store(recorder)
+	if recorder != nil {
+		sm.detach = recorder.Attach(&sm.hub)
+	}
+}
+
+// Transport trả về Transport gửi qua kết nối socket address:port
+func (sm *SocketManager) Transport(address string, port string) Transport {
+	return &socketTransport{manager: sm, address: address, port: port}
+}
+
+// socketTransport gắn SocketManager với một endpoint cụ thể
+type socketTransport struct {
+	manager *SocketManager
+	address string
+	port    string
+}
+
+func (t *socketTransport) Send(data string) error {
+	return t.manager.Send(t.address, t.port, data)
+}
+
+func (t *socketTransport) Kind() string {
+	return KindTCP
+}
+
+func (t *socketTransport) Endpoint() string {
+	return ConnectionKey(t.address, t.port)
+}
+
+func (t *socketTransport) Subscribe(handler func(Frame)) func() {
+	endpoint := t.Endpoint()
+	return t.manager.hub.Subscribe(func(frame Frame) {
+		if frame.Endpoint == endpoint {
+			handler(frame)
+		}
+	})
+}
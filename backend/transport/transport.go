@@ -0,0 +1,21 @@
+package transport
+
+// Các loại đường truyền tới datalogger
+const (
+	KindSerial = "serial"
+	KindTCP    = "tcp"
+)
+
+// Transport là một đường truyền tới datalogger (cổng COM, socket TCP, ...).
+// Mỗi lệnh là một dòng JSON, Send tự thêm ký tự xuống dòng ở cuối.
+type Transport interface {
+	// Send gửi một dòng dữ liệu xuống thiết bị
+	Send(data string) error
+	// Kind trả về loại đường truyền (KindSerial, KindTCP)
+	Kind() string
+	// Endpoint trả về tên cổng hoặc địa chỉ đang kết nối
+	Endpoint() string
+	// Subscribe nhận các dòng dữ liệu thiết bị gửi về qua đường truyền này,
+	// trả về hàm hủy đăng ký
+	Subscribe(handler func(Frame)) func()
+}
@@ -1,54 +1,35 @@
 package workspace
 
 import (
+	"bytes"
 	"context"
 	_ "embed" // để nhúng file JSON mẫu
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"myproject/backend/auth"
-	"net"
+	"myproject/backend/config"
+	"myproject/backend/device"
+	"myproject/backend/modbus"
+	"myproject/backend/script"
+	"myproject/backend/transport"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
-	"sync"
 	"time"
 )
 
 //go:embed test.json
 var testTemplate []byte
 
-// SocketConnection quản lý kết nối socket
-type SocketConnection struct {
-	conn      net.Conn
-	isActive  bool
-	mutex     sync.Mutex
-	dataChain chan string
-	buffer    string
-}
-
-// SocketManager quản lý các kết nối socket
-type SocketManager struct {
-	connections map[string]*SocketConnection
-	mutex       sync.RWMutex
-}
-
-// NewSocketManager tạo mới socket manager
-func NewSocketManager() *SocketManager {
-	return &SocketManager{
-		connections: make(map[string]*SocketConnection),
-	}
-}
-
 type WorkspaceService struct {
 	basePath      string
 	ctx           context.Context
 	clipboard     *Clipboard
-	authService   *auth.AuthService
-	socketManager *SocketManager
+	device        *device.DeviceService
+	socketManager *transport.SocketManager
 }
 
 type FileNode struct {
@@ -71,11 +52,11 @@ type Clipboard struct {
 	Action     ClipboardAction
 }
 
-func NewWorkspaceService(authService *auth.AuthService) *WorkspaceService {
+func NewWorkspaceService(deviceService *device.DeviceService, socketManager *transport.SocketManager) *WorkspaceService {
 	return &WorkspaceService{
-		authService:   authService,
+		device:        deviceService,
 		basePath:      "./workspace",
-		socketManager: NewSocketManager(),
+		socketManager: socketManager,
 	}
 }
 
@@ -163,6 +144,11 @@ func (ws *WorkspaceService) ReadFile(relPath string) (string, error) {
 	return string(data), nil
 }
 
+// DefaultTemplate trả về nội dung file cấu hình mẫu test.json được nhúng vào ứng dụng
+func DefaultTemplate() []byte {
+	return append([]byte(nil), testTemplate...)
+}
+
 func (ws *WorkspaceService) GetDefaultData() (string, error) {
 	if len(testTemplate) == 0 {
 		return "", fmt.Errorf("Template mặc định trống hoặc không được embed")
@@ -540,809 +526,497 @@ func (ws *WorkspaceService) NewProject(name string) error {
 	return nil
 }
 
-func (ws *WorkspaceService) DownloadConfig() error {
-	return ws.authService.Send(`{"type":"download_config"}`)
+// ValidateConfig kiểm tra cấu hình có kiểu, trả về danh sách lỗi và cảnh báo theo JSON pointer
+func (ws *WorkspaceService) ValidateConfig(cfg *config.Config) []config.Issue {
+	return config.Validate(cfg)
 }
 
-func (ws *WorkspaceService) UploadConfig(data string) error {
-	var configData map[string]interface{}
-	if err := json.Unmarshal([]byte(data), &configData); err != nil {
-		return fmt.Errorf("dữ liệu upload không phải JSON hợp lệ: %w", err)
-	}
-
-	// Thêm trường type
-	configData["type"] = "upload_config"
+// ValidateConfigData kiểm tra nội dung JSON đang mở trong editor
+func (ws *WorkspaceService) ValidateConfigData(data string) ([]config.Issue, error) {
+	return config.ValidateData([]byte(data))
+}
 
-	finalData, err := json.Marshal(configData)
-	if err != nil {
-		return fmt.Errorf("lỗi khi chuyển thành JSON: %w", err)
-	}
+// LintScript kiểm tra code của một prog/timer: lỗi cú pháp, hàm không tồn tại,
+// chỉ số ngoài phạm vi và nhánh không bao giờ chạy, theo giới hạn của firmware hiện tại
+func (ws *WorkspaceService) LintScript(code string) []script.Diagnostic {
+	return script.Lint(code, config.ScriptOptions(nil))
+}
 
-	return ws.authService.Send(string(finalData))
+// SimulateScript chạy thử code của một prog/timer trên datalogger ảo với các thanh ghi đặt sẵn,
+// trả về những thanh ghi, DO và timer đã thay đổi
+func (ws *WorkspaceService) SimulateScript(code string, memory map[int]float64) (*script.Result, error) {
+	return script.Simulate(code, memory, config.ScriptOptions(nil))
 }
 
-func copyFile(src, dst string) (int64, error) {
-	sourceFile, err := os.Open(src)
+// SimulateConfigFile chạy thử toàn bộ prog/timers của một file cấu hình trong workspace
+func (ws *WorkspaceService) SimulateConfigFile(relPath string, input config.SimulationInput) (*script.Result, error) {
+	cfg, err := ws.ReadConfigFile(relPath)
 	if err != nil {
-		return 0, fmt.Errorf("không thể mở file nguồn '%s': %w", src, err)
+		return nil, err
 	}
-	defer sourceFile.Close()
+	return config.Simulate(cfg, input)
+}
 
-	destFile, err := os.Create(dst)
+// SimulateConfigData giống SimulateConfigFile cho nội dung JSON đang mở trong editor
+func (ws *WorkspaceService) SimulateConfigData(data string, input config.SimulationInput) (*script.Result, error) {
+	cfg, err := config.Parse([]byte(data))
 	if err != nil {
-		return 0, fmt.Errorf("không thể tạo file đích '%s': %w", dst, err)
+		return nil, err
 	}
-	defer destFile.Close()
+	return config.Simulate(cfg, input)
+}
 
-	nBytes, err := io.Copy(destFile, sourceFile)
+// RunScriptTests chạy các test case trong file <config>.tests.json cạnh file cấu hình
+func (ws *WorkspaceService) RunScriptTests(relPath string) (*config.ScriptTestReport, error) {
+	cfg, err := ws.ReadConfigFile(relPath)
 	if err != nil {
-		return 0, fmt.Errorf("lỗi khi sao chép nội dung từ '%s' sang '%s': %w", src, dst, err)
+		return nil, err
 	}
-	return nBytes, nil
+	return ws.runScriptTests(relPath, cfg)
 }
 
-// ExportJSONFile xuất một file JSON từ workspace ra một đường dẫn đích.
-// jsonFileName là tên của file JSON (ví dụ: "config.json") trong workspace.
-// destinationFolder là đường dẫn tuyệt đối đến thư mục đích bên ngoài workspace.
-func (ws *WorkspaceService) ExportJSONFile(jsonFileName string, destinationPath string) error {
-	// Lấy đường dẫn tuyệt đối của workspace
-	absWorkspacePath, err := ws.GetWorkspacePath()
+// RunScriptTestsData giống RunScriptTests nhưng dùng nội dung JSON đang mở trong editor
+// thay cho file cấu hình đã lưu, để kiểm tra ngay khi sửa
+func (ws *WorkspaceService) RunScriptTestsData(relPath, data string) (*config.ScriptTestReport, error) {
+	cfg, err := config.Parse([]byte(data))
 	if err != nil {
-		return fmt.Errorf("không thể lấy đường dẫn workspace: %w", err)
+		return nil, err
 	}
+	return ws.runScriptTests(relPath, cfg)
+}
 
-	// Tạo đường dẫn đầy đủ tới file nguồn trong workspace
-	sourceFullPath := filepath.Join(absWorkspacePath, jsonFileName)
-
-	// Kiểm tra file nguồn có tồn tại không
-	info, err := os.Stat(sourceFullPath)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("file JSON '%s' không tồn tại trong workspace", jsonFileName)
-	} else if err != nil {
-		return fmt.Errorf("lỗi khi kiểm tra file nguồn: %w", err)
+func (ws *WorkspaceService) runScriptTests(relPath string, cfg *config.Config) (*config.ScriptTestReport, error) {
+	testsPath := config.ScriptTestsPath(relPath)
+	data, err := os.ReadFile(filepath.Join(ws.basePath, testsPath))
+	if err != nil {
+		return nil, fmt.Errorf("không đọc được file test %s: %w", testsPath, err)
 	}
-
-	if info.IsDir() {
-		return fmt.Errorf("'%s' là thư mục, không phải file JSON", jsonFileName)
+	tests, err := config.ParseScriptTests(data)
+	if err != nil {
+		return nil, err
 	}
+	return config.RunScriptTests(cfg, tests), nil
+}
 
-	// Kiểm tra phần mở rộng .json của file đích
-	if !strings.HasSuffix(strings.ToLower(destinationPath), ".json") {
-		return fmt.Errorf("đường dẫn đích '%s' không có phần mở rộng .json", destinationPath)
+// ReadSymbols đọc bảng ký hiệu <config>.symbols.json cạnh file cấu hình, trả về bảng rỗng nếu chưa có
+func (ws *WorkspaceService) ReadSymbols(relPath string) (*script.SymbolTable, error) {
+	data, err := os.ReadFile(filepath.Join(ws.basePath, config.SymbolsPath(relPath)))
+	if errors.Is(err, os.ErrNotExist) {
+		return &script.SymbolTable{Symbols: []script.Symbol{}}, nil
 	}
-
-	// Sao chép file từ sourceFullPath sang destinationPath
-	fmt.Printf("Đang xuất file JSON từ '%s' đến '%s'...\n", sourceFullPath, destinationPath)
-	_, err = copyFile(sourceFullPath, destinationPath)
 	if err != nil {
-		return fmt.Errorf("lỗi khi sao chép file: %w", err)
+		return nil, err
 	}
-
-	fmt.Println("✅ Xuất file thành công.")
-	return nil
+	return config.ParseSymbols(data)
 }
 
-func (ws *WorkspaceService) DeleteItem(itemName string) error {
-	// Lấy đường dẫn tuyệt đối của workspace
-	absPath, err := ws.GetWorkspacePath()
+// SaveSymbols kiểm tra rồi ghi bảng ký hiệu cạnh file cấu hình
+func (ws *WorkspaceService) SaveSymbols(relPath string, table *script.SymbolTable) error {
+	if err := table.Check(config.MemorySize); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(table, "", "  ")
 	if err != nil {
-		return fmt.Errorf("không thể lấy đường dẫn workspace: %w", err)
+		return fmt.Errorf("lỗi khi chuyển bảng ký hiệu thành JSON: %w", err)
 	}
+	return os.WriteFile(filepath.Join(ws.basePath, config.SymbolsPath(relPath)), data, 0644)
+}
 
-	// Xây dựng đường dẫn đầy đủ của mục cần xóa
-	itemFullPath := filepath.Join(absPath, itemName)
-
-	// Kiểm tra xem mục có tồn tại không
-	info, err := os.Stat(itemFullPath)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("mục '%s' không tồn tại trong workspace để xóa", itemName)
-	} else if err != nil {
-		return fmt.Errorf("lỗi khi kiểm tra mục '%s': %w", itemName, err)
+// CompileScript thay tên thanh ghi trong code bằng chỉ số theo bảng ký hiệu của file cấu hình
+func (ws *WorkspaceService) CompileScript(relPath, code string) (*script.CompileResult, error) {
+	table, err := ws.ReadSymbols(relPath)
+	if err != nil {
+		return nil, err
 	}
+	return script.Compile(code, table), nil
+}
 
-	// Thực hiện xóa
-	err = os.RemoveAll(itemFullPath)
+// CompileStructuredScript biên dịch code có cấu trúc (if/else, switch, const) của một prog/timer
+// thành code ba ngôi cho thiết bị, dùng bảng ký hiệu của file cấu hình cho tên thanh ghi
+func (ws *WorkspaceService) CompileStructuredScript(relPath, code string) (*script.StructuredResult, error) {
+	table, err := ws.ReadSymbols(relPath)
 	if err != nil {
-		// Kiểm tra lại lỗi nếu có vấn đề về quyền truy cập
-		if os.IsPermission(err) {
-			return fmt.Errorf("không có quyền xóa '%s': %w", itemName, err)
-		}
-		return fmt.Errorf("không thể xóa '%s': %w", itemName, err)
+		return nil, err
 	}
+	return script.CompileStructured(code, table, config.ScriptOptions(nil)), nil
+}
 
-	if info.IsDir() {
-		fmt.Printf("✅ Đã xóa thư mục và tất cả nội dung bên trong: '%s'\n", itemName)
-	} else {
-		fmt.Printf("✅ Đã xóa file: '%s'\n", itemName)
+// DecompileScript thay chỉ số thanh ghi trong code bằng tên theo bảng ký hiệu của file cấu hình
+func (ws *WorkspaceService) DecompileScript(relPath, code string) (string, error) {
+	table, err := ws.ReadSymbols(relPath)
+	if err != nil {
+		return "", err
 	}
-
-	return nil
+	return script.Decompile(code, table), nil
 }
 
-func (ws *WorkspaceService) SaveJsonToPath(jsonData string, fullPath string) error {
-	// Optional: kiểm tra đuôi .json
-	if !strings.HasSuffix(fullPath, ".json") {
-		fullPath += ".json"
+// CompileConfigData thay tên thanh ghi trong mọi prog/timers của nội dung JSON đang mở,
+// trả về JSON dạng số để upload. Tên không có trong bảng trả về *config.ValidationError.
+func (ws *WorkspaceService) CompileConfigData(relPath, data string) (string, error) {
+	table, err := ws.ReadSymbols(relPath)
+	if err != nil {
+		return "", err
 	}
-
-	// Optional: validate JSON
-	var js map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonData), &js); err != nil {
-		return fmt.Errorf("JSON không hợp lệ: %w", err)
+	cfg, err := config.Parse([]byte(data))
+	if err != nil {
+		return "", err
 	}
-
-	// Đảm bảo thư mục cha tồn tại
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("Không thể tạo thư mục cha: %w", err)
+	if issues := config.CompileScripts(cfg, table); len(issues) > 0 {
+		return "", &config.ValidationError{Issues: issues}
 	}
-
-	// Ghi file
-	err := os.WriteFile(fullPath, []byte(jsonData), 0644)
+	out, err := json.Marshal(cfg)
 	if err != nil {
-		return fmt.Errorf("Không thể ghi dữ liệu vào file: %w", err)
+		return "", fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
 	}
-
-	fmt.Printf("✅ Đã lưu JSON vào: %s\n", fullPath)
-	return nil
+	return string(out), nil
 }
 
-// ConnectSocket kết nối tới socket server tại địa chỉ và port được chỉ định
-func (ws *WorkspaceService) ConnectSocket(address string, port string) (string, error) {
-	// Tạo key duy nhất cho connection
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.Lock()
-	defer ws.socketManager.mutex.Unlock()
-
-	// Kiểm tra xem đã có kết nối nào tồn tại chưa
-	if conn, exists := ws.socketManager.connections[connectionKey]; exists {
-		if conn.isActive {
-			return fmt.Sprintf("Đã có kết nối tới %s", connectionKey), nil
-		}
-		// Nếu connection cũ không active, xóa nó
-		delete(ws.socketManager.connections, connectionKey)
-	}
-
-	// Tạo kết nối mới
-	fullAddress := fmt.Sprintf("%s:%s", address, port)
-	conn, err := net.DialTimeout("tcp", fullAddress, 10*time.Second)
+// DecompileConfigData thay chỉ số thanh ghi bằng tên trong mọi prog/timers, ví dụ sau khi đọc cấu hình từ thiết bị
+func (ws *WorkspaceService) DecompileConfigData(relPath, data string) (string, error) {
+	table, err := ws.ReadSymbols(relPath)
 	if err != nil {
-		return "", fmt.Errorf("không thể kết nối tới %s: %w", fullAddress, err)
+		return "", err
 	}
-
-	// Tạo socket connection object với buffer nhỏ hơn cho real-time
-	socketConn := &SocketConnection{
-		conn:      conn,
-		isActive:  true,
-		dataChain: make(chan string, 100), // Giảm buffer để tránh delay
+	cfg, err := config.Parse([]byte(data))
+	if err != nil {
+		return "", err
 	}
-
-	// Lưu connection
-	ws.socketManager.connections[connectionKey] = socketConn
-
-	// Bắt đầu goroutine để đọc dữ liệu
-	go ws.readSocketData(connectionKey, socketConn)
-
-	fmt.Printf("✅ Đã kết nối thành công tới socket: %s\n", fullAddress)
-	return fmt.Sprintf("Kết nối thành công tới %s", fullAddress), nil
-}
-
-// readSocketData đọc dữ liệu từ socket connection - ULTRA FAST
-func (ws *WorkspaceService) readSocketData(connectionKey string, socketConn *SocketConnection) {
-	defer func() {
-		socketConn.mutex.Lock()
-		socketConn.isActive = false
-		socketConn.conn.Close()
-		close(socketConn.dataChain)
-		socketConn.mutex.Unlock()
-
-		ws.socketManager.mutex.Lock()
-		delete(ws.socketManager.connections, connectionKey)
-		ws.socketManager.mutex.Unlock()
-	}()
-
-	buffer := make([]byte, 4096) // Giảm buffer để responsive hơn
-
-	for {
-		socketConn.mutex.Lock()
-		if !socketConn.isActive {
-			socketConn.mutex.Unlock()
-			break
-		}
-		socketConn.mutex.Unlock()
-
-		// Timeout cực ngắn để real-time
-		socketConn.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
-
-		n, err := socketConn.conn.Read(buffer)
-		if err != nil {
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				continue // Timeout ngắn - tiếp tục ngay
-			}
-			break // Lỗi khác - thoát
-		}
-
-		if n > 0 {
-			data := string(buffer[:n])
-
-			// Push data ngay lập tức - NO BLOCKING
-			select {
-			case socketConn.dataChain <- data:
-			default:
-				// Channel đầy - clear hết và push data mới
-				for len(socketConn.dataChain) > 0 {
-					<-socketConn.dataChain
-				}
-				socketConn.dataChain <- data
-			}
-		}
+	config.DecompileScripts(cfg, table)
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("lỗi khi chuyển cấu hình thành JSON: %w", err)
 	}
+	return string(out), nil
 }
 
-// GetSocketData lấy dữ liệu mới nhất từ socket - optimized
-func (ws *WorkspaceService) GetSocketData(address string, port string) (string, error) {
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.RLock()
-	socketConn, exists := ws.socketManager.connections[connectionKey]
-	ws.socketManager.mutex.RUnlock()
-
-	if !exists {
-		return "", fmt.Errorf("không có kết nối tới %s", connectionKey)
+// ReadModbusReader đọc thử một mục modbus_reader qua Modbus TCP từ máy tính, trả về thanh ghi thô
+// và giá trị đã giải mã theo d_t/d_o/d_f như datalogger. timeoutMs <= 0 dùng 1 giây.
+func (ws *WorkspaceService) ReadModbusReader(entry config.ModbusReader, timeoutMs int) (*modbus.ReadResult, error) {
+	if entry.Type == 1 {
+		return nil, fmt.Errorf("mục này đọc qua Modbus RTU (type 1), hãy dùng Poll RTU trong rtu_master")
 	}
-
-	// Kiểm tra active status nhanh hơn
-	socketConn.mutex.Lock()
-	isActive := socketConn.isActive
-	socketConn.mutex.Unlock()
-
-	if !isActive {
-		return "", fmt.Errorf("kết nối tới %s không còn hoạt động", connectionKey)
-	}
-
-	// Lấy data ngay lập tức (non-blocking)
-	select {
-	case data := <-socketConn.dataChain:
-		return strings.TrimSpace(data), nil
-	default:
-		return "", nil // Trả về empty thay vì error để tránh spam log
+	if entry.DevA == "" {
+		return nil, fmt.Errorf("chưa nhập địa chỉ thiết bị dev_a")
 	}
+	return modbus.ReadTCP(entry.DevA, modbusQuery(entry), modbusTimeout(timeoutMs))
 }
 
-// GetAllSocketData lấy tất cả dữ liệu có sẵn từ socket - ULTRA FAST
-func (ws *WorkspaceService) GetAllSocketData(address string, port string) ([]string, error) {
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.RLock()
-	socketConn, exists := ws.socketManager.connections[connectionKey]
-	ws.socketManager.mutex.RUnlock()
-
-	if !exists {
-		return []string{}, nil // Return empty thay vì error
+// PollModbusRTU mở cổng nối tiếp portName của máy tính với thông số rtu_master của nội dung JSON đang mở
+// và đọc một lượt mọi mục modbus_reader type 1 (RTU), báo timeout, lỗi CRC và exception của từng mục
+func (ws *WorkspaceService) PollModbusRTU(portName, data string) (*modbus.PollReport, error) {
+	cfg, err := config.Parse([]byte(data))
+	if err != nil {
+		return nil, err
 	}
-
-	socketConn.mutex.Lock()
-	if !socketConn.isActive {
-		socketConn.mutex.Unlock()
-		return []string{}, nil
+	if cfg.RTUMaster == nil {
+		return nil, fmt.Errorf("cấu hình chưa có rtu_master")
 	}
-
-	// Lấy buffer hiện tại và clear ngay
-	buffer := socketConn.buffer
-	socketConn.buffer = ""
-	socketConn.mutex.Unlock()
-
-	var results []string
-
-	// Đọc TẤT CẢ data từ channel trong 1 pass
-	for len(socketConn.dataChain) > 0 {
-		select {
-		case chunk := <-socketConn.dataChain:
-			buffer += chunk
-		default:
-			break
+	var targets []modbus.PollTarget
+	for i, entry := range cfg.ModbusReader {
+		if entry.Type != 1 {
+			continue
 		}
-	}
-
-	// Nếu có data thì xử lý, nếu không thì return ngay
-	if buffer == "" {
-		return []string{}, nil
-	}
-
-	// Split nhanh và return luôn
-	lines := strings.Split(buffer, "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" {
-			results = append(results, trimmed)
+		name := fmt.Sprintf("modbus_reader[%d]", i)
+		if entry.Desc != "" {
+			name += " " + entry.Desc
 		}
+		if !entry.En {
+			name += " (đang tắt)"
+		}
+		targets = append(targets, modbus.PollTarget{Name: name, Query: modbusQuery(entry)})
 	}
-
-	return results, nil
-}
-
-// SendSocketData gửi dữ liệu tới socket
-func (ws *WorkspaceService) SendSocketData(address string, port string, data string) error {
-
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.RLock()
-	socketConn, exists := ws.socketManager.connections[connectionKey]
-	ws.socketManager.mutex.RUnlock()
-
-	if !exists {
-		return fmt.Errorf("không có kết nối tới %s", connectionKey)
-	}
-
-	socketConn.mutex.Lock()
-	defer socketConn.mutex.Unlock()
-
-	if !socketConn.isActive {
-		return fmt.Errorf("kết nối tới %s không còn hoạt động", connectionKey)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("không có mục modbus_reader nào đọc qua RTU (type 1)")
 	}
 
-	// Set timeout cho việc ghi
-	socketConn.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-
-	// 1. Chuyển đổi chuỗi dữ liệu gốc thành byte array (thường là UTF-8)
-	dataBytes := []byte(data)
-
-	// 2. Thêm byte 0x0A (Line Feed) vào cuối mảng byte
-	// Đây chính là "thêm 0a dưới dạng HEX" vào cuối chuỗi dữ liệu ban đầu.
-	finalData := append(dataBytes, 0x0A)
-
-	_, err := socketConn.conn.Write([]byte(finalData))
+	master := cfg.RTUMaster
+	client, err := modbus.OpenRTU(portName, modbus.RTUSettings{
+		BaudRate: master.BaudRate,
+		Parity:   master.Parity,
+		StopBits: master.StopBits,
+		Wait:     master.Wait,
+		Retry:    master.Retry,
+		Delay:    master.Delay,
+	})
 	if err != nil {
-		return fmt.Errorf("không thể gửi dữ liệu tới socket %s: %w", connectionKey, err)
+		return nil, err
 	}
-
-	fmt.Printf("📤 Đã gửi dữ liệu tới socket %s: %s\n", connectionKey, data)
-	return nil
+	defer client.Close()
+	return modbus.Poll(client, targets), nil
 }
 
-// func (ws *WorkspaceService) SendSocketData(address string, port string, data string) error {
-// 	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-// 	ws.socketManager.mutex.RLock()
-// 	socketConn, exists := ws.socketManager.connections[connectionKey]
-// 	ws.socketManager.mutex.RUnlock()
-
-// 	if !exists {
-// 		return fmt.Errorf("không có kết nối tới %s", connectionKey)
-// 	}
-
-// 	socketConn.mutex.Lock()
-// 	defer socketConn.mutex.Unlock()
-
-// 	if !socketConn.isActive {
-// 		return fmt.Errorf("kết nối tới %s không còn hoạt động", connectionKey)
-// 	}
-
-// 	// Set timeout cho việc ghi
-// 	socketConn.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-
-// 	// 1. Gửi chuỗi dữ liệu gốc
-// 	dataBytes := []byte(data)
-// 	_, err := socketConn.conn.Write(dataBytes)
-// 	if err != nil {
-// 		return fmt.Errorf("không thể gửi dữ liệu chính tới socket %s: %w", connectionKey, err)
-// 	}
-
-// 	// 2. Gửi byte 0x0A riêng lẻ
-// 	_, err = socketConn.conn.Write([]byte{0x0A})
-// 	if err != nil {
-// 		return fmt.Errorf("không thể gửi ký tự kết thúc tới socket %s: %w", connectionKey, err)
-// 	}
-
-// 	fmt.Printf("📤 Đã gửi tới socket %s: %s\\n (chia 2 lần)\n", connectionKey, data)
-// 	return nil
-// }
-
-func (ws *WorkspaceService) Login(address, port, username, password string) error {
-	// Tạo message JSON đúng format thiết bị yêu cầu
-	loginMessage := fmt.Sprintf(`{"type":"login","username":"%s","password":"%s"}`, username, password)
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, loginMessage)
-	if err != nil {
-		return fmt.Errorf("không thể gửi login request: %w", err)
+// modbusQuery đổi một mục modbus_reader thành yêu cầu đọc
+func modbusQuery(entry config.ModbusReader) modbus.Query {
+	return modbus.Query{
+		Unit:     entry.ID,
+		DataType: modbus.DataType(entry.DT),
+		Address:  entry.RegA,
+		Count:    entry.NObj,
+		Format:   modbus.Format(entry.DF),
+		Order:    modbus.ByteOrder(entry.DO),
 	}
-
-	fmt.Println("✅ Đã gửi login request tới thiết bị.")
-	return nil
 }
 
-func (ws *WorkspaceService) Logout(address, port string) error {
-	// Tạo message JSON cho logout
-	logoutMessage := `{"type":"logout"}`
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, logoutMessage)
-	if err != nil {
-		return fmt.Errorf("không thể gửi logout request: %w", err)
+func modbusTimeout(timeoutMs int) time.Duration {
+	if timeoutMs <= 0 {
+		return time.Second
 	}
-
-	fmt.Println("✅ Đã gửi logout request tới thiết bị.")
-	return nil
+	return time.Duration(timeoutMs) * time.Millisecond
 }
 
-func (ws *WorkspaceService) ChangePassword(address, port, oldPassword, newPassword string) error {
-	// Tạo message JSON cho đổi mật khẩu
-	changePasswordMessage := fmt.Sprintf(
-		`{"type":"change_password","old_password":"%s","new_password":"%s"}`,
-		oldPassword, newPassword,
-	)
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, changePasswordMessage)
+// AnalyzeMemoryFile xây bản đồ sử dụng bộ nhớ và tìm xung đột của một file cấu hình trong workspace
+func (ws *WorkspaceService) AnalyzeMemoryFile(relPath string) (*config.MemoryMap, error) {
+	cfg, err := ws.ReadConfigFile(relPath)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu đổi mật khẩu: %w", err)
+		return nil, err
 	}
-
-	fmt.Println("✅ Đã gửi yêu cầu đổi mật khẩu tới thiết bị.")
-	return nil
+	return config.AnalyzeMemory(cfg), nil
 }
 
-func (ws *WorkspaceService) DownloadConfigEthernet(address, port string) error {
-	// Tạo message JSON cho yêu cầu tải cấu hình
-	message := `{"type":"download_config"}`
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, message)
+// AnalyzeMemoryData giống AnalyzeMemoryFile cho nội dung JSON đang mở trong editor
+func (ws *WorkspaceService) AnalyzeMemoryData(data string) (*config.MemoryMap, error) {
+	cfg, err := config.Parse([]byte(data))
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu download config: %w", err)
+		return nil, err
 	}
-
-	fmt.Println("✅ Đã gửi yêu cầu download config tới thiết bị.")
-	return nil
+	return config.AnalyzeMemory(cfg), nil
 }
 
-func (ws *WorkspaceService) ReadAnalog(address, port, mode string) error {
-	// Đảm bảo mode chỉ nhận "enable" hoặc "disable"
-	if mode != "enable" && mode != "disable" {
-		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'enable' hoặc 'disable')", mode)
+// DiffDeviceConfig so sánh cấu hình trên thiết bị (qua kết nối đang chọn của DeviceService)
+// với file trong workspace: kết quả là những gì sẽ thay đổi nếu upload file đó
+func (ws *WorkspaceService) DiffDeviceConfig(relPath string) (*config.Diff, error) {
+	local, err := ws.ReadFile(relPath)
+	if err != nil {
+		return nil, err
 	}
-
-	// Tạo message JSON
-	message := fmt.Sprintf(`{"type":"read_analog","data":"%s"}`, mode)
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, message)
+	remote, err := ws.device.DownloadConfig()
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu read_analog: %w", err)
+		return nil, err
 	}
-
-	fmt.Printf("✅ Đã gửi read_analog [%s] tới thiết bị.\n", mode)
-	return nil
+	return config.CompareData([]byte(remote), []byte(local))
 }
 
-func (ws *WorkspaceService) ReadMemoryView(address, port, mode string) error {
-	// Chỉ chấp nhận "enable" hoặc "disable"
-	if mode != "enable" && mode != "disable" {
-		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'enable' hoặc 'disable')", mode)
+// DiffConfigFiles so sánh hai file cấu hình trong workspace, từ fromPath sang toPath
+func (ws *WorkspaceService) DiffConfigFiles(fromPath, toPath string) (*config.Diff, error) {
+	from, err := ws.ReadFile(fromPath)
+	if err != nil {
+		return nil, err
 	}
-
-	// Tạo message JSON
-	message := fmt.Sprintf(`{"type":"read_memory_view","data":"%s"}`, mode)
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, message)
+	to, err := ws.ReadFile(toPath)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu read_memory_view: %w", err)
+		return nil, err
 	}
-
-	fmt.Printf("✅ Đã gửi read_memory_view [%s] tới thiết bị.\n", mode)
-	return nil
+	return config.CompareData([]byte(from), []byte(to))
 }
 
-func (ws *WorkspaceService) ReadTagView(address, port, mode string) error {
-	// Kiểm tra mode hợp lệ
-	if mode != "enable" && mode != "disable" {
-		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'enable' hoặc 'disable')", mode)
-	}
-
-	// Tạo JSON message
-	message := fmt.Sprintf(`{"type":"read_tag_view","data":"%s"}`, mode)
-
-	// Gửi dữ liệu
-	err := ws.SendSocketData(address, port, message)
+// ReadConfigFile đọc file cấu hình trong workspace thành model có kiểu
+func (ws *WorkspaceService) ReadConfigFile(relPath string) (*config.Config, error) {
+	data, err := ws.ReadFile(relPath)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu read_tag_view: %w", err)
+		return nil, err
 	}
-
-	fmt.Printf("✅ Đã gửi read_tag_view [%s] tới thiết bị.\n", mode)
-	return nil
+	return config.Parse([]byte(data))
 }
 
-func (ws *WorkspaceService) SetMeasureMode(address, port, mode string) error {
-	// Chỉ chấp nhận "current" hoặc "voltage"
-	if mode != "current" && mode != "voltage" {
-		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'current' hoặc 'voltage')", mode)
+// SaveConfigFile ghi cấu hình có kiểu ra file .json (cùng định dạng với SaveJsonFile)
+func (ws *WorkspaceService) SaveConfigFile(destinationPath string, cfg *config.Config) error {
+	if !strings.HasSuffix(destinationPath, ".json") {
+		return fmt.Errorf("chỉ chấp nhận lưu file .json")
 	}
 
-	// Tạo message JSON
-	message := fmt.Sprintf(`{"type":"set_measure_mode","mode":"%s"}`, mode)
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, message)
+	data, err := config.Format(cfg)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu set_measure_mode: %w", err)
+		return err
+	}
+	if _, err := OverwriteFile(destinationPath, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("không thể lưu file JSON: %w", err)
 	}
-
-	fmt.Printf("✅ Đã gửi set_measure_mode [%s] tới thiết bị.\n", mode)
 	return nil
 }
 
-func (ws *WorkspaceService) GetMeasureMode(address, port string) error {
-	message := `{"type":"get_measure_mode"}`
+// GetDefaultConfig trả về cấu hình mẫu test.json dạng model có kiểu
+func (ws *WorkspaceService) GetDefaultConfig() (*config.Config, error) {
+	return config.Parse(testTemplate)
+}
 
-	err := ws.SendSocketData(address, port, message)
+func copyFile(src, dst string) (int64, error) {
+	sourceFile, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu get_measure_mode: %w", err)
+		return 0, fmt.Errorf("không thể mở file nguồn '%s': %w", src, err)
 	}
+	defer sourceFile.Close()
 
-	fmt.Println("✅ Đã gửi get_measure_mode tới thiết bị.")
-	return nil
-}
-
-func (ws *WorkspaceService) GetRTC(address, port string) error {
-	message := `{"type":"get_rtc"}`
-
-	err := ws.SendSocketData(address, port, message)
+	destFile, err := os.Create(dst)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu get_rtc: %w", err)
+		return 0, fmt.Errorf("không thể tạo file đích '%s': %w", dst, err)
 	}
+	defer destFile.Close()
 
-	fmt.Println("✅ Đã gửi get_rtc tới thiết bị.")
-	return nil
-}
-
-func (ws *WorkspaceService) GetGps(address, port string) error {
-	message := `{"type":"get_gps"}`
-
-	err := ws.SendSocketData(address, port, message)
+	nBytes, err := io.Copy(destFile, sourceFile)
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu get_gps: %w", err)
+		return 0, fmt.Errorf("lỗi khi sao chép nội dung từ '%s' sang '%s': %w", src, dst, err)
 	}
-
-	fmt.Println("✅ Đã gửi get_gps tới thiết bị.")
-	return nil
+	return nBytes, nil
 }
 
-func (ws *WorkspaceService) SetRTC(address, port, mode string, ts int64) error {
-	// Chỉ chấp nhận "manual" hoặc "internet"
-	if mode != "manual" && mode != "internet" {
-		return fmt.Errorf("giá trị không hợp lệ cho mode: %s (chỉ 'manual' hoặc 'internet')", mode)
-	}
-
-	// Tạo message JSON
-	message := fmt.Sprintf(`{"type":"set_rtc", "mode":"%s", "ts":%d}`, mode, ts)
-
-	err := ws.SendSocketData(address, port, message)
+// ExportJSONFile xuất một file JSON từ workspace ra một đường dẫn đích.
+// jsonFileName là tên của file JSON (ví dụ: "config.json") trong workspace.
+// destinationFolder là đường dẫn tuyệt đối đến thư mục đích bên ngoài workspace.
+func (ws *WorkspaceService) ExportJSONFile(jsonFileName string, destinationPath string) error {
+	// Lấy đường dẫn tuyệt đối của workspace
+	absWorkspacePath, err := ws.GetWorkspacePath()
 	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu set_rtc: %w", err)
+		return fmt.Errorf("không thể lấy đường dẫn workspace: %w", err)
 	}
 
-	fmt.Printf("✅ Đã gửi set_rtc [%s, %d] tới thiết bị.\n", mode, ts)
-	return nil
-}
-
-func (ws *WorkspaceService) SettingNetworkEthernet(address, port string, data map[string]interface{}) error {
-	// Gắn type vào payload
-	data["type"] = "network_setting"
+	// Tạo đường dẫn đầy đủ tới file nguồn trong workspace
+	sourceFullPath := filepath.Join(absWorkspacePath, jsonFileName)
 
-	// Convert sang JSON
-	finalData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("lỗi khi chuyển JSON: %w", err)
+	// Kiểm tra file nguồn có tồn tại không
+	info, err := os.Stat(sourceFullPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("file JSON '%s' không tồn tại trong workspace", jsonFileName)
+	} else if err != nil {
+		return fmt.Errorf("lỗi khi kiểm tra file nguồn: %w", err)
 	}
 
-	// Gửi xuống thiết bị qua socket
-	err = ws.SendSocketData(address, port, string(finalData))
-	if err != nil {
-		return fmt.Errorf("không thể gửi network_setting qua ethernet: %w", err)
+	if info.IsDir() {
+		return fmt.Errorf("'%s' là thư mục, không phải file JSON", jsonFileName)
 	}
 
-	fmt.Println("✅ Đã gửi network_setting qua Ethernet.")
-	return nil
-}
-
-func (ws *WorkspaceService) QueryNetwork(address, port string) error {
-	// Tạo JSON yêu cầu thông tin mạng
-	message := `{"type":"network"}`
-
-	// Gửi xuống thiết bị qua socket
-	err := ws.SendSocketData(address, port, message)
-	if err != nil {
-		return fmt.Errorf("không thể gửi yêu cầu network: %w", err)
+	// Kiểm tra phần mở rộng .json của file đích
+	if !strings.HasSuffix(strings.ToLower(destinationPath), ".json") {
+		return fmt.Errorf("đường dẫn đích '%s' không có phần mở rộng .json", destinationPath)
 	}
 
-	fmt.Println("✅ Đã gửi yêu cầu lấy thông tin mạng tới thiết bị.")
-	return nil
-}
-
-func (ws *WorkspaceService) Calibrate4mA(address, port string) error {
-	message := `{"type":"calib_4ma"}`
-
-	err := ws.SendSocketData(address, port, message)
+	// Sao chép file từ sourceFullPath sang destinationPath
+	fmt.Printf("Đang xuất file JSON từ '%s' đến '%s'...\n", sourceFullPath, destinationPath)
+	_, err = copyFile(sourceFullPath, destinationPath)
 	if err != nil {
-		return fmt.Errorf("không thể gửi lệnh calib_4ma: %w", err)
+		return fmt.Errorf("lỗi khi sao chép file: %w", err)
 	}
 
+	fmt.Println("✅ Xuất file thành công.")
 	return nil
 }
 
-func (ws *WorkspaceService) Calibrate16mA(address, port string) error {
-	message := `{"type":"calib_16ma"}`
-
-	err := ws.SendSocketData(address, port, message)
+func (ws *WorkspaceService) DeleteItem(itemName string) error {
+	// Lấy đường dẫn tuyệt đối của workspace
+	absPath, err := ws.GetWorkspacePath()
 	if err != nil {
-		return fmt.Errorf("không thể gửi lệnh calib_16ma: %w", err)
-	}
-
-	return nil
-}
-
-func (ws *WorkspaceService) SetDigitalOutputEthernet(address, port string, outputStates []bool) error {
-	if len(outputStates) != 8 {
-		return fmt.Errorf("outputStates phải có đúng 8 phần tử")
+		return fmt.Errorf("không thể lấy đường dẫn workspace: %w", err)
 	}
 
-	// Chuyển []bool thành []int
-	intStates := make([]int, len(outputStates))
-	for i, v := range outputStates {
-		if v {
-			intStates[i] = 1
-		} else {
-			intStates[i] = 0
-		}
-	}
+	// Xây dựng đường dẫn đầy đủ của mục cần xóa
+	itemFullPath := filepath.Join(absPath, itemName)
 
-	// Tạo JSON message
-	message := map[string]interface{}{
-		"type": "set_digital_output",
-		"data": intStates,
+	// Kiểm tra xem mục có tồn tại không
+	info, err := os.Stat(itemFullPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("mục '%s' không tồn tại trong workspace để xóa", itemName)
+	} else if err != nil {
+		return fmt.Errorf("lỗi khi kiểm tra mục '%s': %w", itemName, err)
 	}
 
-	finalData, err := json.Marshal(message)
+	// Thực hiện xóa
+	err = os.RemoveAll(itemFullPath)
 	if err != nil {
-		return fmt.Errorf("marshal lỗi: %w", err)
+		// Kiểm tra lại lỗi nếu có vấn đề về quyền truy cập
+		if os.IsPermission(err) {
+			return fmt.Errorf("không có quyền xóa '%s': %w", itemName, err)
+		}
+		return fmt.Errorf("không thể xóa '%s': %w", itemName, err)
 	}
 
-	// Gửi qua socket
-	err = ws.SendSocketData(address, port, string(finalData))
-	if err != nil {
-		return fmt.Errorf("không thể gửi set_digital_output: %w", err)
+	if info.IsDir() {
+		fmt.Printf("✅ Đã xóa thư mục và tất cả nội dung bên trong: '%s'\n", itemName)
+	} else {
+		fmt.Printf("✅ Đã xóa file: '%s'\n", itemName)
 	}
 
-	fmt.Println("✅ Đã gửi set_digital_output tới thiết bị.")
 	return nil
 }
 
-func (ws *WorkspaceService) UploadConfigEthernet(address, port string, data string) error {
-	var configData map[string]interface{}
-	if err := json.Unmarshal([]byte(data), &configData); err != nil {
-		return fmt.Errorf("dữ liệu upload không phải JSON hợp lệ: %w", err)
+func (ws *WorkspaceService) SaveJsonToPath(jsonData string, fullPath string) error {
+	// Optional: kiểm tra đuôi .json
+	if !strings.HasSuffix(fullPath, ".json") {
+		fullPath += ".json"
 	}
 
-	// Thêm trường type
-	configData["type"] = "upload_config"
+	// Optional: validate JSON
+	var js map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonData), &js); err != nil {
+		return fmt.Errorf("JSON không hợp lệ: %w", err)
+	}
 
-	finalData, err := json.Marshal(configData)
-	if err != nil {
-		return fmt.Errorf("lỗi khi chuyển thành JSON: %w", err)
+	// Đảm bảo thư mục cha tồn tại
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Không thể tạo thư mục cha: %w", err)
 	}
 
-	// Gửi dữ liệu qua socket
-	err = ws.SendSocketData(address, port, string(finalData))
+	// Ghi file
+	err := os.WriteFile(fullPath, []byte(jsonData), 0644)
 	if err != nil {
-		return fmt.Errorf("không thể gửi upload_config tới thiết bị: %w", err)
+		return fmt.Errorf("Không thể ghi dữ liệu vào file: %w", err)
 	}
 
-	fmt.Println("✅ Đã gửi upload_config tới thiết bị.")
+	fmt.Printf("✅ Đã lưu JSON vào: %s\n", fullPath)
 	return nil
 }
 
-func (ws *WorkspaceService) WriteSerialNumber(address, port, serial string) error {
-	message := fmt.Sprintf(`{"type":"write_serial_number","data":"%s"}`, serial)
-	return ws.SendSocketData(address, port, message)
+// ConnectSocket kết nối tới socket server tại địa chỉ và port được chỉ định
+func (ws *WorkspaceService) ConnectSocket(address string, port string) (string, error) {
+	return ws.socketManager.Connect(address, port)
 }
 
-func (ws *WorkspaceService) WriteMacAddress(address, port, mac string) error {
-	message := fmt.Sprintf(`{"type":"write_mac","data":"%s"}`, mac)
-	return ws.SendSocketData(address, port, message)
+// SendSocketData gửi dữ liệu tới socket
+func (ws *WorkspaceService) SendSocketData(address string, port string, data string) error {
+	return ws.socketManager.Send(address, port, data)
 }
 
-func (ws *WorkspaceService) ResetConfiguration(address, port string) error {
-	message := `{"type":"reset_configuration"}`
-	return ws.SendSocketData(address, port, message)
+// DisconnectSocket ngắt kết nối socket
+func (ws *WorkspaceService) DisconnectSocket(address string, port string) error {
+	_ = device.NewClient(ws.socketManager.Transport(address, port)).SendLogout()
+	return ws.socketManager.Disconnect(address, port)
 }
 
-func (ws *WorkspaceService) RebootDevice(address, port string) error {
-	message := `{"type":"reboot"}`
-	return ws.SendSocketData(address, port, message)
+// ListActiveConnections liệt kê tất cả kết nối đang hoạt động
+func (ws *WorkspaceService) ListActiveConnections() []string {
+	return ws.socketManager.ListActive()
 }
 
-func (ws *WorkspaceService) ReadSystemInfo(address, port string) error {
-	message := `{"type":"read_system_info"}`
-	return ws.SendSocketData(address, port, message)
+// CheckSocketConnection kiểm tra trạng thái kết nối socket
+func (ws *WorkspaceService) CheckSocketConnection(address string, port string) bool {
+	return ws.socketManager.IsActive(address, port)
 }
 
-func (ws *WorkspaceService) ReadSimInfo(address, port string) error {
-	message := `{"type":"read_sim_info"}`
-	return ws.SendSocketData(address, port, message)
+// GetSocketStatus trả về sức khỏe kết nối socket (trạng thái, số lần kết nối lại, luồng đang bật...)
+func (ws *WorkspaceService) GetSocketStatus(address string, port string) (transport.SocketStatus, error) {
+	return ws.socketManager.Status(address, port)
 }
 
-func (ws *WorkspaceService) ReadSdCardInfo(address, port string) error {
-	message := `{"type":"read_sdcard_info"}`
-	return ws.SendSocketData(address, port, message)
+// ListSocketStatus trả về sức khỏe của mọi kết nối socket, kể cả kết nối đang kết nối lại
+func (ws *WorkspaceService) ListSocketStatus() []transport.SocketStatus {
+	return ws.socketManager.ListStatus()
 }
 
-func (ws *WorkspaceService) PingDevice(address, port, targetIP string) error {
-	message := fmt.Sprintf(`{"type":"ping","data":"%s"}`, targetIP)
-	return ws.SendSocketData(address, port, message)
+// GetSocketReconnectPolicy trả về cấu hình keepalive và kết nối lại hiện tại
+func (ws *WorkspaceService) GetSocketReconnectPolicy() transport.ReconnectPolicy {
+	return ws.socketManager.ReconnectPolicy()
 }
 
-// DisconnectSocket ngắt kết nối socket
-func (ws *WorkspaceService) DisconnectSocket(address string, port string) error {
-	logoutSignal := `{"type":"logout"}`
-	_ = ws.SendSocketData(address, port, logoutSignal)
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.Lock()
-	socketConn, exists := ws.socketManager.connections[connectionKey]
-	if !exists {
-		ws.socketManager.mutex.Unlock()
-		return fmt.Errorf("không có kết nối tới %s", connectionKey)
-	}
-
-	socketConn.mutex.Lock()
-	if socketConn.isActive {
-		socketConn.isActive = false
-		socketConn.conn.Close()
-	}
-	socketConn.mutex.Unlock()
-
-	delete(ws.socketManager.connections, connectionKey)
-	ws.socketManager.mutex.Unlock()
-
-	fmt.Printf("🔌 Đã ngắt kết nối socket: %s\n", connectionKey)
-	return nil
+// SetSocketReconnectPolicy đổi cấu hình keepalive và kết nối lại
+func (ws *WorkspaceService) SetSocketReconnectPolicy(policy transport.ReconnectPolicy) error {
+	return ws.socketManager.SetReconnectPolicy(policy)
 }
 
-// ListActiveConnections liệt kê tất cả kết nối đang hoạt động
-func (ws *WorkspaceService) ListActiveConnections() []string {
-	ws.socketManager.mutex.RLock()
-	defer ws.socketManager.mutex.RUnlock()
-
-	var activeConnections []string
-	for key, conn := range ws.socketManager.connections {
-		conn.mutex.Lock()
-		if conn.isActive {
-			activeConnections = append(activeConnections, key)
-		}
-		conn.mutex.Unlock()
-	}
-
-	return activeConnections
+// GetSocketBufferStats trả về thống kê bộ đệm nhận (số dòng chờ phát, số dòng bị bỏ...)
+func (ws *WorkspaceService) GetSocketBufferStats(address string, port string) (transport.BufferStats, error) {
+	return ws.socketManager.BufferStats(address, port)
 }
 
-// CheckSocketConnection kiểm tra trạng thái kết nối socket
-func (ws *WorkspaceService) CheckSocketConnection(address string, port string) bool {
-	connectionKey := fmt.Sprintf("%s:%s", address, port)
-
-	ws.socketManager.mutex.RLock()
-	socketConn, exists := ws.socketManager.connections[connectionKey]
-	ws.socketManager.mutex.RUnlock()
-
-	if !exists {
-		return false
-	}
-
-	socketConn.mutex.Lock()
-	isActive := socketConn.isActive
-	socketConn.mutex.Unlock()
-
-	return isActive
+// SetSocketBufferCapacity đổi số dòng tối đa của bộ đệm nhận cho các kết nối mở sau đó
+func (ws *WorkspaceService) SetSocketBufferCapacity(lines int) error {
+	return ws.socketManager.SetBufferCapacity(lines)
 }
@@ -0,0 +1,99 @@
+// Command modbusslave chạy một slave Modbus TCP/RTU giả lập để thử các mục modbus_reader không cần máy phân tích thật.
+//
+//	go run ./cmd/modbusslave -tcp 127.0.0.1:1502 -config workspace/default.json
+//	go run ./cmd/modbusslave -tcp "" -pty -crc-error 0.1 -drop 0.1
+//
+// Với -pty, đường dẫn pty được in ra dùng làm cổng nối tiếp cho Poll RTU (chỉ Linux); -crc-error và -drop
+// giả lập nhiễu đường RS-485.
+//
+// Với -config, mỗi mục modbus_reader được nạp sẵn giá trị 10*(i+1)+k (k là số thứ tự giá trị, số thực cộng thêm 0.25)
+// mã hóa theo d_t/d_o/d_f của mục đó; đổi dev_a thành địa chỉ của slave rồi đọc thử và so sánh với giá trị được in ra.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"myproject/backend/config"
+	"myproject/backend/modbus"
+	"myproject/backend/simulator"
+)
+
+func main() {
+	tcpAddress := flag.String("tcp", "127.0.0.1:1502", "địa chỉ TCP lắng nghe, rỗng để tắt")
+	usePTY := flag.Bool("pty", false, "tạo pseudo-terminal để trả lời Modbus RTU (chỉ Linux)")
+	crcErrors := flag.Float64("crc-error", 0, "tỉ lệ phản hồi RTU sai CRC (0..1)")
+	drop := flag.Float64("drop", 0, "tỉ lệ yêu cầu RTU không được trả lời (0..1)")
+	unit := flag.Int("unit", 0, "unit id được trả lời, 0 để trả lời mọi unit")
+	size := flag.Int("size", 10000, "số địa chỉ của mỗi bảng, địa chỉ lớn hơn trả exception 2")
+	configPath := flag.String("config", "", "file cấu hình để nạp giá trị mẫu cho các mục modbus_reader")
+	flag.Parse()
+
+	slave := modbus.NewSlave(byte(*unit), *size)
+	slave.CRCErrorRate = *crcErrors
+	slave.DropRate = *drop
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("Không thể đọc file cấu hình: %v", err)
+		}
+		cfg, err := config.Parse(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		seed(slave, cfg)
+	}
+
+	if *tcpAddress != "" {
+		listener, err := net.Listen("tcp", *tcpAddress)
+		if err != nil {
+			log.Fatalf("Không thể lắng nghe TCP: %v", err)
+		}
+		log.Printf("Modbus slave TCP: %s", listener.Addr())
+		go slave.ServeTCP(listener)
+	}
+
+	if *usePTY {
+		pty, err := simulator.OpenPTY()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Modbus slave RTU: %s", pty.Path)
+		go func() {
+			if err := slave.ServeRTU(pty); err != nil {
+				log.Printf("Modbus slave RTU kết thúc: %v", err)
+			}
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+}
+
+// seed ghi giá trị mẫu cho từng mục modbus_reader và in ra để so sánh khi đọc thử
+func seed(slave *modbus.Slave, cfg *config.Config) {
+	for i, entry := range cfg.ModbusReader {
+		t, format, order := modbus.DataType(entry.DT), modbus.Format(entry.DF), modbus.ByteOrder(entry.DO)
+		step := format.Words()
+		if t.Bits() {
+			step = 1
+		}
+		for k := 0; k < entry.NObj; k++ {
+			value := float64(10*(i+1) + k)
+			if t.Bits() {
+				value = float64(k % 2)
+			} else if format == modbus.Float32 || format == modbus.Float64 {
+				value += 0.25
+			}
+			if err := slave.SetValue(t, entry.RegA+k*step, format, order, value); err != nil {
+				log.Printf("modbus_reader[%d] %q: %v", i, entry.Desc, err)
+				break
+			}
+			log.Printf("modbus_reader[%d] %q: %s %d = %g (%s, %s)", i, entry.Desc, t, entry.RegA+k*step, value, format, order)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Command scripttest chạy test case của script prog/timers không cần thiết bị.
+//
+//	go run ./cmd/scripttest workspace/default.json
+//
+// Test case của station.json nằm trong station.tests.json. Thoát với mã 1 nếu có case không đạt.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"myproject/backend/config"
+)
+
+func main() {
+	testsPath := flag.String("tests", "", "file test, rỗng để dùng <config>.tests.json (chỉ khi có một file cấu hình)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Cách dùng: %s [-tests file] config.json...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*testsPath != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, configPath := range flag.Args() {
+		path := *testsPath
+		if path == "" {
+			path = config.ScriptTestsPath(configPath)
+		}
+		report, err := run(configPath, path)
+		if err != nil {
+			log.Printf("%s: %v", configPath, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("== %s (%s)\n%s", configPath, path, report)
+		if report.Failed > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func run(configPath, testsPath string) (*config.ScriptTestReport, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	data, err = os.ReadFile(testsPath)
+	if err != nil {
+		return nil, err
+	}
+	tests, err := config.ParseScriptTests(data)
+	if err != nil {
+		return nil, err
+	}
+	return config.RunScriptTests(cfg, tests), nil
+}
@@ -0,0 +1,82 @@
+// Command simulator chạy bộ mô phỏng datalogger để phát triển và kiểm thử không cần thiết bị thật.
+//
+//	go run ./cmd/simulator -tcp 127.0.0.1:19981 -pty
+//
+// Ứng dụng kết nối bằng ConnectSocket tới địa chỉ TCP, hoặc ConnectToPort tới đường dẫn pty được in ra.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"myproject/backend/simulator"
+	"myproject/backend/workspace"
+)
+
+func main() {
+	tcpAddress := flag.String("tcp", "127.0.0.1:19981", "địa chỉ TCP lắng nghe, rỗng để tắt")
+	usePTY := flag.Bool("pty", false, "tạo pseudo-terminal để kết nối như cổng COM (chỉ Linux)")
+	discover := flag.Bool("discover", true, "trả lời gói UDP discover trên cùng cổng TCP")
+	configPath := flag.String("config", "", "file cấu hình ban đầu, rỗng để dùng test.json mẫu")
+	serial := flag.String("serial", simulator.DefaultOptions().Serial, "số serial của thiết bị mô phỏng")
+	noLogin := flag.Bool("no-login", false, "không bắt đăng nhập trước các lệnh")
+	chunkLoss := flag.Float64("chunk-loss", 0, "tỉ lệ transfer_chunk bị bỏ qua (0..1) để thử gửi lại")
+	flag.Parse()
+
+	seed := workspace.DefaultTemplate()
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("Không thể đọc file cấu hình: %v", err)
+		}
+		seed = data
+	}
+
+	options := simulator.DefaultOptions()
+	options.Serial = *serial
+	options.RequireLogin = !*noLogin
+	options.ChunkLoss = *chunkLoss
+	sim, err := simulator.New(seed, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *tcpAddress != "" {
+		listener, err := net.Listen("tcp", *tcpAddress)
+		if err != nil {
+			log.Fatalf("Không thể lắng nghe TCP: %v", err)
+		}
+		log.Printf("Simulator TCP: %s", listener.Addr())
+		go sim.ServeTCP(listener)
+
+		if *discover {
+			_, port, _ := net.SplitHostPort(listener.Addr().String())
+			packetConn, err := net.ListenPacket("udp4", ":"+port)
+			if err != nil {
+				log.Printf("Không thể lắng nghe UDP discover: %v", err)
+			} else {
+				go sim.ServeDiscovery(packetConn)
+			}
+		}
+	}
+
+	if *usePTY {
+		pty, err := simulator.OpenPTY()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Simulator COM: %s", pty.Path)
+		go func() {
+			if err := sim.Serve(pty); err != nil {
+				log.Printf("Simulator COM kết thúc: %v", err)
+			}
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+}
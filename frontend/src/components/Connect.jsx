@@ -1,804 +1,1198 @@
-import { useEffect, useState, useContext, useCallback, useRef } from "react";
-import * as AuthService from "../../wailsjs/go/auth/AuthService";
-import {
-  DownloadConfig,
-  UploadConfig,
-  DownloadConfigEthernet,
-  UploadConfigEthernet,
-} from "../../wailsjs/go/workspace/WorkspaceService";
-import { ContextMenuContext } from "../store";
-
-import { ChangePassword } from "../../wailsjs/go/auth/AuthService";
-
-import { ShowErrorDialog, ShowInfoDialog } from "../../wailsjs/go/main/App";
-
-import {
-  Login,
-  ChangePassword as ChangePasswordWS,
-} from "../../wailsjs/go/workspace/WorkspaceService";
-
-import {
-  connectSocket,
-  disconnectSocket,
-  getAllSocketData,
-  validateSocketParams,
-} from "./functions/socket";
-
-function ConnectComponent({
-  onConnected,
-  dataFile,
-  setDataFile,
-  fileLoaded,
-  setFileLoaded,
-}) {
-  const [ports, setPorts] = useState([]);
-  const [status, setStatus] = useState("Not connected");
-  const [username, setUsername] = useState("");
-  const [password, setPassword] = useState("");
-  const [showModal, setShowModal] = useState(false);
-  const [oldPassword, setOldPassword] = useState("");
-  const [passwordInput, setPasswordInput] = useState("");
-  const [passwordConfirm, setPasswordConfirm] = useState("");
-  const btnRef = useRef(null);
-
-  const context = useContext(ContextMenuContext);
-
-  const handleConnect = () => {
-    if (context.selectedConnection === "serial") {
-      handleSerialConnect();
-    } else if (context.selectedConnection === "ethernet") {
-      handleSocketConnect();
-    }
-  };
-
-  const handleSerialConnect = () => {
-    if (!context.selectedPort) {
-      setStatus("Please select a COM port");
-      return;
-    }
-
-    AuthService.ConnectToPort(context.selectedPort)
-      .then(() => {
-        context.setIsConnected(true);
-        context.setSelectedPort(context.selectedPort);
-        setStatus("Connected to " + context.selectedPort);
-        if (onConnected) onConnected();
-      })
-      .catch((err) => setStatus("Connection error: " + err));
-  };
-
-  const handleSocketConnect = async () => {
-    if (!validateSocketParams(context.socketAddress, context.socketPort)) {
-      setStatus("Invalid address or port");
-      return;
-    }
-
-    try {
-      await connectSocket(context.socketAddress, context.socketPort);
-      context.setIsSocketConnected(true);
-      context.setIsConnected(true);
-      setStatus(`Connected to ${context.socketAddress}:${context.socketPort}`);
-      if (onConnected) onConnected();
-    } catch (err) {
-      setStatus("Socket connection error: " + err.message);
-      context.setIsSocketConnected(false);
-    }
-  };
-
-  const handleLogin = () => {
-    setUsername("");
-    setPassword("");
-    if (context.isLogin) {
-      ShowErrorDialog("Vui lòng đăng xuất trước khi đăng nhập lại");
-      return;
-    }
-
-    if (!context.isConnected) {
-      ShowErrorDialog("Vui lòng kết nối trước khi đăng nhập");
-      return;
-    }
-
-    if (!username || !password) {
-      ShowErrorDialog("Vui lòng nhập tên người dùng và mật khẩu");
-      return;
-    }
-    if (context.selectedConnection === "serial") {
-      AuthService.Login(username, password).catch((err) => {
-        ShowErrorDialog("Lỗi khi đăng nhập: " + err);
-      });
-    } else if (context.selectedConnection === "ethernet") {
-      Login(
-        context.socketAddress,
-        context.socketPort,
-        username,
-        password
-      ).catch((err) => {
-        ShowErrorDialog("Lỗi khi đăng nhập: " + err);
-      });
-    }
-  };
-
-  const handleDisconnect = () => {
-    if (context.selectedConnection === "serial") {
-      handleSerialDisconnect();
-    } else if (context.selectedConnection === "ethernet") {
-      handleSocketDisconnect();
-    }
-    // Reset connection state regardless of connection type
-    context.setIsConnected(false);
-    context.setIsLogin(false);
-    context.setRole("");
-    context.setDisplayAnalogUnit(false);
-    context.setDisplayMemoryView(false);
-    context.setDisplayTagView(false);
-    context.setDigitalOutput([]);
-    setStatus("Disconnected");
-  };
-
-  const handleSerialDisconnect = async () => {
-    if (!context.isConnected) return;
-    try {
-      await AuthService.Disconnect();
-      setStatus("Disconnected from serial");
-      context.setSelectedPort("");
-      context.setIsConnected(false);
-      context.setIsLogin(false);
-      context.setRole("");
-    } catch (err) {
-      setStatus("Disconnect error: " + err);
-    }
-  };
-
-  const handleSocketDisconnect = async () => {
-    try {
-      await disconnectSocket(context.socketAddress, context.socketPort);
-      context.setIsSocketConnected(false);
-      context.setIsConnected(false);
-      context.setIsLogin(false);
-      context.setRole("");
-      setStatus("Disconnected from socket");
-    } catch (err) {
-      setStatus("Disconnect error: " + err.message);
-      // Even if there's an error, reset the state
-      context.setIsSocketConnected(false);
-      context.setIsConnected(false);
-      context.setIsLogin(false);
-      context.setRole("");
-    }
-  };
-
-  const handleUploadConfig = () => {
-    if (!dataFile) {
-      ShowErrorDialog("Không có data để upload");
-      return;
-    }
-    if (context.selectedConnection === "serial") {
-      UploadConfig(JSON.stringify(dataFile)).catch((err) => {
-        ShowErrorDialog("Lỗi upload cấu hình: " + err);
-      });
-    } else if (context.selectedConnection === "ethernet") {
-      UploadConfigEthernet(
-        context.socketAddress,
-        context.socketPort,
-        JSON.stringify(dataFile)
-      ).catch((err) => {
-        ShowErrorDialog("Lỗi upload cấu hình: " + err);
-      });
-    }
-  };
-
-  // Function xử lý data response
-  const handleDataResponse = useCallback(
-    (jsonData) => {
-      switch (jsonData.type) {
-        case "read_analog":
-          if (jsonData.data) {
-            context.setAnalogData(jsonData.data);
-          }
-          break;
-
-        case "read_tag_view":
-          if (jsonData.data) {
-            context.setTagViewData(jsonData.data);
-          }
-          break;
-
-        case "read_memory_view":
-          if (jsonData.data) {
-            context.setMemoryViewData(jsonData.data);
-          }
-          break;
-
-        case "set_rtc":
-          if (jsonData.status === "success") {
-            context.setInfoDialog("Đặt thời gian thành công");
-          } else {
-            context.setInfoDialog("Đặt thời gian thất bại");
-          }
-          break;
-
-        case "get_measure_mode":
-          if (jsonData.mode) {
-            context.setChangeMode(jsonData.mode);
-          }
-          break;
-
-        case "set_measure_mode":
-          if (jsonData.status === "success") {
-            const newMode =
-              context.changeMode === "current" ? "voltage" : "current";
-            context.setChangeMode(newMode);
-
-            ShowInfoDialog(
-              `Đặt chế độ đo ${newMode} thành công`,
-              "Set Measure Mode"
-            );
-          } else {
-            ShowErrorDialog("Đặt chế độ đo thất bại");
-          }
-          break;
-
-        case "get_rtc":
-          if (jsonData.ts) {
-            const tsSeconds = Number(jsonData.ts); // UTC giây
-            const localTimeStr = new Date(tsSeconds * 1000).toLocaleString();
-            context.setInfoDialog(`Thời gian hiện tại: ${localTimeStr}`);
-          }
-          break;
-
-        case "download_config":
-          setFileLoaded("");
-          setDataFile(jsonData);
-          ShowInfoDialog(
-            "Đã download về máy bạn thành công",
-            "Download Config"
-          );
-          break;
-
-        case "upload_config":
-          if (jsonData.status === "success") {
-            if (btnRef.current) {
-              btnRef.current.click();
-            }
-            ShowInfoDialog(
-              "Đã upload lên thiết bị thành công",
-              "Upload Config"
-            );
-          } else {
-            ShowErrorDialog("Upload thất bại");
-          }
-          break;
-
-        case "login":
-          if (jsonData.status === "success") {
-            context.setIsLogin(true);
-            context.setRole(jsonData.role || "user");
-            ShowInfoDialog("Đăng nhập thành công", "Login");
-          } else {
-            ShowErrorDialog("Đăng nhập thất bại");
-          }
-          break;
-
-        case "logout":
-          if (jsonData.status === "success") {
-            context.setIsLogin(false);
-            context.setRole("");
-            ShowInfoDialog("Đăng xuất thành công", "Logout");
-          } else {
-            ShowErrorDialog("Đăng xuất thất bại");
-          }
-          break;
-
-        case "change_password":
-          if (jsonData.status === "success") {
-            ShowInfoDialog("Đổi mật khẩu thành công", "Change Password");
-            setShowModal(false); // Đóng modal khi đổi mật khẩu thành công
-            setOldPassword(""); // Clear form
-            setPasswordInput("");
-            setPasswordConfirm("");
-          } else {
-            ShowErrorDialog(jsonData.message);
-          }
-          break;
-
-        case "reboot":
-          if (jsonData.status === "success") {
-            if (btnRef.current) {
-              btnRef.current.click();
-            }
-            ShowInfoDialog(
-              "Reboot thành công. App sẽ Disconnect khỏi thiết bị",
-              "Reboot"
-            );
-            context.setInfoDialog("");
-          } else {
-            ShowErrorDialog("Reboot thất bại");
-          }
-          break;
-
-        case "network_setting":
-          if (jsonData.status === "success") {
-            ShowInfoDialog(
-              "Cài đặt mạng thành công. Reboot thiết bị để áp dụng thay đổi",
-              "Network Setting"
-            );
-          } else {
-            ShowErrorDialog("Cài đặt mạng thất bại");
-          }
-          break;
-
-        case "network":
-          context.setFormData(jsonData);
-          break;
-
-        case "calib_4ma":
-          if (jsonData.status === "success") {
-            ShowInfoDialog("Calib 4mA thành công", "Calib 4mA");
-          } else {
-            ShowErrorDialog("Calib 4mA thất bại");
-          }
-          break;
-
-        case "calib_16ma":
-          if (jsonData.status === "success") {
-            ShowInfoDialog("Calib 16mA thành công", "Calib 16mA");
-          } else {
-            ShowErrorDialog("Calib 16mA thất bại");
-          }
-          break;
-
-        case "set_digital_output":
-          if (jsonData.status === "success") {
-            ShowInfoDialog(
-              "Đã cập nhật đầu Digital Output thành công",
-              "Set Digital Output"
-            );
-          } else {
-            ShowErrorDialog("Cập nhật đầu Digital Output thất bại");
-          }
-          break;
-
-        case "read_system_info":
-          if (jsonData.data) {
-            context.setInfoDialog(jsonData.data.replaceAll(",", "\n"));
-          } else {
-            context.setInfoDialog("Không có dữ liệu hệ thống");
-          }
-          break;
-
-        case "read_sim_info":
-          if (jsonData.data) {
-            context.setInfoDialog(jsonData.data);
-          } else {
-            context.setInfoDialog("Không có thông tin SIM");
-          }
-          break;
-
-        case "read_sdcard_info":
-          if (jsonData.data) {
-            context.setInfoDialog(jsonData.data);
-          } else {
-            context.setInfoDialog("Không có thông tin thẻ SD");
-          }
-          break;
-
-        case "ping":
-          if (jsonData.status === "success") {
-            context.setInfoDialog("Ping thành công");
-          } else {
-            context.setInfoDialog("Ping thất bại");
-          }
-          break;
-
-        case "write_serial_number":
-          if (jsonData.status === "success") {
-            context.setInfoDialog("Write serial number thành công");
-          } else {
-            context.setInfoDialog("Write serial number thất bại");
-          }
-          break;
-
-        case "write_mac":
-          if (jsonData.status === "success") {
-            context.setInfoDialog("Write mac thành công");
-          } else {
-            context.setInfoDialog("Write mac thất bại");
-          }
-          break;
-
-        case "reset_configuration":
-          if (jsonData.status === "success") {
-            context.setInfoDialog("Reset configuration thành công");
-          } else {
-            context.setInfoDialog("Reset configuration thất bại");
-          }
-          break;
-
-        case "get_gps":
-          if (jsonData.data) {
-            context.setInfoDialog(jsonData.data);
-          } else {
-            context.setInfoDialog("Không có dữ liệu GPS");
-          }
-          break;
-
-        default:
-          break;
-      }
-    },
-    [context.changeMode]
-  );
-
-  // Socket data reader
-  const readSocketData = async () => {
-    if (!context.isSocketConnected) return;
-
-    try {
-      const data = await getAllSocketData(
-        context.socketAddress,
-        context.socketPort
-      );
-
-      if (data && data.length > 0) {
-        for (let i = 0; i < data.length; i++) {
-          const result = data[i];
-          context.setDataTest(result);
-          try {
-            const jsonData = JSON.parse(result);
-            handleDataResponse(jsonData);
-          } catch (parseErr) {
-            console.log("Non-JSON data received:", latestData);
-          }
-        }
-      }
-    } catch (err) {
-      console.error("Error reading socket data:", err);
-    }
-  };
-
-  useEffect(() => {
-    AuthService.ListPorts()
-      .then(setPorts)
-      .catch((err) => console.log("Get COM error: " + err));
-
-    if (context.selectedPort && context.isConnected) {
-      setStatus("Connected to " + context.selectedPort);
-    } else {
-      AuthService.GetCurrentPort()
-        .then((port) => {
-          if (port) {
-            context.setSelectedPort(port);
-            context.setIsConnected(true);
-            setStatus("Connected to " + port);
-          } else {
-            setStatus("Not connected");
-          }
-        })
-        .catch((err) => console.log("Get current port error: " + err));
-    }
-  }, []);
-
-  useEffect(() => {
-    // Handle data listening for both serial and socket
-    if (!context.isConnected) return;
-
-    let isListening = true;
-
-    const listenForData = async () => {
-      while (isListening) {
-        try {
-          if (context.selectedConnection === "serial" && context.selectedPort) {
-            const response = await AuthService.GetResponse(1000); // 1 second timeout
-            if (response) {
-              try {
-                const jsonData = JSON.parse(response);
-                context.setDataTest(response);
-                handleDataResponse(jsonData);
-              } catch (e) {
-                context.setDataTest(response);
-              }
-            }
-          } else if (
-            context.selectedConnection === "ethernet" &&
-            context.isSocketConnected
-          ) {
-            await readSocketData();
-            await new Promise((resolve) => setTimeout(resolve, 500));
-          }
-        } catch (err) {
-          if (!err.toString().includes("timeout")) {
-            console.error("Data listening error:", err);
-          }
-        }
-      }
-    };
-
-    listenForData();
-
-    return () => {
-      isListening = false;
-    };
-  }, [
-    context.isConnected,
-    context.selectedPort,
-    context.selectedConnection,
-    context.isSocketConnected,
-    context.changeMode,
-  ]);
-
-  // Cleanup effect to disconnect when component unmounts
-  useEffect(() => {
-    return () => {
-      if (context.isConnected) {
-        handleDisconnect();
-      }
-    };
-  }, []);
-
-  return (
-    <div className="w-full max-w-xs bg-white border border-gray-300 rounded-lg shadow p-4 flex flex-col gap-3">
-      {showModal && (
-        <div
-          className="fixed inset-0 bg-black/40 flex items-center justify-center z-[1100]"
-          // onClick={() => setShowModal(false)} // Đóng modal khi click nền đen
-        >
-          <div
-            className="bg-white rounded-xl shadow-2xl w-full max-w-xs sm:max-w-sm p-7 relative animate-fadeIn"
-            onClick={(e) => e.stopPropagation()} // Ngăn sự kiện nổi bọt khi click vào modal
-            style={{ boxShadow: "0 8px 32px rgba(0,0,0,0.18)" }}
-          >
-            <div className="flex flex-col items-center mb-4">
-              <span className="text-lg font-semibold text-gray-800">
-                Change Password
-              </span>
-            </div>
-            <input
-              type="password"
-              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
-              value={oldPassword}
-              autoFocus
-              placeholder="Old Password"
-              onChange={(e) => setOldPassword(e.target.value)}
-            />
-            <input
-              type="password"
-              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
-              value={passwordInput}
-              autoFocus
-              placeholder="New Password"
-              onChange={(e) => setPasswordInput(e.target.value)}
-            />
-            <input
-              type="password"
-              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
-              value={passwordConfirm}
-              autoFocus
-              placeholder="Confirm New Password"
-              onChange={(e) => setPasswordConfirm(e.target.value)}
-            />
-            <div className="flex gap-3 mt-2">
-              <button
-                className="flex-1 px-4 py-2 bg-blue-500 text-white rounded-lg font-medium hover:bg-blue-600 transition"
-                onClick={() => {
-                  if (!oldPassword || !passwordInput || !passwordConfirm) {
-                    ShowErrorDialog("Vui lòng điền đầy đủ thông tin");
-                    return;
-                  }
-
-                  if (passwordInput === oldPassword) {
-                    ShowErrorDialog(
-                      "Mật khẩu mới không được trùng với mật khẩu cũ"
-                    );
-                    return;
-                  }
-
-                  if (passwordInput !== passwordConfirm) {
-                    ShowErrorDialog("Mật khẩu không khớp");
-                    return;
-                  }
-                  if (context.selectedConnection === "serial") {
-                    ChangePassword(oldPassword, passwordInput);
-                  } else if (context.selectedConnection === "ethernet") {
-                    ChangePasswordWS(
-                      context.socketAddress,
-                      context.socketPort,
-                      oldPassword,
-                      passwordInput
-                    );
-                  }
-                }}
-              >
-                Save
-              </button>
-              <button
-                className="flex-1 px-4 py-2 bg-gray-200 text-gray-700 rounded-lg font-medium hover:bg-gray-300 transition"
-                onClick={() => setShowModal(false)} // Đóng modal khi click nút Close
-              >
-                Close
-              </button>
-            </div>
-          </div>
-        </div>
-      )}
-      <div className="flex items-center gap-2 mb-1">
-        <span className="text-base font-semibold">COM Port</span>
-        <span
-          className={
-            status.includes("Connected to")
-              ? "inline-block w-3 h-3 rounded-full bg-green-500 border border-green-700"
-              : status.includes("error")
-              ? "inline-block w-3 h-3 rounded-full bg-red-500 border border-red-700"
-              : "inline-block w-3 h-3 rounded-full bg-gray-400 border border-gray-500"
-          }
-        ></span>
-      </div>
-      <div>
-        <label className="block text-xs text-gray-700 mb-1">Connection</label>
-        <select
-          className="border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
-          value={context.selectedConnection}
-          onChange={(e) => {
-            // Disconnect current connection before switching
-            if (context.isConnected) {
-              handleDisconnect();
-            }
-            context.setSelectedConnection(e.target.value);
-          }}
-        >
-          <option value="">-- Select connection --</option>
-          {["serial", "ethernet"]?.map((type) => (
-            <option key={type} value={type}>
-              {type.toUpperCase()}
-            </option>
-          ))}
-        </select>
-      </div>
-      {context.selectedConnection === "serial" && (
-        <div>
-          <label className="block text-xs text-gray-700 mb-1">
-            Serial port
-          </label>
-          <select
-            className="border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
-            value={context.selectedPort}
-            onChange={(e) => context.setSelectedPort(e.target.value)}
-          >
-            <option value="">-- Select COM port --</option>
-            {ports?.map((port) => (
-              <option key={port} value={port}>
-                {port}
-              </option>
-            ))}
-          </select>
-        </div>
-      )}
-      {context.selectedConnection === "ethernet" && (
-        <div>
-          <label className="block text-xs text-gray-700 mb-1">IP Address</label>
-          <input
-            className="mb-1 border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
-            value={context.socketAddress}
-            onChange={(e) => context.setSocketAddress(e.target.value)}
-          />
-          <label className="block text-xs text-gray-700 mb-1">Port</label>
-          <input
-            className="mb-1 border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
-            value={context.socketPort}
-            onChange={(e) => context.setSocketPort(e.target.value)}
-            type="number"
-          />
-        </div>
-      )}
-
-      <div className="text-xs text-gray-500 mb-2 flex flex-col gap-1">
-        <label className="block text-xs text-gray-700">Login</label>
-        <input
-          type="text"
-          value={username}
-          disabled={!context.isConnected}
-          placeholder="Username"
-          onChange={(e) => setUsername(e.target.value)}
-          className="w-full border border-gray-300 rounded px-2 py-1 mb-1 focus:outline-none focus:ring focus:border-blue-400"
-        />
-        <input
-          type="password"
-          value={password}
-          disabled={!context.isConnected}
-          placeholder="Password"
-          onChange={(e) => setPassword(e.target.value)}
-          className="w-full border border-gray-300 rounded px-2 py-1 mb-1 focus:outline-none focus:ring focus:border-blue-400"
-        />
-      </div>
-      <div className="flex flex-col md:flex-row items-center justify-center gap-2 w-full">
-        {!context.isConnected ? (
-          <button
-            onClick={handleConnect}
-            className="flex-1 px-2 w-full bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
-          >
-            Connect
-          </button>
-        ) : (
-          <button
-            ref={btnRef}
-            onClick={handleDisconnect}
-            className="flex-1 px-2 w-full bg-gray-200 text-gray-700 py-1 rounded border border-gray-400 hover:bg-gray-300 text-xs transition"
-          >
-            Disconnect
-          </button>
-        )}
-      </div>
-      <div className="flex flex-col md:flex-row items-center justify-center gap-2 w-full">
-        <button
-          onClick={handleLogin}
-          className="flex-1 px-2 w-full bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
-          disabled={!context.isConnected}
-        >
-          Login
-        </button>
-      </div>
-      <button
-        onClick={() => setShowModal(true)}
-        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
-    ${
-      context.isConnected && context.isLogin
-        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
-        : "bg-gray-100 text-gray-400 border-gray-300"
-    }
-  `}
-        disabled={!context.isConnected || !context.isLogin}
-      >
-        Change Password
-      </button>
-      <button
-        disabled={!context.isConnected || !context.isLogin}
-        onClick={handleUploadConfig}
-        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
-    ${
-      context.isConnected && context.isLogin
-        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
-        : "bg-gray-100 text-gray-400 border-gray-300"
-    }
-  `}
-      >
-        Upload
-      </button>
-      <button
-        disabled={!context.isConnected}
-        onClick={() => {
-          if (context.selectedConnection === "serial") {
-            DownloadConfig();
-          } else if (context.selectedConnection === "ethernet") {
-            DownloadConfigEthernet(context.socketAddress, context.socketPort);
-          }
-        }}
-        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
-    ${
-      context.isConnected
-        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
-        : "bg-gray-100 text-gray-400 border-gray-300"
-    }
-  `}
-      >
-        Download
-      </button>
-      <div
-        className={`text-xs text-center ${
-          status.includes("error")
-            ? "text-red-500"
-            : status.includes("Connected to")
-            ? "text-green-600"
-            : "text-gray-700"
-        }`}
-      >
-        {status}
-      </div>
-      {!!fileLoaded && (
-        <div className="fixed bottom-0 left-0 z-20 p-2 bg-stone-100 shadow-md text-sm text-gray-600">
-          {fileLoaded}
-        </div>
-      )}
-    </div>
-  );
-}
-
-export default ConnectComponent;
+import { useEffect, useState, useContext, useCallback, useRef } from "react";
+import * as AuthService from "../../wailsjs/go/auth/AuthService";
+import {
+  ValidateConfigData,
+  DiffDeviceConfig,
+} from "../../wailsjs/go/workspace/WorkspaceService";
+import * as DeviceService from "../../wailsjs/go/device/DeviceService";
+import { ContextMenuContext } from "../store";
+
+import {
+  ShowErrorDialog,
+  ShowInfoDialog,
+  ShowQuestionDialog,
+} from "../../wailsjs/go/main/App";
+
+import {
+  connectSocket,
+  disconnectSocket,
+  validateSocketParams,
+} from "./functions/socket";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import * as DiscoveryService from "../../wailsjs/go/discovery/DiscoveryService";
+import * as CaptureService from "../../wailsjs/go/capture/CaptureService";
+
+function ConnectComponent({
+  onConnected,
+  dataFile,
+  setDataFile,
+  fileLoaded,
+  setFileLoaded,
+}) {
+  const [ports, setPorts] = useState([]);
+  const [profiles, setProfiles] = useState([]);
+  const [selectedProfile, setSelectedProfile] = useState("");
+  const [discovered, setDiscovered] = useState([]);
+  const [isScanning, setIsScanning] = useState(false);
+  const [isDetecting, setIsDetecting] = useState(false);
+  const [capture, setCapture] = useState(null);
+  const [captureFiles, setCaptureFiles] = useState([]);
+  const [replayFile, setReplayFile] = useState("");
+  const [replaySpeed, setReplaySpeed] = useState(1);
+  const [replay, setReplay] = useState(null);
+  const [chunkedUpload, setChunkedUpload] = useState(false);
+  const [activeConnection, setActiveConnection] = useState("");
+  const [status, setStatus] = useState("Not connected");
+  const [username, setUsername] = useState("");
+  const [password, setPassword] = useState("");
+  const [showModal, setShowModal] = useState(false);
+  const [oldPassword, setOldPassword] = useState("");
+  const [passwordInput, setPasswordInput] = useState("");
+  const [passwordConfirm, setPasswordConfirm] = useState("");
+  const btnRef = useRef(null);
+
+  const context = useContext(ContextMenuContext);
+
+  // Chọn đường truyền cho DeviceService một lần sau khi kết nối; mọi lệnh thiết bị
+  // sau đó (đăng nhập, upload, Control, MemoryView...) không cần biết COM hay Ethernet
+  const selectConnection = async (use) => {
+    try {
+      await use();
+      setActiveConnection(await DeviceService.ActiveConnection());
+    } catch (err) {
+      setStatus("Select connection error: " + err);
+    }
+  };
+
+  const handleConnect = () => {
+    if (context.selectedConnection === "serial") {
+      handleSerialConnect();
+    } else if (context.selectedConnection === "ethernet") {
+      handleSocketConnect();
+    }
+  };
+
+  const handleSerialConnect = () => {
+    if (!context.selectedPort) {
+      setStatus("Please select a COM port");
+      return;
+    }
+
+    const profile =
+      profiles.find((p) => p.name === selectedProfile) || profiles[0] || {};
+
+    AuthService.ConnectToPort(context.selectedPort, profile)
+      .then(async () => {
+        await selectConnection(DeviceService.UseSerial);
+        context.setIsConnected(true);
+        context.setSelectedPort(context.selectedPort);
+        setStatus("Connected to " + context.selectedPort);
+        if (onConnected) onConnected();
+      })
+      .catch((err) => setStatus("Connection error: " + err));
+  };
+
+  const handleSocketConnect = async () => {
+    if (!validateSocketParams(context.socketAddress, context.socketPort)) {
+      setStatus("Invalid address or port");
+      return;
+    }
+
+    try {
+      await connectSocket(context.socketAddress, context.socketPort);
+      await selectConnection(() =>
+        DeviceService.UseSocket(context.socketAddress, context.socketPort)
+      );
+      context.setIsSocketConnected(true);
+      context.setIsConnected(true);
+      setStatus(`Connected to ${context.socketAddress}:${context.socketPort}`);
+      if (onConnected) onConnected();
+    } catch (err) {
+      setStatus("Socket connection error: " + err.message);
+      context.setIsSocketConnected(false);
+    }
+  };
+
+  const handleLogin = () => {
+    setUsername("");
+    setPassword("");
+    if (context.isLogin) {
+      ShowErrorDialog("Vui lòng đăng xuất trước khi đăng nhập lại");
+      return;
+    }
+
+    if (!context.isConnected) {
+      ShowErrorDialog("Vui lòng kết nối trước khi đăng nhập");
+      return;
+    }
+
+    if (!username || !password) {
+      ShowErrorDialog("Vui lòng nhập tên người dùng và mật khẩu");
+      return;
+    }
+    DeviceService.Login(username, password).catch((err) => {
+      ShowErrorDialog("Lỗi khi đăng nhập: " + err);
+    });
+  };
+
+  const handleDisconnect = () => {
+    if (context.selectedConnection === "serial") {
+      handleSerialDisconnect();
+    } else if (context.selectedConnection === "ethernet") {
+      handleSocketDisconnect();
+    }
+    // Reset connection state regardless of connection type
+    DeviceService.ClearConnection();
+    setActiveConnection("");
+    context.setIsConnected(false);
+    context.setIsLogin(false);
+    context.setRole("");
+    context.setDisplayAnalogUnit(false);
+    context.setDisplayMemoryView(false);
+    context.setDisplayTagView(false);
+    context.setDigitalOutput([]);
+    setStatus("Disconnected");
+  };
+
+  const handleSerialDisconnect = async () => {
+    if (!context.isConnected) return;
+    try {
+      await AuthService.Disconnect();
+      setStatus("Disconnected from serial");
+      context.setSelectedPort("");
+      context.setIsConnected(false);
+      context.setIsLogin(false);
+      context.setRole("");
+    } catch (err) {
+      setStatus("Disconnect error: " + err);
+    }
+  };
+
+  const toggleCapture = async () => {
+    try {
+      if (capture?.active) {
+        const result = await CaptureService.StopCapture();
+        setCapture(result);
+        setStatus(`Capture saved to ${result.path} (${result.frames} frames)`);
+      } else {
+        const result = await CaptureService.StartCapture({
+          path: "",
+          maxSizeMB: 0,
+          maxFiles: 0,
+        });
+        setCapture(result);
+        setStatus(`Recording traffic to ${result.path}`);
+      }
+    } catch (err) {
+      setStatus("Capture error: " + err);
+    }
+  };
+
+  const loadCaptureFiles = () => {
+    CaptureService.ListCaptures()
+      .then((files) => {
+        setCaptureFiles(files || []);
+        if (!replayFile && files?.length) setReplayFile(files[0].path);
+      })
+      .catch((err) => setStatus("List captures error: " + err));
+  };
+
+  const toggleReplay = async () => {
+    try {
+      if (replay?.active) {
+        setReplay({ ...replay, ...(await CaptureService.StopReplay()) });
+        return;
+      }
+      // Phát lại vào kết nối đang chọn để các màn hình hiển thị như thiết bị thật
+      const kind = context.selectedConnection === "ethernet" ? "tcp" : "serial";
+      const endpoint =
+        kind === "tcp"
+          ? `${context.socketAddress}:${context.socketPort}`
+          : context.selectedPort || "replay";
+      const state = await CaptureService.StartReplay({
+        path: replayFile,
+        speed: Number(replaySpeed),
+        transport: kind,
+        endpoint,
+        maxGapMs: 0,
+      });
+      setReplay({ ...state, kind, endpoint });
+      setStatus(`Replaying ${replayFile} (${state.total} frames)`);
+    } catch (err) {
+      setStatus("Replay error: " + err);
+    }
+  };
+
+  const handleAutoDetect = async () => {
+    setIsDetecting(true);
+    setStatus("Probing COM ports...");
+    try {
+      const profile = profiles.find((p) => p.name === selectedProfile) || {};
+      const results = await AuthService.AutoDetect(profile, 0);
+      const loggers = (results || []).filter((r) => r.isLogger);
+      if (loggers.length > 0) {
+        context.setSelectedPort(loggers[0].port.name);
+        setStatus(
+          `Datalogger found on ${loggers.map((r) => r.port.name).join(", ")}`
+        );
+      } else {
+        setStatus("No datalogger answered on any COM port");
+      }
+    } catch (err) {
+      setStatus("Auto detect error: " + err);
+    } finally {
+      setIsDetecting(false);
+    }
+  };
+
+  const handleDiscover = async () => {
+    setIsScanning(true);
+    setDiscovered([]);
+    setStatus("Scanning LAN for dataloggers...");
+    // Thiết bị tìm thấy được đẩy lên dần qua sự kiện discovery:device
+    const off = EventsOn("discovery:device", (device) => {
+      setDiscovered((list) => [
+        ...list.filter(
+          (d) => d.address !== device.address || d.port !== device.port
+        ),
+        device,
+      ]);
+    });
+    try {
+      const options = await DiscoveryService.GetDefaultOptions();
+      const devices = await DiscoveryService.Discover(options);
+      setDiscovered(devices || []);
+      setStatus(`Found ${devices?.length || 0} datalogger(s)`);
+    } catch (err) {
+      setStatus("Scan error: " + err);
+    } finally {
+      off();
+      setIsScanning(false);
+    }
+  };
+
+  const selectDiscovered = (device) => {
+    context.setSocketAddress(device.address);
+    context.setSocketPort(String(device.port));
+  };
+
+  const handleSocketDisconnect = async () => {
+    try {
+      await disconnectSocket(context.socketAddress, context.socketPort);
+      context.setIsSocketConnected(false);
+      context.setIsConnected(false);
+      context.setIsLogin(false);
+      context.setRole("");
+      setStatus("Disconnected from socket");
+    } catch (err) {
+      setStatus("Disconnect error: " + err.message);
+      // Even if there's an error, reset the state
+      context.setIsSocketConnected(false);
+      context.setIsConnected(false);
+      context.setIsLogin(false);
+      context.setRole("");
+    }
+  };
+
+  // Kiểm tra cấu hình trước khi upload: trả về force (true nếu người dùng xác nhận
+  // upload dù có lỗi) hoặc null nếu hủy
+  const confirmValidation = async (data) => {
+    try {
+      const issues = (await ValidateConfigData(data)) || [];
+      const errors = issues.filter((i) => i.severity === "error");
+      if (errors.length === 0) return false;
+
+      const lines = errors
+        .slice(0, 10)
+        .map((i) => `${i.pointer || "/"}: ${i.message}`);
+      if (errors.length > 10) {
+        lines.push(`... và ${errors.length - 10} lỗi khác`);
+      }
+      const result = await ShowQuestionDialog(
+        `Cấu hình có ${errors.length} lỗi:\n${lines.join(
+          "\n"
+        )}\n\nVẫn upload xuống thiết bị?`,
+        "Cấu hình không hợp lệ"
+      );
+      return result === "Yes" ? true : null;
+    } catch (err) {
+      ShowErrorDialog("Lỗi kiểm tra cấu hình: " + err);
+      return null;
+    }
+  };
+
+  const handleUploadConfig = async () => {
+    if (!dataFile) {
+      ShowErrorDialog("Không có data để upload");
+      return;
+    }
+    const data = JSON.stringify(dataFile);
+    const force = await confirmValidation(data);
+    if (force === null) return;
+
+    if (chunkedUpload) {
+      // Truyền theo chunk có xác nhận, tiến độ nhận qua event transfer:progress
+      const options = { chunkSize: 0, retries: 0, timeoutMs: 0 };
+      DeviceService.UploadConfigChunked(data, force, options).catch((err) => {
+        ShowErrorDialog("Lỗi upload cấu hình: " + err);
+      });
+      return;
+    }
+
+    DeviceService.UploadConfig(data, force).catch((err) => {
+      ShowErrorDialog("Lỗi upload cấu hình: " + err);
+    });
+  };
+
+  // Chỉ upload những section/phần tử khác với cấu hình đang có trên thiết bị
+  const handlePushChanges = async () => {
+    if (!dataFile) {
+      ShowErrorDialog("Không có data để upload");
+      return;
+    }
+    const force = await confirmValidation(JSON.stringify(dataFile));
+    if (force === null) return;
+
+    try {
+      const report = await DeviceService.PushConfigChanges(dataFile, force);
+      const sectionName = (ref) =>
+        ref.index === undefined || ref.index === null
+          ? ref.name
+          : `${ref.name}[${ref.index}]`;
+      const lines = report.results.map(
+        (r) =>
+          `${sectionName(r.section)}: ${r.status}${r.error ? " - " + r.error : ""}`
+      );
+      const fallback = report.fallback
+        ? "Thiết bị không hỗ trợ upload theo section, đã gửi cả cấu hình bằng upload_config.\n"
+        : "";
+      if (report.success) {
+        ShowInfoDialog(
+          lines.length > 0
+            ? `${fallback}Đã upload ${lines.length} phần:\n${lines.join("\n")}`
+            : "Không có thay đổi so với thiết bị",
+          "Upload changes"
+        );
+      } else {
+        ShowErrorDialog(
+          `${report.error}\n${
+            report.fallback
+              ? "Thiết bị không hỗ trợ upload theo section và upload cả cấu hình không thành công, hãy kiểm tra lại cấu hình thiết bị."
+              : report.rolledBack
+              ? "Đã khôi phục các phần đã upload."
+              : "Khôi phục không hoàn tất, hãy kiểm tra lại cấu hình thiết bị."
+          }\n${lines.join("\n")}`
+        );
+      }
+    } catch (err) {
+      ShowErrorDialog("Lỗi upload cấu hình: " + err);
+    }
+  };
+
+  // So sánh cấu hình trên thiết bị với file đang mở: những gì sẽ thay đổi khi upload
+  const handleDiffConfig = async () => {
+    if (!fileLoaded) {
+      ShowErrorDialog("Chưa mở file cấu hình trong workspace");
+      return;
+    }
+    const relPath = fileLoaded.replace(/^workspace\//, "");
+    try {
+      const diff = await DiffDeviceConfig(relPath);
+      if (!diff || diff.total === 0) {
+        ShowInfoDialog("Cấu hình trên thiết bị giống với file", "Diff");
+        return;
+      }
+      const lines = [];
+      for (const section of diff.sections) {
+        lines.push(`[${section.name}]`);
+        for (const change of section.changes) {
+          if (change.text) {
+            lines.push(`  ${change.path}: sửa theo dòng`);
+            for (const line of change.text) {
+              if (line.op !== " ") lines.push(`    ${line.op} ${line.text}`);
+            }
+          } else if (change.kind === "added") {
+            lines.push(`  ${change.path}: thêm ${JSON.stringify(change.new)}`);
+          } else if (change.kind === "removed") {
+            lines.push(`  ${change.path}: xóa ${JSON.stringify(change.old)}`);
+          } else {
+            lines.push(
+              `  ${change.path}: ${JSON.stringify(change.old)} → ${JSON.stringify(
+                change.new
+              )}`
+            );
+          }
+        }
+      }
+      ShowInfoDialog(
+        `${diff.total} thay đổi\n${lines.join("\n")}`,
+        "Diff " + relPath
+      );
+    } catch (err) {
+      ShowErrorDialog("Lỗi so sánh cấu hình: " + err);
+    }
+  };
+
+  // Function xử lý data response
+  const handleDataResponse = useCallback(
+    (jsonData) => {
+      switch (jsonData.type) {
+        case "read_analog":
+          if (jsonData.data) {
+            context.setAnalogData(jsonData.data);
+          }
+          break;
+
+        case "read_tag_view":
+          if (jsonData.data) {
+            context.setTagViewData(jsonData.data);
+          }
+          break;
+
+        case "read_memory_view":
+          if (jsonData.data) {
+            context.setMemoryViewData(jsonData.data);
+          }
+          break;
+
+        case "set_rtc":
+          if (jsonData.status === "success") {
+            context.setInfoDialog("Đặt thời gian thành công");
+          } else {
+            context.setInfoDialog("Đặt thời gian thất bại");
+          }
+          break;
+
+        case "get_measure_mode":
+          if (jsonData.mode) {
+            context.setChangeMode(jsonData.mode);
+          }
+          break;
+
+        case "set_measure_mode":
+          if (jsonData.status === "success") {
+            const newMode =
+              context.changeMode === "current" ? "voltage" : "current";
+            context.setChangeMode(newMode);
+
+            ShowInfoDialog(
+              `Đặt chế độ đo ${newMode} thành công`,
+              "Set Measure Mode"
+            );
+          } else {
+            ShowErrorDialog("Đặt chế độ đo thất bại");
+          }
+          break;
+
+        case "get_rtc":
+          if (jsonData.ts) {
+            const tsSeconds = Number(jsonData.ts); // UTC giây
+            const localTimeStr = new Date(tsSeconds * 1000).toLocaleString();
+            context.setInfoDialog(`Thời gian hiện tại: ${localTimeStr}`);
+          }
+          break;
+
+        case "download_config":
+          setFileLoaded("");
+          setDataFile(jsonData);
+          ShowInfoDialog(
+            "Đã download về máy bạn thành công",
+            "Download Config"
+          );
+          break;
+
+        case "upload_config":
+          if (jsonData.status === "success") {
+            if (btnRef.current) {
+              btnRef.current.click();
+            }
+            ShowInfoDialog(
+              "Đã upload lên thiết bị thành công",
+              "Upload Config"
+            );
+          } else {
+            ShowErrorDialog("Upload thất bại");
+          }
+          break;
+
+        case "login":
+          if (jsonData.status === "success") {
+            context.setIsLogin(true);
+            context.setRole(jsonData.role || "user");
+            ShowInfoDialog("Đăng nhập thành công", "Login");
+          } else {
+            ShowErrorDialog("Đăng nhập thất bại");
+          }
+          break;
+
+        case "logout":
+          if (jsonData.status === "success") {
+            context.setIsLogin(false);
+            context.setRole("");
+            ShowInfoDialog("Đăng xuất thành công", "Logout");
+          } else {
+            ShowErrorDialog("Đăng xuất thất bại");
+          }
+          break;
+
+        case "change_password":
+          if (jsonData.status === "success") {
+            ShowInfoDialog("Đổi mật khẩu thành công", "Change Password");
+            setShowModal(false); // Đóng modal khi đổi mật khẩu thành công
+            setOldPassword(""); // Clear form
+            setPasswordInput("");
+            setPasswordConfirm("");
+          } else {
+            ShowErrorDialog(jsonData.message);
+          }
+          break;
+
+        case "reboot":
+          if (jsonData.status === "success") {
+            if (btnRef.current) {
+              btnRef.current.click();
+            }
+            ShowInfoDialog(
+              "Reboot thành công. App sẽ Disconnect khỏi thiết bị",
+              "Reboot"
+            );
+            context.setInfoDialog("");
+          } else {
+            ShowErrorDialog("Reboot thất bại");
+          }
+          break;
+
+        case "network_setting":
+          if (jsonData.status === "success") {
+            ShowInfoDialog(
+              "Cài đặt mạng thành công. Reboot thiết bị để áp dụng thay đổi",
+              "Network Setting"
+            );
+          } else {
+            ShowErrorDialog("Cài đặt mạng thất bại");
+          }
+          break;
+
+        case "network":
+          context.setFormData(jsonData);
+          break;
+
+        case "calib_4ma":
+          if (jsonData.status === "success") {
+            ShowInfoDialog("Calib 4mA thành công", "Calib 4mA");
+          } else {
+            ShowErrorDialog("Calib 4mA thất bại");
+          }
+          break;
+
+        case "calib_16ma":
+          if (jsonData.status === "success") {
+            ShowInfoDialog("Calib 16mA thành công", "Calib 16mA");
+          } else {
+            ShowErrorDialog("Calib 16mA thất bại");
+          }
+          break;
+
+        case "set_digital_output":
+          if (jsonData.status === "success") {
+            ShowInfoDialog(
+              "Đã cập nhật đầu Digital Output thành công",
+              "Set Digital Output"
+            );
+          } else {
+            ShowErrorDialog("Cập nhật đầu Digital Output thất bại");
+          }
+          break;
+
+        case "read_system_info":
+          if (jsonData.data) {
+            context.setInfoDialog(jsonData.data.replaceAll(",", "\n"));
+          } else {
+            context.setInfoDialog("Không có dữ liệu hệ thống");
+          }
+          break;
+
+        case "read_sim_info":
+          if (jsonData.data) {
+            context.setInfoDialog(jsonData.data);
+          } else {
+            context.setInfoDialog("Không có thông tin SIM");
+          }
+          break;
+
+        case "read_sdcard_info":
+          if (jsonData.data) {
+            context.setInfoDialog(jsonData.data);
+          } else {
+            context.setInfoDialog("Không có thông tin thẻ SD");
+          }
+          break;
+
+        case "ping":
+          if (jsonData.status === "success") {
+            context.setInfoDialog("Ping thành công");
+          } else {
+            context.setInfoDialog("Ping thất bại");
+          }
+          break;
+
+        case "write_serial_number":
+          if (jsonData.status === "success") {
+            context.setInfoDialog("Write serial number thành công");
+          } else {
+            context.setInfoDialog("Write serial number thất bại");
+          }
+          break;
+
+        case "write_mac":
+          if (jsonData.status === "success") {
+            context.setInfoDialog("Write mac thành công");
+          } else {
+            context.setInfoDialog("Write mac thất bại");
+          }
+          break;
+
+        case "reset_configuration":
+          if (jsonData.status === "success") {
+            context.setInfoDialog("Reset configuration thành công");
+          } else {
+            context.setInfoDialog("Reset configuration thất bại");
+          }
+          break;
+
+        case "get_gps":
+          if (jsonData.data) {
+            context.setInfoDialog(jsonData.data);
+          } else {
+            context.setInfoDialog("Không có dữ liệu GPS");
+          }
+          break;
+
+        default:
+          break;
+      }
+    },
+    [context.changeMode]
+  );
+
+  useEffect(() => {
+    AuthService.ListPorts()
+      .then(setPorts)
+      .catch((err) => console.log("Get COM error: " + err));
+
+    CaptureService.GetCaptureStatus()
+      .then(setCapture)
+      .catch((err) => console.log("Get capture status error: " + err));
+
+    AuthService.ListSerialProfiles()
+      .then((list) => {
+        setProfiles(list || []);
+        if (list?.length) setSelectedProfile(list[0].name);
+      })
+      .catch((err) => console.log("Get serial profiles error: " + err));
+
+    if (context.selectedPort && context.isConnected) {
+      setStatus("Connected to " + context.selectedPort);
+    } else {
+      AuthService.GetCurrentPort()
+        .then((port) => {
+          if (port) {
+            selectConnection(DeviceService.UseSerial);
+            context.setSelectedPort(port);
+            context.setIsConnected(true);
+            setStatus("Connected to " + port);
+          } else {
+            setStatus("Not connected");
+          }
+        })
+        .catch((err) => console.log("Get current port error: " + err));
+    }
+  }, []);
+
+  useEffect(() => {
+    // Nhận dữ liệu thiết bị qua Wails events cho cả serial và socket
+    if (!context.isConnected && !replay?.active) return;
+
+    const isSelected = (message) => {
+      if (replay?.active && message.kind === replay.kind) {
+        return message.endpoint === replay.endpoint;
+      }
+      return `${message.kind}://${message.endpoint}` === activeConnection;
+    };
+
+    const offMessage = EventsOn("device:message", (message) => {
+      if (!isSelected(message)) return;
+      context.setDataTest(message.raw);
+      handleDataResponse(message.payload);
+    });
+    const offRaw = EventsOn("device:raw", (message) => {
+      if (!isSelected(message)) return;
+      context.setDataTest(message.raw);
+    });
+
+    return () => {
+      offMessage();
+      offRaw();
+    };
+  }, [context.isConnected, activeConnection, handleDataResponse, replay]);
+
+  useEffect(() => {
+    // Theo dõi tiến độ phát lại capture
+    return EventsOn("replay:state", (state) => {
+      setReplay((current) => current && { ...current, ...state });
+      if (!state.active) {
+        setStatus(`Replay finished: ${state.sent}/${state.total} frames`);
+      }
+    });
+  }, []);
+
+  useEffect(() => {
+    // Theo dõi tiến độ upload theo chunk
+    return EventsOn("transfer:progress", (progress) => {
+      if (progress.error) {
+        setStatus(`Upload failed at ${progress.sent}/${progress.chunks} chunks`);
+      } else if (progress.done) {
+        setStatus(
+          `Upload complete: ${progress.total} bytes` +
+            (progress.fallback ? " (device has no chunked transfer, sent in one line)" : "") +
+            (progress.retries ? `, ${progress.retries} retries` : "")
+        );
+      } else {
+        setStatus(
+          `Uploading ${progress.sent}/${progress.chunks} chunks ` +
+            `(${progress.bytes}/${progress.total} bytes)` +
+            (progress.resumed ? `, resumed ${progress.resumed}` : "")
+        );
+      }
+    });
+  }, []);
+
+  useEffect(() => {
+    // Theo dõi trạng thái cổng COM (mất kết nối, đang kết nối lại, ...)
+    return EventsOn("serial:state", (state) => {
+      if (context.selectedConnection !== "serial") return;
+      switch (state.state) {
+        case "connected":
+          context.setIsConnected(true);
+          setStatus("Connected to " + state.port);
+          break;
+        case "lost":
+          setStatus(
+            `Connection to ${state.port} lost, waiting for device` +
+              (state.error ? ` (${state.error})` : "")
+          );
+          break;
+        case "reconnecting":
+          setStatus(`Reconnecting to ${state.port} (attempt ${state.attempts})`);
+          break;
+        case "disconnected":
+          context.setIsConnected(false);
+          setStatus("Not connected");
+          break;
+        default:
+          break;
+      }
+    });
+  }, [context.selectedConnection]);
+
+  useEffect(() => {
+    // Cảnh báo khi bộ đệm nhận của socket bị tràn và phải bỏ dòng cũ
+    return EventsOn("socket:overflow", (overflow) => {
+      if (context.selectedConnection !== "ethernet") return;
+      setStatus(
+        `Receive buffer overflow on ${overflow.key}: ` +
+          `${overflow.stats.dropped} line(s) dropped`
+      );
+    });
+  }, [context.selectedConnection]);
+
+  // Cleanup effect to disconnect when component unmounts
+  useEffect(() => {
+    return () => {
+      if (context.isConnected) {
+        handleDisconnect();
+      }
+    };
+  }, []);
+
+  return (
+    <div className="w-full max-w-xs bg-white border border-gray-300 rounded-lg shadow p-4 flex flex-col gap-3">
+      {showModal && (
+        <div
+          className="fixed inset-0 bg-black/40 flex items-center justify-center z-[1100]"
+          // onClick={() => setShowModal(false)} // Đóng modal khi click nền đen
+        >
+          <div
+            className="bg-white rounded-xl shadow-2xl w-full max-w-xs sm:max-w-sm p-7 relative animate-fadeIn"
+            onClick={(e) => e.stopPropagation()} // Ngăn sự kiện nổi bọt khi click vào modal
+            style={{ boxShadow: "0 8px 32px rgba(0,0,0,0.18)" }}
+          >
+            <div className="flex flex-col items-center mb-4">
+              <span className="text-lg font-semibold text-gray-800">
+                Change Password
+              </span>
+            </div>
+            <input
+              type="password"
+              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
+              value={oldPassword}
+              autoFocus
+              placeholder="Old Password"
+              onChange={(e) => setOldPassword(e.target.value)}
+            />
+            <input
+              type="password"
+              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
+              value={passwordInput}
+              autoFocus
+              placeholder="New Password"
+              onChange={(e) => setPasswordInput(e.target.value)}
+            />
+            <input
+              type="password"
+              className="w-full px-3 py-2 border border-gray-200 rounded-lg mb-5 focus:outline-none focus:border-blue-500 transition"
+              value={passwordConfirm}
+              autoFocus
+              placeholder="Confirm New Password"
+              onChange={(e) => setPasswordConfirm(e.target.value)}
+            />
+            <div className="flex gap-3 mt-2">
+              <button
+                className="flex-1 px-4 py-2 bg-blue-500 text-white rounded-lg font-medium hover:bg-blue-600 transition"
+                onClick={() => {
+                  if (!oldPassword || !passwordInput || !passwordConfirm) {
+                    ShowErrorDialog("Vui lòng điền đầy đủ thông tin");
+                    return;
+                  }
+
+                  if (passwordInput === oldPassword) {
+                    ShowErrorDialog(
+                      "Mật khẩu mới không được trùng với mật khẩu cũ"
+                    );
+                    return;
+                  }
+
+                  if (passwordInput !== passwordConfirm) {
+                    ShowErrorDialog("Mật khẩu không khớp");
+                    return;
+                  }
+                  DeviceService.ChangePassword(oldPassword, passwordInput);
+                }}
+              >
+                Save
+              </button>
+              <button
+                className="flex-1 px-4 py-2 bg-gray-200 text-gray-700 rounded-lg font-medium hover:bg-gray-300 transition"
+                onClick={() => setShowModal(false)} // Đóng modal khi click nút Close
+              >
+                Close
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
+      <div className="flex items-center gap-2 mb-1">
+        <span className="text-base font-semibold">COM Port</span>
+        <span
+          className={
+            status.includes("Connected to")
+              ? "inline-block w-3 h-3 rounded-full bg-green-500 border border-green-700"
+              : status.includes("error")
+              ? "inline-block w-3 h-3 rounded-full bg-red-500 border border-red-700"
+              : "inline-block w-3 h-3 rounded-full bg-gray-400 border border-gray-500"
+          }
+        ></span>
+      </div>
+      <div>
+        <label className="block text-xs text-gray-700 mb-1">Connection</label>
+        <select
+          className="border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
+          value={context.selectedConnection}
+          onChange={(e) => {
+            // Disconnect current connection before switching
+            if (context.isConnected) {
+              handleDisconnect();
+            }
+            context.setSelectedConnection(e.target.value);
+          }}
+        >
+          <option value="">-- Select connection --</option>
+          {["serial", "ethernet"]?.map((type) => (
+            <option key={type} value={type}>
+              {type.toUpperCase()}
+            </option>
+          ))}
+        </select>
+      </div>
+      {context.selectedConnection === "serial" && (
+        <div>
+          <label className="block text-xs text-gray-700 mb-1">
+            Serial port
+          </label>
+          <select
+            className="border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
+            value={context.selectedPort}
+            onChange={(e) => context.setSelectedPort(e.target.value)}
+          >
+            <option value="">-- Select COM port --</option>
+            {ports?.map((port) => (
+              <option key={port.name} value={port.name}>
+                {port.name}
+                {port.product && ` - ${port.product}`}
+                {port.isUsb && port.vid && ` (${port.vid}:${port.pid})`}
+              </option>
+            ))}
+          </select>
+          <button
+            className="mt-1 w-full bg-gray-200 hover:bg-gray-300 text-gray-800 text-xs py-1 rounded disabled:opacity-50"
+            onClick={handleAutoDetect}
+            disabled={isDetecting || context.isConnected}
+          >
+            {isDetecting ? "Detecting..." : "Auto detect"}
+          </button>
+          <label className="block text-xs text-gray-700 mt-2 mb-1">
+            Line settings
+          </label>
+          <select
+            className="border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
+            value={selectedProfile}
+            onChange={(e) => setSelectedProfile(e.target.value)}
+            disabled={context.isConnected}
+          >
+            {profiles?.map((profile) => (
+              <option key={profile.name} value={profile.name}>
+                {profile.name}
+              </option>
+            ))}
+          </select>
+        </div>
+      )}
+      {context.selectedConnection === "ethernet" && (
+        <div>
+          <label className="block text-xs text-gray-700 mb-1">IP Address</label>
+          <input
+            className="mb-1 border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
+            value={context.socketAddress}
+            onChange={(e) => context.setSocketAddress(e.target.value)}
+          />
+          <label className="block text-xs text-gray-700 mb-1">Port</label>
+          <input
+            className="mb-1 border border-gray-300 rounded px-2 py-1 w-full text-xs focus:outline-none focus:ring focus:border-blue-400"
+            value={context.socketPort}
+            onChange={(e) => context.setSocketPort(e.target.value)}
+            type="number"
+          />
+          <button
+            className="mb-1 w-full bg-gray-200 hover:bg-gray-300 text-gray-800 text-xs py-1 rounded disabled:opacity-50"
+            onClick={handleDiscover}
+            disabled={isScanning || context.isSocketConnected}
+          >
+            {isScanning ? "Scanning..." : "Scan LAN"}
+          </button>
+          {discovered.length > 0 && (
+            <ul className="mb-1 border border-gray-300 rounded max-h-32 overflow-y-auto">
+              {discovered.map((device) => (
+                <li
+                  key={`${device.address}:${device.port}`}
+                  className="px-2 py-1 text-xs cursor-pointer hover:bg-blue-100"
+                  onClick={() => selectDiscovered(device)}
+                  title={device.info}
+                >
+                  {device.address}:{device.port}
+                  {device.serial && ` - SN ${device.serial}`}
+                  {device.mac && ` - ${device.mac}`}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      )}
+
+      <div className="text-xs text-gray-500 mb-2 flex flex-col gap-1">
+        <label className="block text-xs text-gray-700">Login</label>
+        <input
+          type="text"
+          value={username}
+          disabled={!context.isConnected}
+          placeholder="Username"
+          onChange={(e) => setUsername(e.target.value)}
+          className="w-full border border-gray-300 rounded px-2 py-1 mb-1 focus:outline-none focus:ring focus:border-blue-400"
+        />
+        <input
+          type="password"
+          value={password}
+          disabled={!context.isConnected}
+          placeholder="Password"
+          onChange={(e) => setPassword(e.target.value)}
+          className="w-full border border-gray-300 rounded px-2 py-1 mb-1 focus:outline-none focus:ring focus:border-blue-400"
+        />
+      </div>
+      <div className="flex flex-col md:flex-row items-center justify-center gap-2 w-full">
+        {!context.isConnected ? (
+          <button
+            onClick={handleConnect}
+            className="flex-1 px-2 w-full bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+          >
+            Connect
+          </button>
+        ) : (
+          <button
+            ref={btnRef}
+            onClick={handleDisconnect}
+            className="flex-1 px-2 w-full bg-gray-200 text-gray-700 py-1 rounded border border-gray-400 hover:bg-gray-300 text-xs transition"
+          >
+            Disconnect
+          </button>
+        )}
+      </div>
+      <div className="flex flex-col md:flex-row items-center justify-center gap-2 w-full">
+        <button
+          onClick={handleLogin}
+          className="flex-1 px-2 w-full bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+          disabled={!context.isConnected}
+        >
+          Login
+        </button>
+      </div>
+      <button
+        onClick={() => setShowModal(true)}
+        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
+    ${
+      context.isConnected && context.isLogin
+        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
+        : "bg-gray-100 text-gray-400 border-gray-300"
+    }
+  `}
+        disabled={!context.isConnected || !context.isLogin}
+      >
+        Change Password
+      </button>
+      <button
+        disabled={!context.isConnected || !context.isLogin}
+        onClick={handleUploadConfig}
+        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
+    ${
+      context.isConnected && context.isLogin
+        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
+        : "bg-gray-100 text-gray-400 border-gray-300"
+    }
+  `}
+      >
+        Upload
+      </button>
+      <button
+        disabled={!context.isConnected || !context.isLogin}
+        onClick={handlePushChanges}
+        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
+    ${
+      context.isConnected && context.isLogin
+        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
+        : "bg-gray-100 text-gray-400 border-gray-300"
+    }
+  `}
+      >
+        Upload changes
+      </button>
+      <button
+        disabled={!context.isConnected || !fileLoaded}
+        onClick={handleDiffConfig}
+        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
+    ${
+      context.isConnected && fileLoaded
+        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
+        : "bg-gray-100 text-gray-400 border-gray-300"
+    }
+  `}
+      >
+        Diff
+      </button>
+      <button
+        disabled={!context.isConnected}
+        onClick={() => DeviceService.DownloadConfig()}
+        className={`flex-1 px-2 w-full py-1 rounded border text-xs transition
+    ${
+      context.isConnected
+        ? "bg-gray-200 text-gray-700 border-gray-400 hover:bg-gray-300 cursor-pointer"
+        : "bg-gray-100 text-gray-400 border-gray-300"
+    }
+  `}
+      >
+        Download
+      </button>
+      <div
+        className={`text-xs text-center ${
+          status.includes("error")
+            ? "text-red-500"
+            : status.includes("Connected to")
+            ? "text-green-600"
+            : "text-gray-700"
+        }`}
+      >
+        {status}
+      </div>
+      <label
+        className="mb-2 flex items-center gap-1 text-xs text-gray-700"
+        title="Send the configuration in acknowledged, CRC-checked chunks (resumable)"
+      >
+        <input
+          type="checkbox"
+          checked={chunkedUpload}
+          onChange={(e) => setChunkedUpload(e.target.checked)}
+        />
+        Chunked upload
+      </label>
+      <button
+        className={`mb-2 w-full text-xs py-1 rounded ${
+          capture?.active
+            ? "bg-red-100 hover:bg-red-200 text-red-700"
+            : "bg-gray-200 hover:bg-gray-300 text-gray-800"
+        }`}
+        onClick={toggleCapture}
+        title="Record every TX/RX frame to a JSONL capture file"
+      >
+        {capture?.active ? "Stop recording traffic" : "Record traffic"}
+      </button>
+      <div className="mb-2 flex gap-1">
+        <select
+          className="flex-1 min-w-0 border border-gray-300 rounded px-1 py-1 text-xs"
+          value={replayFile}
+          onFocus={loadCaptureFiles}
+          onChange={(e) => setReplayFile(e.target.value)}
+          disabled={replay?.active}
+        >
+          <option value="">-- Capture file --</option>
+          {captureFiles.map((file) => (
+            <option key={file.path} value={file.path}>
+              {file.path}
+            </option>
+          ))}
+        </select>
+        <select
+          className="border border-gray-300 rounded px-1 py-1 text-xs"
+          value={replaySpeed}
+          onChange={(e) => setReplaySpeed(e.target.value)}
+          disabled={replay?.active}
+        >
+          <option value={1}>1x</option>
+          <option value={4}>4x</option>
+          <option value={16}>16x</option>
+          <option value={0}>Fast</option>
+        </select>
+        <button
+          className="bg-gray-200 hover:bg-gray-300 text-gray-800 text-xs px-2 rounded disabled:opacity-50"
+          onClick={toggleReplay}
+          disabled={!replayFile}
+        >
+          {replay?.active ? `Stop ${replay.sent}/${replay.total}` : "Replay"}
+        </button>
+      </div>
+      {!!fileLoaded && (
+        <div className="fixed bottom-0 left-0 z-20 p-2 bg-stone-100 shadow-md text-sm text-gray-600">
+          {fileLoaded}
+        </div>
+      )}
+    </div>
+  );
+}
+
+export default ConnectComponent;
@@ -3,11 +3,10 @@ import { ContextMenuContext } from "../store";
 
 import {
   SettingNetwork,
-  GetNetworkInfo,
-  Calib4ma,
-  Calib16ma,
+  QueryNetwork,
+  Calibrate4mA,
+  Calibrate16mA,
   ReadAnalog,
-  StopReadAnalog,
   SetDigitalOutput,
   ReadSystemInfo,
   WriteSerialNumber,
@@ -15,36 +14,14 @@ import {
   ResetConfiguration,
   Reboot,
   ReadSimInfo,
-  ReadSdcardInfo,
+  ReadSdCardInfo,
   Ping,
   GetRTC,
   SetRTC,
   SetMeasureMode,
   GetMeasureMode,
   GetGps,
-} from "../../wailsjs/go/control/ControlService";
-
-import {
-  ReadAnalog as ReadAnalogWS,
-  QueryNetwork,
-  Calibrate4mA,
-  Calibrate16mA,
-  SetDigitalOutputEthernet,
-  ReadSystemInfo as ReadSystemInfoWS,
-  WriteSerialNumber as WriteSerialNumberWS,
-  WriteMacAddress as WriteMacAddressWS,
-  ResetConfiguration as ResetConfigurationWS,
-  RebootDevice as RebootWS,
-  ReadSimInfo as ReadSimInfoWS,
-  ReadSdCardInfo as ReadSdcardInfoWS,
-  PingDevice as PingWS,
-  SettingNetworkEthernet,
-  GetRTC as GetRTCWS,
-  SetRTC as SetRTCWS,
-  SetMeasureMode as SetMeasureModeEthernet,
-  GetMeasureMode as GetMeasureModeWS,
-  GetGps as GetGpsWS,
-} from "../../wailsjs/go/workspace/WorkspaceService";
+} from "../../wailsjs/go/device/DeviceService";
 import { ShowQuestionDialog } from "../../wailsjs/go/main/App";
 
 const Control = () => {
@@ -75,23 +52,11 @@ const Control = () => {
   };
 
   const handleSetNetwork = () => {
-    if (context.selectedConnection === "serial") {
-      SettingNetwork(context.formData);
-    } else if (context.selectedConnection === "ethernet") {
-      SettingNetworkEthernet(
-        context.socketAddress,
-        context.socketPort,
-        context.formData
-      );
-    }
+    SettingNetwork(context.formData);
   };
 
   const handleGetNetwork = () => {
-    if (context.selectedConnection === "serial") {
-      GetNetworkInfo();
-    } else if (context.selectedConnection === "ethernet") {
-      QueryNetwork(context.socketAddress, context.socketPort);
-    }
+    QueryNetwork();
   };
 
   const handleCalib4 = async () => {
@@ -101,11 +66,7 @@ const Control = () => {
     );
 
     if (result === "Yes") {
-      if (context.selectedConnection === "serial") {
-        Calib4ma();
-      } else if (context.selectedConnection === "ethernet") {
-        Calibrate4mA(context.socketAddress, context.socketPort);
-      }
+      Calibrate4mA();
     }
   };
 
@@ -116,9 +77,7 @@ const Control = () => {
     );
 
     if (result === "Yes") {
-      if (context.selectedConnection === "serial") Calib16ma();
-      else if (context.selectedConnection === "ethernet")
-        Calibrate16mA(context.socketAddress, context.socketPort);
+      Calibrate16mA();
     }
   };
 
@@ -287,27 +246,7 @@ const Control = () => {
               className="w-4 h-4"
               checked={context.displayAnalogUnit}
               onChange={(e) => {
-                if (e.target.checked) {
-                  if (context.selectedConnection === "serial") {
-                    ReadAnalog();
-                  } else if (context.selectedConnection === "ethernet") {
-                    ReadAnalogWS(
-                      context.socketAddress,
-                      context.socketPort,
-                      "enable"
-                    );
-                  }
-                } else {
-                  if (context.selectedConnection === "serial") {
-                    StopReadAnalog();
-                  } else if (context.selectedConnection === "ethernet") {
-                    ReadAnalogWS(
-                      context.socketAddress,
-                      context.socketPort,
-                      "disable"
-                    );
-                  }
-                }
+                ReadAnalog(e.target.checked ? "enable" : "disable");
                 context.setDisplayAnalogUnit(e.target.checked);
               }}
             />
@@ -457,15 +396,7 @@ const Control = () => {
   `}
                 style={{ minWidth: 60 }}
                 onClick={() => {
-                  if (context.selectedConnection === "serial") {
-                    SetDigitalOutput(digitalOutput);
-                  } else if (context.selectedConnection === "ethernet") {
-                    SetDigitalOutputEthernet(
-                      context.socketAddress,
-                      context.socketPort,
-                      digitalOutput
-                    );
-                  }
+                  SetDigitalOutput(digitalOutput);
                 }}
               >
                 SET
@@ -519,91 +450,32 @@ const Control = () => {
                       switch (selectedCommand) {
                         case "read_system_info":
                           context.setInfoDialog("Reading system info...");
-                          if (context.selectedConnection === "serial") {
-                            ReadSystemInfo();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            ReadSystemInfoWS(
-                              context.socketAddress,
-                              context.socketPort
-                            );
-                          }
+                          ReadSystemInfo();
                           break;
                         case "write_serial_number":
                           context.setInfoDialog("Writing serial number...");
-                          if (context.selectedConnection === "serial") {
-                            WriteSerialNumber(dataCommand);
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            WriteSerialNumberWS(
-                              context.socketAddress,
-                              context.socketPort,
-                              dataCommand
-                            );
-                          }
+                          WriteSerialNumber(dataCommand);
                           break;
                         case "write_mac":
                           context.setInfoDialog("Writing MAC address...");
-                          if (context.selectedConnection === "serial") {
-                            WriteMacAddress(dataCommand);
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            WriteMacAddressWS(
-                              context.socketAddress,
-                              context.socketPort,
-                              dataCommand
-                            );
-                          }
+                          WriteMacAddress(dataCommand);
                           break;
                         case "reset_configuration":
                           context.setInfoDialog("Resetting configuration...");
-                          if (context.selectedConnection === "serial") {
-                            ResetConfiguration();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            ResetConfigurationWS(
-                              context.socketAddress,
-                              context.socketPort
-                            );
-                          }
+                          ResetConfiguration();
                           break;
                         case "reboot":
                           context.setInfoDialog("Rebooting system...");
-                          if (context.selectedConnection === "serial") {
-                            Reboot();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            RebootWS(context.socketAddress, context.socketPort);
-                          }
+                          Reboot();
                           break;
                         case "read_sim_info":
                           context.setInfoDialog("Reading SIM info...");
-                          if (context.selectedConnection === "serial") {
-                            ReadSimInfo();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            ReadSimInfoWS(
-                              context.socketAddress,
-                              context.socketPort
-                            );
-                          }
+                          ReadSimInfo();
                           break;
 
                         case "get_gps":
                           context.setInfoDialog("Getting GPS data...");
-                          if (context.selectedConnection === "serial") {
-                            GetGps();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            GetGpsWS(context.socketAddress, context.socketPort);
-                          }
+                          GetGps();
                           break;
 
                         case "set_rtc_manual":
@@ -617,73 +489,25 @@ const Control = () => {
 
                           const tsUTC = Math.floor(date.getTime() / 1000);
 
-                          if (context.selectedConnection === "serial") {
-                            SetRTC("manual", tsUTC);
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            SetRTCWS(
-                              context.socketAddress,
-                              context.socketPort,
-                              "manual",
-                              tsUTC
-                            );
-                          }
+                          SetRTC("manual", tsUTC);
                           break;
 
                         case "set_rtc_internet":
                           context.setInfoDialog("Setting RTC via internet...");
-                          if (context.selectedConnection === "serial") {
-                            // Assuming setDataCommand is in the format "YYYY-MM-DD HH:MM:SS"
-                            SetRTC("internet", 0);
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            SetDigitalOutputEthernet(
-                              context.socketAddress,
-                              context.socketPort,
-                              "internet",
-                              0
-                            );
-                          }
+                          SetRTC("internet", 0);
                           break;
 
                         case "get_rtc":
                           context.setInfoDialog("Getting RTC...");
-                          if (context.selectedConnection === "serial") {
-                            GetRTC();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            GetRTCWS(context.socketAddress, context.socketPort);
-                          }
+                          GetRTC();
                           break;
                         case "read_sdcard_info":
                           context.setInfoDialog("Reading SD card info...");
-                          if (context.selectedConnection === "serial") {
-                            ReadSdcardInfo();
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            ReadSdcardInfoWS(
-                              context.socketAddress,
-                              context.socketPort
-                            );
-                          }
+                          ReadSdCardInfo();
                           break;
                         case "ping":
                           context.setInfoDialog("Pinging...");
-                          if (context.selectedConnection === "serial") {
-                            Ping(dataCommand);
-                          } else if (
-                            context.selectedConnection === "ethernet"
-                          ) {
-                            PingWS(
-                              context.socketAddress,
-                              context.socketPort,
-                              dataCommand
-                            );
-                          }
+                          Ping(dataCommand);
                           break;
                         default:
                           break;
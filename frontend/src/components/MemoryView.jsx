@@ -1,12 +1,7 @@
 import React, { useContext, useState, useEffect } from "react";
 import { ContextMenuContext } from "../store";
 
-import {
-  ReadMemoryView,
-  StopReadMemoryView,
-} from "../../wailsjs/go/control/ControlService";
-
-import { ReadMemoryView as ReadMemoryViewWS } from "../../wailsjs/go/workspace/WorkspaceService";
+import { ReadMemoryView } from "../../wailsjs/go/device/DeviceService";
 
 const MemoryView = () => {
   const [loadingPos, setLoadingPos] = useState(0);
@@ -115,30 +110,7 @@ const MemoryView = () => {
           className="custom"
           checked={context.displayMemoryView}
           onChange={(e) => {
-            if (e.target.checked) {
-              if (context.selectedConnection === "serial") {
-                ReadMemoryView();
-              } else if (
-                context.selectedConnection === "ethernet" &&
-                context.isSocketConnected
-              ) {
-                ReadMemoryViewWS(
-                  context.socketAddress,
-                  context.socketPort,
-                  "enable"
-                );
-              }
-            } else {
-              if (context.selectedConnection === "serial") {
-                StopReadMemoryView();
-              } else if (context.selectedConnection === "ethernet") {
-                ReadMemoryViewWS(
-                  context.socketAddress,
-                  context.socketPort,
-                  "disable"
-                );
-              }
-            }
+            ReadMemoryView(e.target.checked ? "enable" : "disable");
             context.setDisplayMemoryView(e.target.checked);
           }}
         />
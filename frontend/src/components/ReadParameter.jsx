@@ -1,7 +1,149 @@
-import { useCallback } from "react";
+import { useCallback, useEffect, useState } from "react";
 import { handleUpdateParameter } from "./functions";
+import {
+  CompileStructuredScript,
+  LintScript,
+  PollModbusRTU,
+  ReadModbusReader,
+  SimulateScript,
+} from "../../wailsjs/go/workspace/WorkspaceService";
+import { ShowErrorDialog, ShowInfoDialog } from "../../wailsjs/go/main/App";
+import { ListPorts } from "../../wailsjs/go/auth/AuthService";
 
-const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
+// Kiểm tra code prog/timers và hiện lỗi kèm dòng, cột
+const checkScript = async (code) => {
+  try {
+    const diagnostics = await LintScript(code || "");
+    if (diagnostics.length === 0) {
+      ShowInfoDialog("Không tìm thấy lỗi", "Check code");
+      return;
+    }
+    const errors = diagnostics.filter((d) => d.severity === "error").length;
+    const lines = diagnostics.map(
+      (d) => `[${d.severity}] dòng ${d.line}, cột ${d.column}: ${d.message}`
+    );
+    ShowInfoDialog(
+      `${errors} lỗi, ${diagnostics.length - errors} cảnh báo\n${lines.join("\n")}`,
+      "Check code"
+    );
+  } catch (error) {
+    ShowErrorDialog(error);
+  }
+};
+
+// Chạy thử code trên datalogger ảo; inputs dạng "0=12, 1=3.2" đặt sẵn giá trị thanh ghi
+const runScript = async (code, inputs) => {
+  const memory = {};
+  for (const part of (inputs || "").split(/[,;\s]+/).filter(Boolean)) {
+    const [index, value] = part.split("=");
+    if (value === undefined || isNaN(Number(index)) || isNaN(Number(value))) {
+      ShowErrorDialog(`Giá trị vào không hợp lệ: ${part} (dạng thanh ghi=giá trị)`);
+      return;
+    }
+    memory[Number(index)] = Number(value);
+  }
+  try {
+    const result = await SimulateScript(code || "", memory);
+    const lines = result.changes.map(
+      // Bộ nhớ lưu float32, làm tròn 7 chữ số để 10.12 không hiện thành 10.119999885559082
+      (c) =>
+        `${c.target}[${c.index}]: ${parseFloat(c.old.toPrecision(7))} -> ${parseFloat(c.new.toPrecision(7))}`
+    );
+    ShowInfoDialog(
+      lines.length > 0 ? lines.join("\n") : "Không có thanh ghi, DO hay timer nào thay đổi",
+      "Run code"
+    );
+  } catch (error) {
+    ShowErrorDialog(error);
+  }
+};
+
+// Biên dịch code có cấu trúc (if/else, switch, const) thành code ba ngôi; trả về code mới hoặc null khi lỗi
+const compileStructured = async (filePath, code) => {
+  try {
+    const result = await CompileStructuredScript(filePath || "", code || "");
+    const lines = result.diagnostics.map(
+      (d) => `[${d.severity}] dòng ${d.line}, cột ${d.column}: ${d.message}`
+    );
+    if (!result.code) {
+      ShowErrorDialog(lines.join("\n") || "Không có code để biên dịch");
+      return null;
+    }
+    ShowInfoDialog(
+      [`Đã chạy thử ${result.verified} trạng thái bộ nhớ, kết quả giống code nguồn`, ...lines].join("\n"),
+      "Compile if/else"
+    );
+    return result.code;
+  } catch (error) {
+    ShowErrorDialog(error);
+    return null;
+  }
+};
+
+// Đọc thử một mục modbus_reader từ máy tính, hiện thanh ghi thô, giá trị giải mã và thanh ghi logger nhận giá trị
+const testModbusRead = async (entry) => {
+  try {
+    const result = await ReadModbusReader(entry, 0);
+    const hex = (r) => "0x" + r.toString(16).toUpperCase().padStart(4, "0");
+    const lines = result.values.map(
+      (v, i) =>
+        `${v.address}: [${v.raw.map(hex).join(" ")}] -> ${v.value} -> memory[${entry.loc_val + i}] = ${parseFloat(v.stored.toPrecision(7))}`
+    );
+    ShowInfoDialog(
+      [`Đọc ${result.values.length} giá trị trong ${result.durationMs} ms`, ...lines].join("\n"),
+      "Modbus read"
+    );
+  } catch (error) {
+    ShowErrorDialog(error);
+  }
+};
+
+// Poll các mục modbus_reader RTU qua cổng nối tiếp với thông số rtu_master, hiện trạng thái từng mục và thống kê lỗi đường truyền
+const pollModbusRTU = async (port, dataFile) => {
+  if (!port) {
+    ShowInfoDialog("Chưa chọn cổng COM", "Poll RTU");
+    return;
+  }
+  try {
+    const report = await PollModbusRTU(port, JSON.stringify(dataFile));
+    const lines = report.results.map((r) => {
+      switch (r.status) {
+        case "ok":
+          return `${r.name}: ${r.result.values.map((v) => `${v.address}=${v.value}`).join(", ")}`;
+        case "exception":
+          return `${r.name}: exception ${r.exception} - ${r.error}`;
+        default:
+          return `${r.name}: [${r.status}] ${r.error}`;
+      }
+    });
+    const { requests, retries, timeouts, crcErrors } = report.stats;
+    ShowInfoDialog(
+      [
+        `${report.ok} mục đọc được, ${report.failed} mục lỗi`,
+        `Yêu cầu: ${requests}, gửi lại: ${retries}, timeout: ${timeouts}, sai CRC: ${crcErrors}`,
+        ...lines,
+      ].join("\n"),
+      "Poll RTU"
+    );
+  } catch (error) {
+    ShowErrorDialog(error);
+  }
+};
+
+const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile, filePath }) => {
+  const [scriptInputs, setScriptInputs] = useState("");
+  const [ports, setPorts] = useState([]);
+  const [rtuPort, setRtuPort] = useState("");
+
+  useEffect(() => {
+    if (parameter?.key !== "rtu_master") return;
+    ListPorts()
+      .then((list) => {
+        setPorts(list || []);
+        if (list?.length) setRtuPort((current) => current || list[0].name);
+      })
+      .catch((err) => console.log("Get COM error: " + err));
+  }, [parameter?.key]);
   const convertStatFlagToDisplay = (statFlag) => {
     if (typeof statFlag !== "number" || statFlag < 0) {
       return ""; // Trả về chuỗi rỗng nếu không phải số hợp lệ
@@ -221,6 +363,34 @@ const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
                           />
                         </td>
                       </tr>
+                      <tr className="">
+                        <td className="px-2 py-1 text-xs text-right font-semibold align-top">
+                          Poll port
+                        </td>
+                        <td className="px-2 py-1 text-xs flex gap-1">
+                          <input
+                            list="rtu-poll-ports"
+                            value={rtuPort}
+                            placeholder="COM3, /dev/ttyUSB0"
+                            onChange={(e) => setRtuPort(e.target.value)}
+                            className="bg-gray-50 border border-gray-300 text-gray-900 text-xs rounded-lg px-2 py-1 w-full"
+                          />
+                          <datalist id="rtu-poll-ports">
+                            {ports.map((port) => (
+                              <option key={port.name} value={port.name}>
+                                {port.product}
+                              </option>
+                            ))}
+                          </datalist>
+                          <button
+                            type="button"
+                            onClick={() => pollModbusRTU(rtuPort, dataFile)}
+                            className="px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition whitespace-nowrap"
+                          >
+                            Poll RTU
+                          </button>
+                        </td>
+                      </tr>
                     </>
                   )}
                 </tbody>
@@ -2275,6 +2445,48 @@ const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
                             className="bg-gray-50 border max-w-[550px] border-gray-300 text-gray-900 text-xs rounded-lg px-2 py-1 w-full resize-y font-mono"
                             placeholder="Enter code here..."
                           />
+                          <button
+                            type="button"
+                            onClick={() => checkScript(parameter.value.code)}
+                            className="mt-1 px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                          >
+                            Check code
+                          </button>
+                          <button
+                            type="button"
+                            onClick={async () => {
+                              const code = await compileStructured(filePath, parameter.value.code);
+                              if (code !== null) {
+                                handleUpdateParameter({
+                                  dataFile,
+                                  setDataFile,
+                                  key: "prog",
+                                  paramKey: "code",
+                                  index: item.idx,
+                                  value: code,
+                                });
+                              }
+                            }}
+                            className="mt-1 ml-1 px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                          >
+                            Compile if/else
+                          </button>
+                          <div className="mt-1 flex gap-1 max-w-[550px]">
+                            <input
+                              type="text"
+                              value={scriptInputs}
+                              onChange={(e) => setScriptInputs(e.target.value)}
+                              className="bg-gray-50 border border-gray-300 text-gray-900 text-xs rounded-lg px-2 py-1 flex-1"
+                              placeholder="Giá trị vào, ví dụ 0=12, 41=2"
+                            />
+                            <button
+                              type="button"
+                              onClick={() => runScript(parameter.value.code, scriptInputs)}
+                              className="px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                            >
+                              Run
+                            </button>
+                          </div>
                         </td>
                       </tr>
                     </>
@@ -2417,6 +2629,48 @@ const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
                             className="bg-gray-50 border max-w-[550px] border-gray-300 text-gray-900 text-xs rounded-lg px-2 py-1 w-full resize-y font-mono"
                             placeholder="Enter code here..."
                           />
+                          <button
+                            type="button"
+                            onClick={() => checkScript(parameter.value.code)}
+                            className="mt-1 px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                          >
+                            Check code
+                          </button>
+                          <button
+                            type="button"
+                            onClick={async () => {
+                              const code = await compileStructured(filePath, parameter.value.code);
+                              if (code !== null) {
+                                handleUpdateParameter({
+                                  dataFile,
+                                  setDataFile,
+                                  key: "timers",
+                                  paramKey: "code",
+                                  index: item.idx,
+                                  value: code,
+                                });
+                              }
+                            }}
+                            className="mt-1 ml-1 px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                          >
+                            Compile if/else
+                          </button>
+                          <div className="mt-1 flex gap-1 max-w-[550px]">
+                            <input
+                              type="text"
+                              value={scriptInputs}
+                              onChange={(e) => setScriptInputs(e.target.value)}
+                              className="bg-gray-50 border border-gray-300 text-gray-900 text-xs rounded-lg px-2 py-1 flex-1"
+                              placeholder="Giá trị vào, ví dụ 0=12, 41=2"
+                            />
+                            <button
+                              type="button"
+                              onClick={() => runScript(parameter.value.code, scriptInputs)}
+                              className="px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                            >
+                              Run
+                            </button>
+                          </div>
                         </td>
                       </tr>
                     </>
@@ -2754,6 +3008,18 @@ const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
                           />
                         </td>
                       </tr>
+                      <tr className="">
+                        <td className="px-2 py-1 text-xs text-right font-semibold"></td>
+                        <td className="px-2 py-1 text-xs">
+                          <button
+                            type="button"
+                            onClick={() => testModbusRead(parameter.value)}
+                            className="px-2 bg-blue-600 text-white py-1 rounded border border-blue-700 hover:bg-blue-700 text-xs transition"
+                          >
+                            Test read
+                          </button>
+                        </td>
+                      </tr>
                     </>
                   )}
                 </tbody>
@@ -2764,7 +3030,7 @@ const ReadParameter = ({ parameter, setParameter, dataFile, setDataFile }) => {
           return null;
       }
     },
-    [parameter, setParameter]
+    [parameter, setParameter, scriptInputs, ports, rtuPort]
   );
 
   return <>{readParameter(parameter)}</>;
@@ -1,12 +1,7 @@
 import React, { useContext, useState, useEffect } from "react";
 import { ContextMenuContext } from "../store";
 
-import {
-  ReadTagView,
-  StopReadTagView,
-} from "../../wailsjs/go/control/ControlService";
-
-import { ReadTagView as ReadTagViewWS } from "../../wailsjs/go/workspace/WorkspaceService";
+import { ReadTagView } from "../../wailsjs/go/device/DeviceService";
 
 const TagView = () => {
   const [loadingPos, setLoadingPos] = useState(0);
@@ -36,27 +31,7 @@ const TagView = () => {
           onChange={(e) => {
             const checked = e.target.checked;
 
-            if (checked) {
-              if (context.selectedConnection === "serial") {
-                ReadTagView();
-              } else if (context.selectedConnection === "ethernet") {
-                ReadTagViewWS(
-                  context.socketAddress,
-                  context.socketPort,
-                  "enable"
-                );
-              }
-            } else {
-              if (context.selectedConnection === "serial") {
-                StopReadTagView();
-              } else if (context.selectedConnection === "ethernet") {
-                ReadTagViewWS(
-                  context.socketAddress,
-                  context.socketPort,
-                  "disable"
-                );
-              }
-            }
+            ReadTagView(checked ? "enable" : "disable");
 
             context.setDisplayTagView(checked);
           }}
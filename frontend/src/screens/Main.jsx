@@ -7,6 +7,11 @@ import {
   SaveJsonFile,
   SaveJsonToPath,
   GetDefaultData,
+  ValidateConfigData,
+  AnalyzeMemoryData,
+  RunScriptTestsData,
+  CompileConfigData,
+  DecompileConfigData,
 } from "../../wailsjs/go/workspace/WorkspaceService";
 import ReadData from "../components/ReadData";
 import ReadParameter from "../components/ReadParameter";
@@ -120,6 +125,14 @@ export default function Main({ onLoginOut }) {
       const cleanPath = normalizeWorkspacePath(fileLoaded);
       await SaveJsonFile(cleanPath, jsonString);
       ShowInfoDialog("Lưu project thành công!", "Save Project");
+      // Chạy lại test script sau mỗi lần lưu, chỉ báo khi có case không đạt (file chưa có test thì bỏ qua)
+      RunScriptTestsData(cleanPath, jsonString)
+        .then((report) => {
+          if (report.failed > 0) {
+            ShowErrorDialog(formatTestReport(report));
+          }
+        })
+        .catch(() => {});
     } catch (error) {
       ShowErrorDialog(error);
     }
@@ -143,6 +156,103 @@ export default function Main({ onLoginOut }) {
     });
   };
 
+  const handleValidate = async () => {
+    if (!dataFile) return;
+
+    try {
+      const issues = (await ValidateConfigData(JSON.stringify(dataFile))) || [];
+      if (issues.length === 0) {
+        ShowInfoDialog("Cấu hình hợp lệ", "Validate");
+        return;
+      }
+      const errors = issues.filter((i) => i.severity === "error").length;
+      const lines = issues
+        .slice(0, 20)
+        .map(
+          (i) =>
+            `[${i.severity === "error" ? "Lỗi" : "Cảnh báo"}] ${
+              i.pointer || "/"
+            }: ${i.message}`
+        );
+      if (issues.length > 20) {
+        lines.push(`... và ${issues.length - 20} mục khác`);
+      }
+      ShowInfoDialog(
+        `${errors} lỗi, ${issues.length - errors} cảnh báo\n${lines.join("\n")}`,
+        "Validate"
+      );
+    } catch (error) {
+      ShowErrorDialog(error);
+    }
+  };
+
+  const formatTestReport = (report) => {
+    const lines = report.cases.map((c) => {
+      const mismatches = c.mismatches.map(
+        (m) =>
+          `${m.target}[${m.index}]: cần ${m.expected}, được ${parseFloat(m.actual.toPrecision(7))}`
+      );
+      const details = [c.error, ...mismatches].filter(Boolean).map((d) => `    ${d}`);
+      return [`${c.passed ? "PASS" : "FAIL"} ${c.name} (${c.target})`, ...details].join("\n");
+    });
+    return `${report.passed} đạt, ${report.failed} lỗi\n${lines.join("\n")}`;
+  };
+
+  const handleScriptTests = async () => {
+    if (!dataFile || !fileLoaded) return;
+
+    try {
+      const report = await RunScriptTestsData(
+        normalizeWorkspacePath(fileLoaded),
+        JSON.stringify(dataFile)
+      );
+      ShowInfoDialog(formatTestReport(report), "Script tests");
+    } catch (error) {
+      ShowErrorDialog(error);
+    }
+  };
+
+  // Đổi tên thanh ghi trong script <-> chỉ số theo bảng <file>.symbols.json trong workspace
+  const handleSymbols = async (compile) => {
+    if (!dataFile || !fileLoaded) return;
+
+    try {
+      const path = normalizeWorkspacePath(fileLoaded);
+      const data = JSON.stringify(dataFile);
+      const result = compile
+        ? await CompileConfigData(path, data)
+        : await DecompileConfigData(path, data);
+      setDataFile(JSON.parse(result));
+    } catch (error) {
+      ShowErrorDialog(error);
+    }
+  };
+
+  const handleMemoryMap = async () => {
+    if (!dataFile) return;
+
+    try {
+      const map = await AnalyzeMemoryData(JSON.stringify(dataFile));
+      if (map.conflicts.length === 0) {
+        ShowInfoDialog(
+          `${map.cells.length} thanh ghi được dùng, không có xung đột`,
+          "Memory map"
+        );
+        return;
+      }
+      const lines = map.conflicts.slice(0, 30).map((c) => `[${c.kind}] ${c.message}`);
+      if (map.conflicts.length > 30) {
+        lines.push(`... và ${map.conflicts.length - 30} mục khác`);
+      }
+      ShowInfoDialog(
+        `${map.cells.length} thanh ghi được dùng, ${map.conflicts.length} xung đột\n${lines.join("\n")}`,
+        "Memory map"
+      );
+    } catch (error) {
+      ShowErrorDialog(error);
+    }
+  };
+
   const handleAction = async (name) => {
     if (!name || !name.trim() || !name.toLowerCase().endsWith(".json")) {
       setInput("");
@@ -252,6 +362,41 @@ export default function Main({ onLoginOut }) {
         >
           Import Project
         </button>
+        <button
+          onClick={handleValidate}
+          disabled={!dataFile}
+          className='rounded-md bg-white border border-gray-300 px-2 py-0.5 text-[10px] font-medium shadow-sm hover:bg-blue-50 hover:border-blue-400 active:bg-blue-100 active:border-blue-400 transition-colors'
+        >
+          Validate
+        </button>
+        <button
+          onClick={handleMemoryMap}
+          disabled={!dataFile}
+          className='rounded-md bg-white border border-gray-300 px-2 py-0.5 text-[10px] font-medium shadow-sm hover:bg-blue-50 hover:border-blue-400 active:bg-blue-100 active:border-blue-400 transition-colors'
+        >
+          Memory map
+        </button>
+        <button
+          onClick={handleScriptTests}
+          disabled={!dataFile || !fileLoaded}
+          className='rounded-md bg-white border border-gray-300 px-2 py-0.5 text-[10px] font-medium shadow-sm hover:bg-blue-50 hover:border-blue-400 active:bg-blue-100 active:border-blue-400 transition-colors'
+        >
+          Script tests
+        </button>
+        <button
+          onClick={() => handleSymbols(false)}
+          disabled={!dataFile || !fileLoaded}
+          className='rounded-md bg-white border border-gray-300 px-2 py-0.5 text-[10px] font-medium shadow-sm hover:bg-blue-50 hover:border-blue-400 active:bg-blue-100 active:border-blue-400 transition-colors'
+        >
+          Decompile symbols
+        </button>
+        <button
+          onClick={() => handleSymbols(true)}
+          disabled={!dataFile || !fileLoaded}
+          className='rounded-md bg-white border border-gray-300 px-2 py-0.5 text-[10px] font-medium shadow-sm hover:bg-blue-50 hover:border-blue-400 active:bg-blue-100 active:border-blue-400 transition-colors'
+        >
+          Compile symbols
+        </button>
       </div>
       <div className='flex-1 mt-2 w-full overflow-hidden flex flex-row'>
         <div className='w-1/4 flex flex-col'>
@@ -360,6 +505,7 @@ export default function Main({ onLoginOut }) {
                 setParameter={setParameter}
                 dataFile={dataFile}
                 setDataFile={setDataFile}
+                filePath={normalizeWorkspacePath(fileLoaded)}
               />
             )}
 
@@ -1,25 +1,28 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {auth} from '../models';
 import {time} from '../models';
 
-export function AddUser(arg1:string,arg2:string):Promise<void>;
+export function AutoDetect(arg1:auth.SerialProfile,arg2:number):Promise<Array<auth.DetectResult>>;
 
-export function ChangePassword(arg1:string,arg2:string):Promise<void>;
+export function ConnectToPort(arg1:string,arg2:auth.SerialProfile):Promise<void>;
 
-export function ConnectToPort(arg1:string):Promise<void>;
+export function DeleteSerialProfile(arg1:string):Promise<void>;
 
 export function Disconnect():Promise<void>;
 
+export function GetConnectionStatus():Promise<auth.SerialStatus>;
+
 export function GetCurrentPort():Promise<string>;
 
-export function GetResponse(arg1:time.Duration):Promise<string>;
+export function GetCurrentProfile():Promise<auth.SerialProfile>;
 
-export function ListPorts():Promise<Array<string>>;
+export function GetResponse(arg1:time.Duration):Promise<string>;
 
-export function Login(arg1:string,arg2:string):Promise<void>;
+export function ListPorts():Promise<Array<auth.PortInfo>>;
 
-export function Logout():Promise<void>;
+export function ListSerialProfiles():Promise<Array<auth.SerialProfile>>;
 
-export function RemoveUser(arg1:string):Promise<void>;
+export function SaveSerialProfile(arg1:auth.SerialProfile):Promise<void>;
 
-export function Send(arg1:string):Promise<void>;
+export function SetAutoReconnect(arg1:boolean):Promise<void>;
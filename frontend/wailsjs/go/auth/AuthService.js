@@ -2,26 +2,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function AddUser(arg1, arg2) {
-  return window['go']['auth']['AuthService']['AddUser'](arg1, arg2);
+export function AutoDetect(arg1, arg2) {
+  return window['go']['auth']['AuthService']['AutoDetect'](arg1, arg2);
 }
 
-export function ChangePassword(arg1, arg2) {
-  return window['go']['auth']['AuthService']['ChangePassword'](arg1, arg2);
+export function ConnectToPort(arg1, arg2) {
+  return window['go']['auth']['AuthService']['ConnectToPort'](arg1, arg2);
 }
 
-export function ConnectToPort(arg1) {
-  return window['go']['auth']['AuthService']['ConnectToPort'](arg1);
+export function DeleteSerialProfile(arg1) {
+  return window['go']['auth']['AuthService']['DeleteSerialProfile'](arg1);
 }
 
 export function Disconnect() {
   return window['go']['auth']['AuthService']['Disconnect']();
 }
 
+export function GetConnectionStatus() {
+  return window['go']['auth']['AuthService']['GetConnectionStatus']();
+}
+
 export function GetCurrentPort() {
   return window['go']['auth']['AuthService']['GetCurrentPort']();
 }
 
+export function GetCurrentProfile() {
+  return window['go']['auth']['AuthService']['GetCurrentProfile']();
+}
+
 export function GetResponse(arg1) {
   return window['go']['auth']['AuthService']['GetResponse'](arg1);
 }
@@ -30,18 +38,14 @@ export function ListPorts() {
   return window['go']['auth']['AuthService']['ListPorts']();
 }
 
-export function Login(arg1, arg2) {
-  return window['go']['auth']['AuthService']['Login'](arg1, arg2);
-}
-
-export function Logout() {
-  return window['go']['auth']['AuthService']['Logout']();
+export function ListSerialProfiles() {
+  return window['go']['auth']['AuthService']['ListSerialProfiles']();
 }
 
-export function RemoveUser(arg1) {
-  return window['go']['auth']['AuthService']['RemoveUser'](arg1);
+export function SaveSerialProfile(arg1) {
+  return window['go']['auth']['AuthService']['SaveSerialProfile'](arg1);
 }
 
-export function Send(arg1) {
-  return window['go']['auth']['AuthService']['Send'](arg1);
+export function SetAutoReconnect(arg1) {
+  return window['go']['auth']['AuthService']['SetAutoReconnect'](arg1);
 }
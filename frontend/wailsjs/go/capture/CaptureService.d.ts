@@ -0,0 +1,21 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {transport} from '../models';
+import {capture} from '../models';
+import {context} from '../models';
+
+export function GetCaptureStatus():Promise<transport.CaptureStatus>;
+
+export function GetReplayStatus():Promise<capture.ReplayStatus>;
+
+export function ListCaptures():Promise<Array<capture.FileInfo>>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
+
+export function StartCapture(arg1:capture.Options):Promise<transport.CaptureStatus>;
+
+export function StartReplay(arg1:capture.ReplayOptions):Promise<capture.ReplayStatus>;
+
+export function StopCapture():Promise<transport.CaptureStatus>;
+
+export function StopReplay():Promise<capture.ReplayStatus>;
@@ -0,0 +1,35 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetCaptureStatus() {
+  return window['go']['capture']['CaptureService']['GetCaptureStatus']();
+}
+
+export function GetReplayStatus() {
+  return window['go']['capture']['CaptureService']['GetReplayStatus']();
+}
+
+export function ListCaptures() {
+  return window['go']['capture']['CaptureService']['ListCaptures']();
+}
+
+export function SetContext(arg1) {
+  return window['go']['capture']['CaptureService']['SetContext'](arg1);
+}
+
+export function StartCapture(arg1) {
+  return window['go']['capture']['CaptureService']['StartCapture'](arg1);
+}
+
+export function StartReplay(arg1) {
+  return window['go']['capture']['CaptureService']['StartReplay'](arg1);
+}
+
+export function StopCapture() {
+  return window['go']['capture']['CaptureService']['StopCapture']();
+}
+
+export function StopReplay() {
+  return window['go']['capture']['CaptureService']['StopReplay']();
+}
@@ -0,0 +1,89 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {config} from '../models';
+import {device} from '../models';
+import {context} from '../models';
+
+export function ActiveConnection():Promise<string>;
+
+export function AddUser(arg1:string,arg2:string):Promise<void>;
+
+export function Calibrate16mA():Promise<void>;
+
+export function Calibrate4mA():Promise<void>;
+
+export function ChangePassword(arg1:string,arg2:string):Promise<void>;
+
+export function ClearConnection():Promise<void>;
+
+export function DownloadConfig():Promise<string>;
+
+export function DownloadSection(arg1:config.SectionRef):Promise<any>;
+
+export function GetGps():Promise<string>;
+
+export function GetMeasureMode():Promise<string>;
+
+export function GetRTC():Promise<device.RTCTime>;
+
+export function Login(arg1:string,arg2:string):Promise<device.LoginResult>;
+
+export function Logout():Promise<void>;
+
+export function Ping(arg1:string):Promise<void>;
+
+export function PushConfigChanges(arg1:config.Config,arg2:boolean):Promise<device.SectionReport>;
+
+export function QueryNetwork():Promise<Record<string, any>>;
+
+export function ReadAnalog(arg1:string):Promise<void>;
+
+export function ReadConfig():Promise<config.Config>;
+
+export function ReadMemoryView(arg1:string):Promise<void>;
+
+export function ReadSdCardInfo():Promise<string>;
+
+export function ReadSimInfo():Promise<string>;
+
+export function ReadSystemInfo():Promise<device.SystemInfo>;
+
+export function ReadTagView(arg1:string):Promise<void>;
+
+export function Reboot():Promise<void>;
+
+export function RemoveUser(arg1:string):Promise<void>;
+
+export function ResetConfiguration():Promise<void>;
+
+export function SendLogout():Promise<void>;
+
+export function SetCommandTimeout(arg1:string,arg2:number):Promise<void>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
+
+export function SetDigitalOutput(arg1:Array<boolean>):Promise<void>;
+
+export function SetMeasureMode(arg1:string):Promise<void>;
+
+export function SetRTC(arg1:string,arg2:number):Promise<void>;
+
+export function SetTime(arg1:Array<number>):Promise<void>;
+
+export function SettingNetwork(arg1:Record<string, any>):Promise<void>;
+
+export function UploadConfig(arg1:string,arg2:boolean):Promise<void>;
+
+export function UploadConfigChunked(arg1:string,arg2:boolean,arg3:device.TransferOptions):Promise<void>;
+
+export function UploadSections(arg1:config.Config,arg2:Array<config.SectionRef>,arg3:boolean):Promise<device.SectionReport>;
+
+export function UseSerial():Promise<void>;
+
+export function UseSocket(arg1:string,arg2:string):Promise<void>;
+
+export function WriteConfig(arg1:config.Config,arg2:boolean):Promise<void>;
+
+export function WriteMacAddress(arg1:string):Promise<void>;
+
+export function WriteSerialNumber(arg1:string):Promise<void>;
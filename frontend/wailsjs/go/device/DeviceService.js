@@ -0,0 +1,171 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function ActiveConnection() {
+  return window['go']['device']['DeviceService']['ActiveConnection']();
+}
+
+export function AddUser(arg1, arg2) {
+  return window['go']['device']['DeviceService']['AddUser'](arg1, arg2);
+}
+
+export function Calibrate16mA() {
+  return window['go']['device']['DeviceService']['Calibrate16mA']();
+}
+
+export function Calibrate4mA() {
+  return window['go']['device']['DeviceService']['Calibrate4mA']();
+}
+
+export function ChangePassword(arg1, arg2) {
+  return window['go']['device']['DeviceService']['ChangePassword'](arg1, arg2);
+}
+
+export function ClearConnection() {
+  return window['go']['device']['DeviceService']['ClearConnection']();
+}
+
+export function DownloadConfig() {
+  return window['go']['device']['DeviceService']['DownloadConfig']();
+}
+
+export function DownloadSection(arg1) {
+  return window['go']['device']['DeviceService']['DownloadSection'](arg1);
+}
+
+export function GetGps() {
+  return window['go']['device']['DeviceService']['GetGps']();
+}
+
+export function GetMeasureMode() {
+  return window['go']['device']['DeviceService']['GetMeasureMode']();
+}
+
+export function GetRTC() {
+  return window['go']['device']['DeviceService']['GetRTC']();
+}
+
+export function Login(arg1, arg2) {
+  return window['go']['device']['DeviceService']['Login'](arg1, arg2);
+}
+
+export function Logout() {
+  return window['go']['device']['DeviceService']['Logout']();
+}
+
+export function Ping(arg1) {
+  return window['go']['device']['DeviceService']['Ping'](arg1);
+}
+
+export function PushConfigChanges(arg1, arg2) {
+  return window['go']['device']['DeviceService']['PushConfigChanges'](arg1, arg2);
+}
+
+export function QueryNetwork() {
+  return window['go']['device']['DeviceService']['QueryNetwork']();
+}
+
+export function ReadAnalog(arg1) {
+  return window['go']['device']['DeviceService']['ReadAnalog'](arg1);
+}
+
+export function ReadConfig() {
+  return window['go']['device']['DeviceService']['ReadConfig']();
+}
+
+export function ReadMemoryView(arg1) {
+  return window['go']['device']['DeviceService']['ReadMemoryView'](arg1);
+}
+
+export function ReadSdCardInfo() {
+  return window['go']['device']['DeviceService']['ReadSdCardInfo']();
+}
+
+export function ReadSimInfo() {
+  return window['go']['device']['DeviceService']['ReadSimInfo']();
+}
+
+export function ReadSystemInfo() {
+  return window['go']['device']['DeviceService']['ReadSystemInfo']();
+}
+
+export function ReadTagView(arg1) {
+  return window['go']['device']['DeviceService']['ReadTagView'](arg1);
+}
+
+export function Reboot() {
+  return window['go']['device']['DeviceService']['Reboot']();
+}
+
+export function RemoveUser(arg1) {
+  return window['go']['device']['DeviceService']['RemoveUser'](arg1);
+}
+
+export function ResetConfiguration() {
+  return window['go']['device']['DeviceService']['ResetConfiguration']();
+}
+
+export function SendLogout() {
+  return window['go']['device']['DeviceService']['SendLogout']();
+}
+
+export function SetCommandTimeout(arg1, arg2) {
+  return window['go']['device']['DeviceService']['SetCommandTimeout'](arg1, arg2);
+}
+
+export function SetContext(arg1) {
+  return window['go']['device']['DeviceService']['SetContext'](arg1);
+}
+
+export function SetDigitalOutput(arg1) {
+  return window['go']['device']['DeviceService']['SetDigitalOutput'](arg1);
+}
+
+export function SetMeasureMode(arg1) {
+  return window['go']['device']['DeviceService']['SetMeasureMode'](arg1);
+}
+
+export function SetRTC(arg1, arg2) {
+  return window['go']['device']['DeviceService']['SetRTC'](arg1, arg2);
+}
+
+export function SetTime(arg1) {
+  return window['go']['device']['DeviceService']['SetTime'](arg1);
+}
+
+export function SettingNetwork(arg1) {
+  return window['go']['device']['DeviceService']['SettingNetwork'](arg1);
+}
+
+export function UploadConfig(arg1, arg2) {
+  return window['go']['device']['DeviceService']['UploadConfig'](arg1, arg2);
+}
+
+export function UploadConfigChunked(arg1, arg2, arg3) {
+  return window['go']['device']['DeviceService']['UploadConfigChunked'](arg1, arg2, arg3);
+}
+
+export function UploadSections(arg1, arg2, arg3) {
+  return window['go']['device']['DeviceService']['UploadSections'](arg1, arg2, arg3);
+}
+
+export function UseSerial() {
+  return window['go']['device']['DeviceService']['UseSerial']();
+}
+
+export function UseSocket(arg1, arg2) {
+  return window['go']['device']['DeviceService']['UseSocket'](arg1, arg2);
+}
+
+export function WriteConfig(arg1, arg2) {
+  return window['go']['device']['DeviceService']['WriteConfig'](arg1, arg2);
+}
+
+export function WriteMacAddress(arg1) {
+  return window['go']['device']['DeviceService']['WriteMacAddress'](arg1);
+}
+
+export function WriteSerialNumber(arg1) {
+  return window['go']['device']['DeviceService']['WriteSerialNumber'](arg1);
+}
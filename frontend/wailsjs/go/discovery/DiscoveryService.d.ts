@@ -0,0 +1,12 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {discovery} from '../models';
+import {context} from '../models';
+
+export function Discover(arg1:discovery.Options):Promise<Array<discovery.Device>>;
+
+export function GetDefaultOptions():Promise<discovery.Options>;
+
+export function LastResult():Promise<Array<discovery.Device>>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
@@ -0,0 +1,19 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Discover(arg1) {
+  return window['go']['discovery']['DiscoveryService']['Discover'](arg1);
+}
+
+export function GetDefaultOptions() {
+  return window['go']['discovery']['DiscoveryService']['GetDefaultOptions']();
+}
+
+export function LastResult() {
+  return window['go']['discovery']['DiscoveryService']['LastResult']();
+}
+
+export function SetContext(arg1) {
+  return window['go']['discovery']['DiscoveryService']['SetContext'](arg1);
+}
@@ -0,0 +1,17 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {context} from '../models';
+
+export function Listen(arg1:string):Promise<void>;
+
+export function Mute(arg1:string):Promise<void>;
+
+export function MutedTypes():Promise<Array<string>>;
+
+export function Startup(arg1:context.Context):Promise<void>;
+
+export function Topics():Promise<Array<string>>;
+
+export function Unlisten(arg1:string):Promise<void>;
+
+export function Unmute(arg1:string):Promise<void>;
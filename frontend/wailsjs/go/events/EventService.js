@@ -0,0 +1,31 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function Listen(arg1) {
+  return window['go']['events']['EventService']['Listen'](arg1);
+}
+
+export function Mute(arg1) {
+  return window['go']['events']['EventService']['Mute'](arg1);
+}
+
+export function MutedTypes() {
+  return window['go']['events']['EventService']['MutedTypes']();
+}
+
+export function Startup(arg1) {
+  return window['go']['events']['EventService']['Startup'](arg1);
+}
+
+export function Topics() {
+  return window['go']['events']['EventService']['Topics']();
+}
+
+export function Unlisten(arg1) {
+  return window['go']['events']['EventService']['Unlisten'](arg1);
+}
+
+export function Unmute(arg1) {
+  return window['go']['events']['EventService']['Unmute'](arg1);
+}
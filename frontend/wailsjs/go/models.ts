@@ -1,3 +1,1762 @@
+export namespace auth {
+	
+	export class PortInfo {
+	    name: string;
+	    isUsb: boolean;
+	    vid: string;
+	    pid: string;
+	    serialNumber: string;
+	    product: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PortInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.isUsb = source["isUsb"];
+	        this.vid = source["vid"];
+	        this.pid = source["pid"];
+	        this.serialNumber = source["serialNumber"];
+	        this.product = source["product"];
+	    }
+	}
+	export class DetectResult {
+	    port: PortInfo;
+	    isLogger: boolean;
+	    info?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DetectResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = this.convertValues(source["port"], PortInfo);
+	        this.isLogger = source["isLogger"];
+	        this.info = source["info"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SerialProfile {
+	    name: string;
+	    baudrate: number;
+	    databits: number;
+	    parity: string;
+	    stopbits: number;
+	    flowcontrol: string;
+	    dtr?: boolean;
+	    rts?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SerialProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.baudrate = source["baudrate"];
+	        this.databits = source["databits"];
+	        this.parity = source["parity"];
+	        this.stopbits = source["stopbits"];
+	        this.flowcontrol = source["flowcontrol"];
+	        this.dtr = source["dtr"];
+	        this.rts = source["rts"];
+	    }
+	}
+	export class SerialStatus {
+	    state: string;
+	    port: string;
+	    profile: string;
+	    error?: string;
+	    attempts: number;
+	    streams: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SerialStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.port = source["port"];
+	        this.profile = source["profile"];
+	        this.error = source["error"];
+	        this.attempts = source["attempts"];
+	        this.streams = source["streams"];
+	    }
+	}
+
+}
+
+export namespace capture {
+	
+	export class FileInfo {
+	    path: string;
+	    size: number;
+	    modified: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modified = source["modified"];
+	    }
+	}
+	export class Options {
+	    path: string;
+	    maxSizeMB: number;
+	    maxFiles: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.maxSizeMB = source["maxSizeMB"];
+	        this.maxFiles = source["maxFiles"];
+	    }
+	}
+	export class ReplayOptions {
+	    path: string;
+	    speed: number;
+	    transport: string;
+	    endpoint: string;
+	    maxGapMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.speed = source["speed"];
+	        this.transport = source["transport"];
+	        this.endpoint = source["endpoint"];
+	        this.maxGapMs = source["maxGapMs"];
+	    }
+	}
+	export class ReplayStatus {
+	    active: boolean;
+	    path: string;
+	    speed: number;
+	    total: number;
+	    sent: number;
+	    positionMs: number;
+	    durationMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.path = source["path"];
+	        this.speed = source["speed"];
+	        this.total = source["total"];
+	        this.sent = source["sent"];
+	        this.positionMs = source["positionMs"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	    }
+	}
+
+}
+
+export namespace config {
+	
+	export class AnalogInput {
+	    measure_mode: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalogInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.measure_mode = source["measure_mode"];
+	    }
+	}
+	export class Line {
+	    op: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Line(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.text = source["text"];
+	    }
+	}
+	export class Change {
+	    section: string;
+	    path: string;
+	    pointer: string;
+	    kind: string;
+	    old?: any;
+	    new?: any;
+	    text?: Line[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Change(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.path = source["path"];
+	        this.pointer = source["pointer"];
+	        this.kind = source["kind"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	        this.text = this.convertValues(source["text"], Line);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Common {
+	    ai_loc: number;
+	    conf_port: number;
+	    di_loc: number;
+	    do_loc: number;
+	    null_ctx: boolean;
+	    page_dur: number;
+	    persist: boolean;
+	    precision: number;
+	    time_sync: number;
+	    data_fields_order: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Common(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ai_loc = source["ai_loc"];
+	        this.conf_port = source["conf_port"];
+	        this.di_loc = source["di_loc"];
+	        this.do_loc = source["do_loc"];
+	        this.null_ctx = source["null_ctx"];
+	        this.page_dur = source["page_dur"];
+	        this.persist = source["persist"];
+	        this.precision = source["precision"];
+	        this.time_sync = source["time_sync"];
+	        this.data_fields_order = source["data_fields_order"];
+	    }
+	}
+	export class TCPSlave {
+	    en: boolean;
+	    id: number;
+	    offset: number;
+	    order: number;
+	    port: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TCPSlave(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.en = source["en"];
+	        this.id = source["id"];
+	        this.offset = source["offset"];
+	        this.order = source["order"];
+	        this.port = source["port"];
+	    }
+	}
+	export class TCPMaster {
+	    conntype: number;
+	    delay: number;
+	    en: boolean;
+	    retry: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TCPMaster(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conntype = source["conntype"];
+	        this.delay = source["delay"];
+	        this.en = source["en"];
+	        this.retry = source["retry"];
+	    }
+	}
+	export class RTUSlave {
+	    baudrate: number;
+	    en: boolean;
+	    id: number;
+	    offset: number;
+	    order: number;
+	    parity: string;
+	    stopbits: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RTUSlave(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baudrate = source["baudrate"];
+	        this.en = source["en"];
+	        this.id = source["id"];
+	        this.offset = source["offset"];
+	        this.order = source["order"];
+	        this.parity = source["parity"];
+	        this.stopbits = source["stopbits"];
+	    }
+	}
+	export class RTUMaster {
+	    baudrate: number;
+	    delay: number;
+	    device: string;
+	    en: boolean;
+	    parity: string;
+	    rddelay: number;
+	    retry: number;
+	    stopbits: number;
+	    wait: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RTUMaster(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baudrate = source["baudrate"];
+	        this.delay = source["delay"];
+	        this.device = source["device"];
+	        this.en = source["en"];
+	        this.parity = source["parity"];
+	        this.rddelay = source["rddelay"];
+	        this.retry = source["retry"];
+	        this.stopbits = source["stopbits"];
+	        this.wait = source["wait"];
+	    }
+	}
+	export class Tag {
+	    avg: number;
+	    desc: string;
+	    en: boolean;
+	    flag: number;
+	    name: string;
+	    stat_flag: number;
+	    stat_idx: number;
+	    unit: string;
+	    val_idx: number;
+	    precision: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.avg = source["avg"];
+	        this.desc = source["desc"];
+	        this.en = source["en"];
+	        this.flag = source["flag"];
+	        this.name = source["name"];
+	        this.stat_flag = source["stat_flag"];
+	        this.stat_idx = source["stat_idx"];
+	        this.unit = source["unit"];
+	        this.val_idx = source["val_idx"];
+	        this.precision = source["precision"];
+	    }
+	}
+	export class Timer {
+	    code: string;
+	    desc: string;
+	    en: boolean;
+	    int: number;
+	    one: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Timer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.desc = source["desc"];
+	        this.en = source["en"];
+	        this.int = source["int"];
+	        this.one = source["one"];
+	    }
+	}
+	export class Program {
+	    code: string;
+	    desc: string;
+	    en: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Program(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.desc = source["desc"];
+	        this.en = source["en"];
+	    }
+	}
+	export class ModbusReader {
+	    d_f: number;
+	    d_o: number;
+	    d_t: number;
+	    desc: string;
+	    dev_a: string;
+	    en: boolean;
+	    id: number;
+	    kf: boolean;
+	    loc_stat: number;
+	    loc_val: number;
+	    n_obj: number;
+	    reg_a: number;
+	    type: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModbusReader(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.d_f = source["d_f"];
+	        this.d_o = source["d_o"];
+	        this.d_t = source["d_t"];
+	        this.desc = source["desc"];
+	        this.dev_a = source["dev_a"];
+	        this.en = source["en"];
+	        this.id = source["id"];
+	        this.kf = source["kf"];
+	        this.loc_stat = source["loc_stat"];
+	        this.loc_val = source["loc_val"];
+	        this.n_obj = source["n_obj"];
+	        this.reg_a = source["reg_a"];
+	        this.type = source["type"];
+	    }
+	}
+	export class FTPCreator {
+	    district: string;
+	    file_type: number;
+	    keep_month: number;
+	    local_prefix: string;
+	    provin: string;
+	    station: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FTPCreator(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.district = source["district"];
+	        this.file_type = source["file_type"];
+	        this.keep_month = source["keep_month"];
+	        this.local_prefix = source["local_prefix"];
+	        this.provin = source["provin"];
+	        this.station = source["station"];
+	    }
+	}
+	export class FTPClient {
+	    assert: boolean;
+	    clone: boolean;
+	    dep: boolean;
+	    global: boolean;
+	    ip: string;
+	    make_dir_type: number;
+	    passwd: string;
+	    port: number;
+	    remote_prefix: string;
+	    user: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FTPClient(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.assert = source["assert"];
+	        this.clone = source["clone"];
+	        this.dep = source["dep"];
+	        this.global = source["global"];
+	        this.ip = source["ip"];
+	        this.make_dir_type = source["make_dir_type"];
+	        this.passwd = source["passwd"];
+	        this.port = source["port"];
+	        this.remote_prefix = source["remote_prefix"];
+	        this.user = source["user"];
+	    }
+	}
+	export class FTP {
+	    client?: FTPClient;
+	    creator?: FTPCreator;
+	    duration: number;
+	    en: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FTP(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.client = this.convertValues(source["client"], FTPClient);
+	        this.creator = this.convertValues(source["creator"], FTPCreator);
+	        this.duration = source["duration"];
+	        this.en = source["en"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DigitalOutput {
+	    act_lev: number;
+	    act_type: number;
+	    duty: number;
+	    memory: number;
+	    period: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DigitalOutput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.act_lev = source["act_lev"];
+	        this.act_type = source["act_type"];
+	        this.duty = source["duty"];
+	        this.memory = source["memory"];
+	        this.period = source["period"];
+	    }
+	}
+	export class DigitalInput {
+	    act_lev: number;
+	    act_type: number;
+	    increment: number;
+	    memory: number;
+	    period: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DigitalInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.act_lev = source["act_lev"];
+	        this.act_type = source["act_type"];
+	        this.increment = source["increment"];
+	        this.memory = source["memory"];
+	        this.period = source["period"];
+	    }
+	}
+	export class Control {
+	    duty: number;
+	    en: boolean;
+	    index: number;
+	    ip: string;
+	    passwd: string;
+	    passwd2: string;
+	    port: number;
+	    port2: number;
+	    type: number;
+	    user: string;
+	    user2: string;
+	    uuid: string;
+	    uuid2: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Control(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.duty = source["duty"];
+	        this.en = source["en"];
+	        this.index = source["index"];
+	        this.ip = source["ip"];
+	        this.passwd = source["passwd"];
+	        this.passwd2 = source["passwd2"];
+	        this.port = source["port"];
+	        this.port2 = source["port2"];
+	        this.type = source["type"];
+	        this.user = source["user"];
+	        this.user2 = source["user2"];
+	        this.uuid = source["uuid"];
+	        this.uuid2 = source["uuid2"];
+	    }
+	}
+	export class Config {
+	    common?: Common;
+	    control?: Control;
+	    ais: AnalogInput[];
+	    dis: DigitalInput[];
+	    dos: DigitalOutput[];
+	    ftp: FTP[];
+	    modbus_reader: ModbusReader[];
+	    prog: Program[];
+	    timers: Timer[];
+	    tags: Tag[];
+	    rtu_master?: RTUMaster;
+	    rtu_slave?: RTUSlave;
+	    tcp_master?: TCPMaster;
+	    tcp_slave?: TCPSlave;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.common = this.convertValues(source["common"], Common);
+	        this.control = this.convertValues(source["control"], Control);
+	        this.ais = this.convertValues(source["ais"], AnalogInput);
+	        this.dis = this.convertValues(source["dis"], DigitalInput);
+	        this.dos = this.convertValues(source["dos"], DigitalOutput);
+	        this.ftp = this.convertValues(source["ftp"], FTP);
+	        this.modbus_reader = this.convertValues(source["modbus_reader"], ModbusReader);
+	        this.prog = this.convertValues(source["prog"], Program);
+	        this.timers = this.convertValues(source["timers"], Timer);
+	        this.tags = this.convertValues(source["tags"], Tag);
+	        this.rtu_master = this.convertValues(source["rtu_master"], RTUMaster);
+	        this.rtu_slave = this.convertValues(source["rtu_slave"], RTUSlave);
+	        this.tcp_master = this.convertValues(source["tcp_master"], TCPMaster);
+	        this.tcp_slave = this.convertValues(source["tcp_slave"], TCPSlave);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SectionDiff {
+	    name: string;
+	    changes: Change[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.changes = this.convertValues(source["changes"], Change);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Diff {
+	    sections: SectionDiff[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Diff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sections = this.convertValues(source["sections"], SectionDiff);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	
+	export class Issue {
+	    pointer: string;
+	    severity: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Issue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pointer = source["pointer"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	export class MemoryRef {
+	    index: number;
+	    access: string;
+	    source: string;
+	    pointer: string;
+	    line?: number;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.access = source["access"];
+	        this.source = source["source"];
+	        this.pointer = source["pointer"];
+	        this.line = source["line"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class MemoryCell {
+	    index: number;
+	    readers: MemoryRef[];
+	    writers: MemoryRef[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.readers = this.convertValues(source["readers"], MemoryRef);
+	        this.writers = this.convertValues(source["writers"], MemoryRef);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MemoryConflict {
+	    kind: string;
+	    index: number;
+	    message: string;
+	    refs: MemoryRef[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.index = source["index"];
+	        this.message = source["message"];
+	        this.refs = this.convertValues(source["refs"], MemoryRef);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MemoryMap {
+	    size: number;
+	    cells: MemoryCell[];
+	    conflicts: MemoryConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryMap(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.size = source["size"];
+	        this.cells = this.convertValues(source["cells"], MemoryCell);
+	        this.conflicts = this.convertValues(source["conflicts"], MemoryConflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Mismatch {
+	    target: string;
+	    index: number;
+	    expected: number;
+	    actual: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Mismatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.index = source["index"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	    }
+	}
+	
+	
+	
+	
+	export class ScriptCaseResult {
+	    name: string;
+	    target: string;
+	    passed: boolean;
+	    error?: string;
+	    mismatches: Mismatch[];
+	    changes: script.Change[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptCaseResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.target = source["target"];
+	        this.passed = source["passed"];
+	        this.error = source["error"];
+	        this.mismatches = this.convertValues(source["mismatches"], Mismatch);
+	        this.changes = this.convertValues(source["changes"], script.Change);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScriptTestReport {
+	    passed: number;
+	    failed: number;
+	    cases: ScriptCaseResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptTestReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passed = source["passed"];
+	        this.failed = source["failed"];
+	        this.cases = this.convertValues(source["cases"], ScriptCaseResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SectionRef {
+	    name: string;
+	    index?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.index = source["index"];
+	    }
+	}
+	export class SimulationInput {
+	    ai: number[];
+	    di: boolean[];
+	    memory: Record<number, number>;
+	    cycles: number;
+	    all: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimulationInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ai = source["ai"];
+	        this.di = source["di"];
+	        this.memory = source["memory"];
+	        this.cycles = source["cycles"];
+	        this.all = source["all"];
+	    }
+	}
+	
+	
+	
+
+}
+
+export namespace device {
+	
+	export class LoginResult {
+	    status: string;
+	    role: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoginResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.role = source["role"];
+	    }
+	}
+	export class RTCTime {
+	    ts: number;
+	    time: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RTCTime(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ts = source["ts"];
+	        this.time = source["time"];
+	    }
+	}
+	export class SectionResult {
+	    section: config.SectionRef;
+	    status: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = this.convertValues(source["section"], config.SectionRef);
+	        this.status = source["status"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SectionReport {
+	    results: SectionResult[];
+	    success: boolean;
+	    rolledBack: boolean;
+	    fallback: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.results = this.convertValues(source["results"], SectionResult);
+	        this.success = source["success"];
+	        this.rolledBack = source["rolledBack"];
+	        this.fallback = source["fallback"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SystemInfo {
+	    raw: string;
+	    lines: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.raw = source["raw"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class TransferOptions {
+	    chunkSize: number;
+	    retries: number;
+	    timeoutMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TransferOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chunkSize = source["chunkSize"];
+	        this.retries = source["retries"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+
+}
+
+export namespace discovery {
+	
+	export class Device {
+	    address: string;
+	    port: number;
+	    serial: string;
+	    mac: string;
+	    methods: string[];
+	    info?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Device(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.address = source["address"];
+	        this.port = source["port"];
+	        this.serial = source["serial"];
+	        this.mac = source["mac"];
+	        this.methods = source["methods"];
+	        this.info = source["info"];
+	    }
+	}
+	export class Options {
+	    port: number;
+	    timeoutMs: number;
+	    broadcast: boolean;
+	    sweep: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.timeoutMs = source["timeoutMs"];
+	        this.broadcast = source["broadcast"];
+	        this.sweep = source["sweep"];
+	    }
+	}
+
+}
+
+export namespace modbus {
+	
+	export class RTUStats {
+	    requests: number;
+	    retries: number;
+	    timeouts: number;
+	    crcErrors: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RTUStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requests = source["requests"];
+	        this.retries = source["retries"];
+	        this.timeouts = source["timeouts"];
+	        this.crcErrors = source["crcErrors"];
+	    }
+	}
+	export class Value {
+	    address: number;
+	    raw: number[];
+	    value: number;
+	    stored: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Value(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.address = source["address"];
+	        this.raw = source["raw"];
+	        this.value = source["value"];
+	        this.stored = source["stored"];
+	    }
+	}
+	export class ReadResult {
+	    query: Query;
+	    raw: number[];
+	    values: Value[];
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReadResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = this.convertValues(source["query"], Query);
+	        this.raw = source["raw"];
+	        this.values = this.convertValues(source["values"], Value);
+	        this.durationMs = source["durationMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Query {
+	    unit: number;
+	    dataType: number;
+	    address: number;
+	    count: number;
+	    format: number;
+	    order: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Query(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.unit = source["unit"];
+	        this.dataType = source["dataType"];
+	        this.address = source["address"];
+	        this.count = source["count"];
+	        this.format = source["format"];
+	        this.order = source["order"];
+	    }
+	}
+	export class PollResult {
+	    name: string;
+	    query: Query;
+	    status: string;
+	    exception?: number;
+	    error?: string;
+	    result?: ReadResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new PollResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.query = this.convertValues(source["query"], Query);
+	        this.status = source["status"];
+	        this.exception = source["exception"];
+	        this.error = source["error"];
+	        this.result = this.convertValues(source["result"], ReadResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PollReport {
+	    ok: number;
+	    failed: number;
+	    results: PollResult[];
+	    stats: RTUStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new PollReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.failed = source["failed"];
+	        this.results = this.convertValues(source["results"], PollResult);
+	        this.stats = this.convertValues(source["stats"], RTUStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+
+}
+
+export namespace script {
+	
+	export class Change {
+	    target: string;
+	    index: number;
+	    old: number;
+	    new: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Change(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.index = source["index"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class Diagnostic {
+	    line: number;
+	    column: number;
+	    severity: string;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Diagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.severity = source["severity"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class CompileResult {
+	    code: string;
+	    diagnostics: Diagnostic[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CompileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], Diagnostic);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Event {
+	    source?: string;
+	    target: string;
+	    index: number;
+	    value: number;
+	    line: number;
+	    column: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Event(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.index = source["index"];
+	        this.value = source["value"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class Result {
+	    memory: number[];
+	    outputs: boolean[];
+	    timers: boolean[];
+	    changes: Change[];
+	    events: Event[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.memory = source["memory"];
+	        this.outputs = source["outputs"];
+	        this.timers = source["timers"];
+	        this.changes = this.convertValues(source["changes"], Change);
+	        this.events = this.convertValues(source["events"], Event);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StructuredResult {
+	    code: string;
+	    diagnostics: Diagnostic[];
+	    verified: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StructuredResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], Diagnostic);
+	        this.verified = source["verified"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Symbol {
+	    name: string;
+	    index: number;
+	    desc?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Symbol(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.index = source["index"];
+	        this.desc = source["desc"];
+	    }
+	}
+	export class SymbolTable {
+	    symbols: Symbol[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SymbolTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbols = this.convertValues(source["symbols"], Symbol);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace transport {
+	
+	export class BufferStats {
+	    capacity: number;
+	    length: number;
+	    received: number;
+	    dropped: number;
+	    oversize: number;
+	    highWater: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BufferStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.capacity = source["capacity"];
+	        this.length = source["length"];
+	        this.received = source["received"];
+	        this.dropped = source["dropped"];
+	        this.oversize = source["oversize"];
+	        this.highWater = source["highWater"];
+	    }
+	}
+	export class CaptureStatus {
+	    active: boolean;
+	    path: string;
+	    maxBytes: number;
+	    maxFiles: number;
+	    bytes: number;
+	    frames: number;
+	    rotations: number;
+	    startedAt?: string;
+	    lastError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.path = source["path"];
+	        this.maxBytes = source["maxBytes"];
+	        this.maxFiles = source["maxFiles"];
+	        this.bytes = source["bytes"];
+	        this.frames = source["frames"];
+	        this.rotations = source["rotations"];
+	        this.startedAt = source["startedAt"];
+	        this.lastError = source["lastError"];
+	    }
+	}
+	export class ReconnectPolicy {
+	    enabled: boolean;
+	    keepAliveSeconds: number;
+	    initialDelayMs: number;
+	    maxDelayMs: number;
+	    multiplier: number;
+	    maxAttempts: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconnectPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.keepAliveSeconds = source["keepAliveSeconds"];
+	        this.initialDelayMs = source["initialDelayMs"];
+	        this.maxDelayMs = source["maxDelayMs"];
+	        this.multiplier = source["multiplier"];
+	        this.maxAttempts = source["maxAttempts"];
+	    }
+	}
+	export class SocketStatus {
+	    key: string;
+	    state: string;
+	    reconnects: number;
+	    attempts: number;
+	    lastError?: string;
+	    connectedAt?: string;
+	    lastRxAt?: string;
+	    loggedIn: boolean;
+	    streams: string[];
+	    buffer: BufferStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new SocketStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.state = source["state"];
+	        this.reconnects = source["reconnects"];
+	        this.attempts = source["attempts"];
+	        this.lastError = source["lastError"];
+	        this.connectedAt = source["connectedAt"];
+	        this.lastRxAt = source["lastRxAt"];
+	        this.loggedIn = source["loggedIn"];
+	        this.streams = source["streams"];
+	        this.buffer = this.convertValues(source["buffer"], BufferStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace workspace {
 	
 	export class FileNode {
@@ -1,43 +1,53 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {config} from '../models';
+import {script} from '../models';
+import {transport} from '../models';
 import {workspace} from '../models';
+import {modbus} from '../models';
 import {context} from '../models';
 
-export function Calibrate16mA(arg1:string,arg2:string):Promise<void>;
+export function AnalyzeMemoryData(arg1:string):Promise<config.MemoryMap>;
 
-export function Calibrate4mA(arg1:string,arg2:string):Promise<void>;
-
-export function ChangePassword(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+export function AnalyzeMemoryFile(arg1:string):Promise<config.MemoryMap>;
 
 export function CheckSocketConnection(arg1:string,arg2:string):Promise<boolean>;
 
+export function CompileConfigData(arg1:string,arg2:string):Promise<string>;
+
+export function CompileScript(arg1:string,arg2:string):Promise<script.CompileResult>;
+
+export function CompileStructuredScript(arg1:string,arg2:string):Promise<script.StructuredResult>;
+
 export function ConnectSocket(arg1:string,arg2:string):Promise<string>;
 
 export function CreateFolder(arg1:string):Promise<string>;
 
+export function DecompileConfigData(arg1:string,arg2:string):Promise<string>;
+
+export function DecompileScript(arg1:string,arg2:string):Promise<string>;
+
 export function DeleteFile(arg1:string):Promise<void>;
 
 export function DeleteItem(arg1:string):Promise<void>;
 
-export function DisconnectSocket(arg1:string,arg2:string):Promise<void>;
+export function DiffConfigFiles(arg1:string,arg2:string):Promise<config.Diff>;
 
-export function DownloadConfig():Promise<void>;
+export function DiffDeviceConfig(arg1:string):Promise<config.Diff>;
 
-export function DownloadConfigEthernet(arg1:string,arg2:string):Promise<void>;
+export function DisconnectSocket(arg1:string,arg2:string):Promise<void>;
 
 export function ExportJSONFile(arg1:string,arg2:string):Promise<void>;
 
-export function GetAllSocketData(arg1:string,arg2:string):Promise<Array<string>>;
+export function GetDefaultConfig():Promise<config.Config>;
 
 export function GetDefaultData():Promise<string>;
 
-export function GetGps(arg1:string,arg2:string):Promise<void>;
+export function GetSocketBufferStats(arg1:string,arg2:string):Promise<transport.BufferStats>;
 
-export function GetMeasureMode(arg1:string,arg2:string):Promise<void>;
+export function GetSocketReconnectPolicy():Promise<transport.ReconnectPolicy>;
 
-export function GetRTC(arg1:string,arg2:string):Promise<void>;
-
-export function GetSocketData(arg1:string,arg2:string):Promise<string>;
+export function GetSocketStatus(arg1:string,arg2:string):Promise<transport.SocketStatus>;
 
 export function GetWorkspacePath():Promise<string>;
 
@@ -45,64 +55,58 @@ export function ImportFileToFolderInWorkspace(arg1:string,arg2:string):Promise<v
 
 export function ImportFileToWorkspace(arg1:string,arg2:string):Promise<void>;
 
+export function LintScript(arg1:string):Promise<Array<script.Diagnostic>>;
+
 export function ListActiveConnections():Promise<Array<string>>;
 
 export function ListFiles():Promise<Array<workspace.FileNode>>;
 
-export function Login(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
-
-export function Logout(arg1:string,arg2:string):Promise<void>;
+export function ListSocketStatus():Promise<Array<transport.SocketStatus>>;
 
 export function NewProject(arg1:string):Promise<void>;
 
 export function Paste(arg1:string,arg2:string):Promise<void>;
 
-export function PingDevice(arg1:string,arg2:string,arg3:string):Promise<void>;
-
-export function QueryNetwork(arg1:string,arg2:string):Promise<void>;
+export function PollModbusRTU(arg1:string,arg2:string):Promise<modbus.PollReport>;
 
-export function ReadAnalog(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function ReadConfigFile(arg1:string):Promise<config.Config>;
 
 export function ReadFile(arg1:string):Promise<string>;
 
-export function ReadMemoryView(arg1:string,arg2:string,arg3:string):Promise<void>;
-
-export function ReadSdCardInfo(arg1:string,arg2:string):Promise<void>;
+export function ReadModbusReader(arg1:config.ModbusReader,arg2:number):Promise<modbus.ReadResult>;
 
-export function ReadSimInfo(arg1:string,arg2:string):Promise<void>;
+export function ReadSymbols(arg1:string):Promise<script.SymbolTable>;
 
-export function ReadSystemInfo(arg1:string,arg2:string):Promise<void>;
-
-export function ReadTagView(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function RenameItem(arg1:string,arg2:string):Promise<void>;
 
-export function RebootDevice(arg1:string,arg2:string):Promise<void>;
+export function RunScriptTests(arg1:string):Promise<config.ScriptTestReport>;
 
-export function RenameItem(arg1:string,arg2:string):Promise<void>;
+export function RunScriptTestsData(arg1:string,arg2:string):Promise<config.ScriptTestReport>;
 
-export function ResetConfiguration(arg1:string,arg2:string):Promise<void>;
+export function SaveConfigFile(arg1:string,arg2:config.Config):Promise<void>;
 
 export function SaveJsonFile(arg1:string,arg2:string):Promise<void>;
 
 export function SaveJsonToPath(arg1:string,arg2:string):Promise<void>;
 
+export function SaveSymbols(arg1:string,arg2:script.SymbolTable):Promise<void>;
+
 export function SendSocketData(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function SetContext(arg1:context.Context):Promise<void>;
 
-export function SetDigitalOutputEthernet(arg1:string,arg2:string,arg3:Array<boolean>):Promise<void>;
-
-export function SetMeasureMode(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function SetSocketBufferCapacity(arg1:number):Promise<void>;
 
-export function SetRTC(arg1:string,arg2:string,arg3:string,arg4:number):Promise<void>;
-
-export function SettingNetworkEthernet(arg1:string,arg2:string,arg3:Record<string, any>):Promise<void>;
+export function SetSocketReconnectPolicy(arg1:transport.ReconnectPolicy):Promise<void>;
 
 export function ShowInExplorer(arg1:string):Promise<void>;
 
-export function UploadConfig(arg1:string):Promise<void>;
+export function SimulateConfigData(arg1:string,arg2:config.SimulationInput):Promise<script.Result>;
+
+export function SimulateConfigFile(arg1:string,arg2:config.SimulationInput):Promise<script.Result>;
 
-export function UploadConfigEthernet(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function SimulateScript(arg1:string,arg2:Record<number, number>):Promise<script.Result>;
 
-export function WriteMacAddress(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function ValidateConfig(arg1:config.Config):Promise<Array<config.Issue>>;
 
-export function WriteSerialNumber(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function ValidateConfigData(arg1:string):Promise<Array<config.Issue>>;
@@ -2,22 +2,30 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function Calibrate16mA(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['Calibrate16mA'](arg1, arg2);
+export function AnalyzeMemoryData(arg1) {
+  return window['go']['workspace']['WorkspaceService']['AnalyzeMemoryData'](arg1);
 }
 
-export function Calibrate4mA(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['Calibrate4mA'](arg1, arg2);
-}
-
-export function ChangePassword(arg1, arg2, arg3, arg4) {
-  return window['go']['workspace']['WorkspaceService']['ChangePassword'](arg1, arg2, arg3, arg4);
+export function AnalyzeMemoryFile(arg1) {
+  return window['go']['workspace']['WorkspaceService']['AnalyzeMemoryFile'](arg1);
 }
 
 export function CheckSocketConnection(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['CheckSocketConnection'](arg1, arg2);
 }
 
+export function CompileConfigData(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['CompileConfigData'](arg1, arg2);
+}
+
+export function CompileScript(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['CompileScript'](arg1, arg2);
+}
+
+export function CompileStructuredScript(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['CompileStructuredScript'](arg1, arg2);
+}
+
 export function ConnectSocket(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['ConnectSocket'](arg1, arg2);
 }
@@ -26,6 +34,14 @@ export function CreateFolder(arg1) {
   return window['go']['workspace']['WorkspaceService']['CreateFolder'](arg1);
 }
 
+export function DecompileConfigData(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['DecompileConfigData'](arg1, arg2);
+}
+
+export function DecompileScript(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['DecompileScript'](arg1, arg2);
+}
+
 export function DeleteFile(arg1) {
   return window['go']['workspace']['WorkspaceService']['DeleteFile'](arg1);
 }
@@ -34,44 +50,40 @@ export function DeleteItem(arg1) {
   return window['go']['workspace']['WorkspaceService']['DeleteItem'](arg1);
 }
 
-export function DisconnectSocket(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['DisconnectSocket'](arg1, arg2);
+export function DiffConfigFiles(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['DiffConfigFiles'](arg1, arg2);
 }
 
-export function DownloadConfig() {
-  return window['go']['workspace']['WorkspaceService']['DownloadConfig']();
+export function DiffDeviceConfig(arg1) {
+  return window['go']['workspace']['WorkspaceService']['DiffDeviceConfig'](arg1);
 }
 
-export function DownloadConfigEthernet(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['DownloadConfigEthernet'](arg1, arg2);
+export function DisconnectSocket(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['DisconnectSocket'](arg1, arg2);
 }
 
 export function ExportJSONFile(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['ExportJSONFile'](arg1, arg2);
 }
 
-export function GetAllSocketData(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['GetAllSocketData'](arg1, arg2);
+export function GetDefaultConfig() {
+  return window['go']['workspace']['WorkspaceService']['GetDefaultConfig']();
 }
 
 export function GetDefaultData() {
   return window['go']['workspace']['WorkspaceService']['GetDefaultData']();
 }
 
-export function GetGps(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['GetGps'](arg1, arg2);
-}
-
-export function GetMeasureMode(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['GetMeasureMode'](arg1, arg2);
+export function GetSocketBufferStats(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['GetSocketBufferStats'](arg1, arg2);
 }
 
-export function GetRTC(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['GetRTC'](arg1, arg2);
+export function GetSocketReconnectPolicy() {
+  return window['go']['workspace']['WorkspaceService']['GetSocketReconnectPolicy']();
 }
 
-export function GetSocketData(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['GetSocketData'](arg1, arg2);
+export function GetSocketStatus(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['GetSocketStatus'](arg1, arg2);
 }
 
 export function GetWorkspacePath() {
@@ -86,6 +98,10 @@ export function ImportFileToWorkspace(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['ImportFileToWorkspace'](arg1, arg2);
 }
 
+export function LintScript(arg1) {
+  return window['go']['workspace']['WorkspaceService']['LintScript'](arg1);
+}
+
 export function ListActiveConnections() {
   return window['go']['workspace']['WorkspaceService']['ListActiveConnections']();
 }
@@ -94,12 +110,8 @@ export function ListFiles() {
   return window['go']['workspace']['WorkspaceService']['ListFiles']();
 }
 
-export function Login(arg1, arg2, arg3, arg4) {
-  return window['go']['workspace']['WorkspaceService']['Login'](arg1, arg2, arg3, arg4);
-}
-
-export function Logout(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['Logout'](arg1, arg2);
+export function ListSocketStatus() {
+  return window['go']['workspace']['WorkspaceService']['ListSocketStatus']();
 }
 
 export function NewProject(arg1) {
@@ -110,52 +122,40 @@ export function Paste(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['Paste'](arg1, arg2);
 }
 
-export function PingDevice(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['PingDevice'](arg1, arg2, arg3);
+export function PollModbusRTU(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['PollModbusRTU'](arg1, arg2);
 }
 
-export function QueryNetwork(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['QueryNetwork'](arg1, arg2);
-}
-
-export function ReadAnalog(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['ReadAnalog'](arg1, arg2, arg3);
+export function ReadConfigFile(arg1) {
+  return window['go']['workspace']['WorkspaceService']['ReadConfigFile'](arg1);
 }
 
 export function ReadFile(arg1) {
   return window['go']['workspace']['WorkspaceService']['ReadFile'](arg1);
 }
 
-export function ReadMemoryView(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['ReadMemoryView'](arg1, arg2, arg3);
-}
-
-export function ReadSdCardInfo(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['ReadSdCardInfo'](arg1, arg2);
-}
-
-export function ReadSimInfo(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['ReadSimInfo'](arg1, arg2);
+export function ReadModbusReader(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['ReadModbusReader'](arg1, arg2);
 }
 
-export function ReadSystemInfo(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['ReadSystemInfo'](arg1, arg2);
+export function ReadSymbols(arg1) {
+  return window['go']['workspace']['WorkspaceService']['ReadSymbols'](arg1);
 }
 
-export function ReadTagView(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['ReadTagView'](arg1, arg2, arg3);
+export function RenameItem(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['RenameItem'](arg1, arg2);
 }
 
-export function RebootDevice(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['RebootDevice'](arg1, arg2);
+export function RunScriptTests(arg1) {
+  return window['go']['workspace']['WorkspaceService']['RunScriptTests'](arg1);
 }
 
-export function RenameItem(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['RenameItem'](arg1, arg2);
+export function RunScriptTestsData(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['RunScriptTestsData'](arg1, arg2);
 }
 
-export function ResetConfiguration(arg1, arg2) {
-  return window['go']['workspace']['WorkspaceService']['ResetConfiguration'](arg1, arg2);
+export function SaveConfigFile(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['SaveConfigFile'](arg1, arg2);
 }
 
 export function SaveJsonFile(arg1, arg2) {
@@ -166,6 +166,10 @@ export function SaveJsonToPath(arg1, arg2) {
   return window['go']['workspace']['WorkspaceService']['SaveJsonToPath'](arg1, arg2);
 }
 
+export function SaveSymbols(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['SaveSymbols'](arg1, arg2);
+}
+
 export function SendSocketData(arg1, arg2, arg3) {
   return window['go']['workspace']['WorkspaceService']['SendSocketData'](arg1, arg2, arg3);
 }
@@ -174,38 +178,34 @@ export function SetContext(arg1) {
   return window['go']['workspace']['WorkspaceService']['SetContext'](arg1);
 }
 
-export function SetDigitalOutputEthernet(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['SetDigitalOutputEthernet'](arg1, arg2, arg3);
-}
-
-export function SetMeasureMode(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['SetMeasureMode'](arg1, arg2, arg3);
-}
-
-export function SetRTC(arg1, arg2, arg3, arg4) {
-  return window['go']['workspace']['WorkspaceService']['SetRTC'](arg1, arg2, arg3, arg4);
+export function SetSocketBufferCapacity(arg1) {
+  return window['go']['workspace']['WorkspaceService']['SetSocketBufferCapacity'](arg1);
 }
 
-export function SettingNetworkEthernet(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['SettingNetworkEthernet'](arg1, arg2, arg3);
+export function SetSocketReconnectPolicy(arg1) {
+  return window['go']['workspace']['WorkspaceService']['SetSocketReconnectPolicy'](arg1);
 }
 
 export function ShowInExplorer(arg1) {
   return window['go']['workspace']['WorkspaceService']['ShowInExplorer'](arg1);
 }
 
-export function UploadConfig(arg1) {
-  return window['go']['workspace']['WorkspaceService']['UploadConfig'](arg1);
+export function SimulateConfigData(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['SimulateConfigData'](arg1, arg2);
+}
+
+export function SimulateConfigFile(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['SimulateConfigFile'](arg1, arg2);
 }
 
-export function UploadConfigEthernet(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['UploadConfigEthernet'](arg1, arg2, arg3);
+export function SimulateScript(arg1, arg2) {
+  return window['go']['workspace']['WorkspaceService']['SimulateScript'](arg1, arg2);
 }
 
-export function WriteMacAddress(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['WriteMacAddress'](arg1, arg2, arg3);
+export function ValidateConfig(arg1) {
+  return window['go']['workspace']['WorkspaceService']['ValidateConfig'](arg1);
 }
 
-export function WriteSerialNumber(arg1, arg2, arg3) {
-  return window['go']['workspace']['WorkspaceService']['WriteSerialNumber'](arg1, arg2, arg3);
+export function ValidateConfigData(arg1) {
+  return window['go']['workspace']['WorkspaceService']['ValidateConfigData'](arg1);
 }
@@ -5,6 +5,7 @@ go 1.23
 require (
 	github.com/wailsapp/wails/v2 v2.10.1
 	go.bug.st/serial.v1 v0.0.0-20191202182710-24a6610f0541
+	golang.org/x/sys v0.30.0
 )
 
 require (
@@ -33,7 +34,6 @@ require (
 	github.com/wailsapp/mimetype v1.4.1 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
-	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
 
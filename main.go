@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"myproject/backend/auth"
-	"myproject/backend/control"
+	"myproject/backend/capture"
+	"myproject/backend/device"
+	"myproject/backend/discovery"
+	"myproject/backend/events"
+	"myproject/backend/transport"
 	"myproject/backend/user"
 	"myproject/backend/workspace"
 
@@ -19,9 +24,19 @@ func main() {
 	// Create an instance of the app structure
 	app := NewApp()
 	authService := auth.NewAuthService()
-	controlService := control.NewControlService(authService)
+	serial := auth.Transport(authService)
+	socketManager := transport.NewSocketManager()
 	userService := user.NewUserService(authService)
-	workspaceService := workspace.NewWorkspaceService(authService)
+	deviceService := device.NewDeviceService(serial, socketManager)
+	workspaceService := workspace.NewWorkspaceService(deviceService, socketManager)
+	eventService := events.NewEventService(serial, socketManager)
+	discoveryService := discovery.NewDiscoveryService()
+
+	// Ghi lưu lượng giao thức của cả cổng COM và socket vào cùng một file capture
+	recorder := transport.NewRecorder()
+	serial.SetRecorder(recorder)
+	socketManager.SetRecorder(recorder)
+	captureService := capture.NewCaptureService(recorder, serial, socketManager)
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -35,13 +50,26 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
-		OnStartup:        app.startup,
+		OnStartup: func(ctx context.Context) {
+			app.startup(ctx)
+			serial.SetContext(ctx)
+			socketManager.SetContext(ctx)
+			workspaceService.SetContext(ctx)
+			deviceService.SetContext(ctx)
+			discoveryService.SetContext(ctx)
+			captureService.SetContext(ctx)
+			eventService.Startup(ctx)
+		},
+		OnShutdown: eventService.Shutdown,
 		Bind: []interface{}{
 			app,
 			userService,
 			workspaceService,
 			authService,
-			controlService,
+			deviceService,
+			eventService,
+			discoveryService,
+			captureService,
 		},
 	})
 